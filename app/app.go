@@ -5,12 +5,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"github.com/robfig/cron"
+	"github.com/shop_management/config"
+	"github.com/shop_management/service/quotation_service"
+	"github.com/shop_management/service/user_service"
 	"github.com/shop_management/util"
 	"github.com/shop_management/vars"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
 	"log"
-	"runtime"
+	"strconv"
 	"time"
 )
 
@@ -18,6 +22,7 @@ var SMApp = NewApp()
 
 type App struct {
 	GinEngine *gin.Engine
+	DB        *gorm.DB
 }
 
 func NewApp() *App {
@@ -26,44 +31,62 @@ func NewApp() *App {
 
 func (a *App) Run() {
 	var err error
+	// 配置, 不正确时直接退出
+	cfg := config.Get()
+	util.SetTokenSecret(cfg.Token.Secret)
+	util.SetEncryptKey(cfg.Token.EncryptKey)
+	util.SetPasswordHashConfig(&cfg.Password)
 	// redis服务
 	vars.RedisClient = redis.NewClient(&redis.Options{
-		Addr:     "120.24.169.86:4399",
-		Password: "ljg098098",
-		PoolSize: runtime.NumCPU() * 10,
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+		PoolSize: cfg.Redis.PoolSize,
 	})
 	// 日志
-	initLogger()
+	initLogger(&cfg.Log)
 	// 时间
-	location, _ := time.LoadLocation("Asia/Shanghai")
+	location, _ := time.LoadLocation(cfg.Server.TimeZone)
 	time.Local = location
+	// 数据库连接池
+	a.DB, err = util.OpenDB(&cfg.Mysql)
+	if err != nil {
+		log.Fatalf("open database failed, err:%v", err)
+	}
 	// gin
+	if cfg.IsProd() {
+		gin.SetMode(gin.ReleaseMode)
+	}
 	a.GinEngine = gin.Default()
 	// 定时任务
-	initCron()
-	initInterceptor(a.GinEngine)
+	initCron(a.DB)
+	initInterceptor(a.GinEngine, a.DB)
 	initRouter(a.GinEngine)
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	a.GinEngine.Use(cors.New(config))
-	err = a.GinEngine.Run(":8080")
+	err = a.GinEngine.Run(":" + strconv.Itoa(cfg.Server.Port))
 	if err != nil {
 		log.Fatalf("running gin server failed, err:%v", err)
 	}
 
 }
 
-func initLogger() {
+func initLogger(logConfig *config.LogConfig) {
 	logger, err := zap.NewProduction()
 	if err != nil {
 		log.Fatal(err)
 	}
+	level, err := zap.ParseAtomicLevel(logConfig.Level)
+	if err != nil {
+		log.Fatal(err)
+	}
 	customTimeEncoder := func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 		enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
 	}
 	cfg := zap.Config{
 		Encoding: "json",
-		Level:    zap.NewAtomicLevelAt(zap.InfoLevel),
+		Level:    level,
 		EncoderConfig: zapcore.EncoderConfig{
 			LevelKey:       "level",
 			TimeKey:        "time",
@@ -74,8 +97,8 @@ func initLogger() {
 			EncodeDuration: zapcore.SecondsDurationEncoder,
 			EncodeCaller:   zapcore.ShortCallerEncoder,
 		},
-		OutputPaths:      []string{"log/zap_info.txt"},
-		ErrorOutputPaths: []string{"log/zap_error.txt"},
+		OutputPaths:      []string{logConfig.InfoPath},
+		ErrorOutputPaths: []string{logConfig.ErrorPath},
 	}
 	logger, err = cfg.Build()
 	if err != nil {
@@ -84,20 +107,27 @@ func initLogger() {
 	vars.Log = logger.Sugar()
 }
 
-func initCron() {
+func initCron(db *gorm.DB) {
 	c := cron.New()
 	c.AddFunc("0 * * * *", func() {
-		db, err := util.GetDB()
+		err := quotation_service.NewQuotationServiceImpl().ExpireOverdue(newCronContext(db))
 		if err != nil {
-			return
+			vars.Log.Errorf("cron expire quotation error:%v", err)
+		}
+	})
+	// 每天凌晨3点清除超过保留期的停用账号
+	c.AddFunc("0 0 3 * * *", func() {
+		err := user_service.NewUserDeactivationServiceImpl().PurgeExpired(newCronContext(db))
+		if err != nil {
+			vars.Log.Errorf("cron purge deactivated user error:%v", err)
 		}
-		defer func() {
-			sqlDB, err := db.DB()
-			if err == nil && sqlDB != nil {
-				_ = sqlDB.Close()
-			}
-		}()
-
 	})
 	c.Start()
 }
+
+// newCronContext 定时任务没有请求, 构造只携带连接池的上下文
+func newCronContext(db *gorm.DB) *gin.Context {
+	ctx := &gin.Context{}
+	ctx.Set(vars.DbMetadataName, db)
+	return ctx
+}
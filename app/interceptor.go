@@ -1,18 +1,26 @@
 package app
 
 import (
+	"bytes"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shop_management/dto/api_key_dto"
+	"github.com/shop_management/dto/user_dto"
 	"github.com/shop_management/redis/user_redis"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/admin_service"
+	"github.com/shop_management/service/api_key_service"
+	"github.com/shop_management/service/auth_service"
 	"github.com/shop_management/sm_error"
 	"github.com/shop_management/sm_error/error_code"
-	"github.com/shop_management/util"
 	"github.com/shop_management/vars"
 	"gorm.io/gorm"
+	"io"
 	"net/http"
 	"runtime/debug"
 )
 
-func initInterceptor(engine *gin.Engine) {
+func initInterceptor(engine *gin.Engine, db *gorm.DB) {
 	// panic
 	engine.Use(func(context *gin.Context) {
 		defer func() {
@@ -24,25 +32,19 @@ func initInterceptor(engine *gin.Engine) {
 		}()
 		context.Next()
 	})
-	// db拦截器
+	// 请求id, 沿用调用方传入的 X-Request-Id, 审计记录据此关联同一请求中的多次变更
 	engine.Use(func(context *gin.Context) {
-		db, err := util.GetDB()
-		if err != nil {
-			context.Abort()
-		}
-		if context.Request.URL.Path == "" {
-		} else {
-			defer func() {
-				if db, ok := context.Get(vars.DbMetadataName); ok {
-					if db, ok := db.(*gorm.DB); ok {
-						sqlDB, err := db.DB()
-						if err == nil && sqlDB != nil {
-							_ = sqlDB.Close()
-						}
-					}
-				}
-			}()
+		requestId := context.GetHeader("X-Request-Id")
+		if requestId == "" || len(requestId) > 64 {
+			requestId = uuid.NewString()
 		}
+		context.Set(vars.RequestIdMetadataName, requestId)
+		context.Set(vars.ClientIpMetadataName, context.ClientIP())
+		context.Header("X-Request-Id", requestId)
+		context.Next()
+	})
+	// db拦截器, 所有请求共用启动时创建的连接池
+	engine.Use(func(context *gin.Context) {
 		context.Set(vars.DbMetadataName, db)
 		context.Next()
 	})
@@ -62,17 +64,45 @@ func initInterceptor(engine *gin.Engine) {
 		c.Next()
 	})
 	// token 或 secret校验
+	authService := auth_service.NewAuthServiceImpl()
+	apiKeyService := api_key_service.NewApiKeyServiceImpl()
 	engine.Use(func(context *gin.Context) {
 		if context.Request.URL.Path == "/v1/api/user/login" ||
-			context.Request.URL.Path == "/v1/api/user/modify_password" ||
+			context.Request.URL.Path == "/v1/api/user/login_verify" ||
+			context.Request.URL.Path == "/v1/api/user/oidc/auth_url" ||
+			context.Request.URL.Path == "/v1/api/user/oidc/login" ||
+			context.Request.URL.Path == "/v1/api/user/send_reset_code" ||
+			context.Request.URL.Path == "/v1/api/user/reset_password" ||
+			context.Request.URL.Path == "/v1/api/user/reactivate" ||
+			context.Request.URL.Path == "/v1/api/invitation/detail" ||
 			context.Request.URL.Path == "/v1/api/user/register" ||
 			context.Request.URL.Path == "/v1/api/sms_record/receive_report" {
 			context.Next()
 			return
 		}
-		token, _ := context.Cookie("token")
-		userId, _ := context.Cookie("user_id")
-		err := user_redis.CheckToken(context, token, userId)
+		var userId string
+		var err error
+		if context.GetHeader(api_key_dto.HeaderAccessKey) != "" {
+			userId, err = authenticateApiKey(context, apiKeyService)
+		} else {
+			token, _ := context.Cookie("token")
+			userId, _ = context.Cookie("user_id")
+			var impersonatorId string
+			impersonatorId, err = user_redis.CheckToken(context, token, userId)
+			if err == nil {
+				context.Set(vars.UserIdMetadataName, userId)
+			}
+			if impersonatorId != "" {
+				context.Set(vars.ImpersonatorIdMetadataName, impersonatorId)
+			}
+		}
+		if err != nil {
+			context.JSON(http.StatusOK, err)
+			context.Abort()
+			return
+		}
+		// 解析所属团队, 后续的数据库操作按团队隔离
+		_, err = authService.GetTeamRole(context, userId)
 		if err != nil {
 			context.JSON(http.StatusOK, err)
 			context.Abort()
@@ -81,3 +111,85 @@ func initInterceptor(engine *gin.Engine) {
 		context.Next()
 	})
 }
+
+// authenticateApiKey 校验 API Key 签名请求, 通过后将 Key 所属用户写入上下文代替 cookie
+func authenticateApiKey(context *gin.Context, apiKeyService service.ApiKeyService) (string, error) {
+	body, err := io.ReadAll(context.Request.Body)
+	if err != nil {
+		return "", sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	context.Request.Body = io.NopCloser(bytes.NewReader(body))
+	apiKey, err := apiKeyService.Authenticate(context, &api_key_dto.SignedRequest{
+		AccessKey: context.GetHeader(api_key_dto.HeaderAccessKey),
+		Secret:    context.GetHeader(api_key_dto.HeaderSecret),
+		Timestamp: context.GetHeader(api_key_dto.HeaderTimestamp),
+		Nonce:     context.GetHeader(api_key_dto.HeaderNonce),
+		Signature: context.GetHeader(api_key_dto.HeaderSignature),
+		Method:    context.Request.Method,
+		Path:      context.Request.URL.RequestURI(),
+		Body:      body,
+	})
+	if err != nil {
+		return "", err
+	}
+	context.Set(vars.UserIdMetadataName, apiKey.UserID)
+	context.Set(vars.ApiKeyMetadataName, apiKey)
+	return apiKey.UserID, nil
+}
+
+// cookieOnly 账号与密钥管理类接口不允许通过 API Key 调用, 也不允许平台管理员模拟登录后调用
+func cookieOnly() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if _, ok := context.Get(vars.ApiKeyMetadataName); ok {
+			context.JSON(http.StatusOK, sm_error.NewHttpError(error_code.ApiKeyNotAllowed))
+			context.Abort()
+			return
+		}
+		if context.GetString(vars.ImpersonatorIdMetadataName) != "" {
+			context.JSON(http.StatusOK, sm_error.NewHttpError(error_code.ImpersonationNotAllowed))
+			context.Abort()
+			return
+		}
+		context.Next()
+	}
+}
+
+// platformAdmin 平台管理接口, 需在 cookieOnly 之后执行
+func platformAdmin() gin.HandlerFunc {
+	adminService := admin_service.NewAdminServiceImpl()
+	return func(context *gin.Context) {
+		err := adminService.CheckPlatformAdmin(context)
+		if err != nil {
+			context.JSON(http.StatusOK, err)
+			context.Abort()
+			return
+		}
+		context.Next()
+	}
+}
+
+// authorize 路由分组的角色权限校验, 在 token 校验之后执行. GET 请求按读权限校验, 其他请求按写权限校验
+func authorize(resource string) gin.HandlerFunc {
+	return authorizeAction(resource, "")
+}
+
+// authorizeAction 指定操作类型, 用于以 POST 提交但只读取数据的接口
+func authorizeAction(resource string, action string) gin.HandlerFunc {
+	authService := auth_service.NewAuthServiceImpl()
+	return func(context *gin.Context) {
+		need := action
+		if need == "" {
+			need = user_dto.ActionWrite
+			if context.Request.Method == http.MethodGet {
+				need = user_dto.ActionRead
+			}
+		}
+		err := authService.CheckPermission(context, resource, need)
+		if err != nil {
+			context.JSON(http.StatusOK, err)
+			context.Abort()
+			return
+		}
+		context.Next()
+	}
+}
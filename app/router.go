@@ -2,9 +2,22 @@ package app
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/server/admin_server"
+	"github.com/shop_management/server/api_key_server"
+	"github.com/shop_management/server/audit_server"
+	"github.com/shop_management/server/currency_server"
 	"github.com/shop_management/server/file_server"
+	"github.com/shop_management/server/fulfillment_server"
+	"github.com/shop_management/server/order_server"
+	"github.com/shop_management/server/pos_server"
 	"github.com/shop_management/server/product_server"
+	"github.com/shop_management/server/promotion_server"
+	"github.com/shop_management/server/quotation_server"
+	"github.com/shop_management/server/report_server"
+	"github.com/shop_management/server/tax_server"
 	"github.com/shop_management/server/user_server"
+	"github.com/shop_management/server/wallet_server"
 	"net/http"
 )
 
@@ -26,31 +39,218 @@ func initRouter(engine *gin.Engine) {
 	engine.Static("/static/", "./static")
 	initUserRouter(engine)
 	initUserTeam(engine)
+	initUserInvitation(engine)
+	initApiKeyRouter(engine)
+	initTwoFactorRouter(engine)
+	initAuditRouter(engine)
+	initAdminRouter(engine)
 	initFileApiRouter(engine)
 	initProductApiRouter(engine)
+	initOrderApiRouter(engine)
+	initFulfillmentApiRouter(engine)
+	initPromotionApiRouter(engine)
+	initPosApiRouter(engine)
+	initQuotationApiRouter(engine)
+	initTaxApiRouter(engine)
+	initCurrencyApiRouter(engine)
+	initReportApiRouter(engine)
+	initWalletApiRouter(engine)
 }
 
 func initUserRouter(engine *gin.Engine) {
 	userServer := user_server.NewUserServer()
 	engine.POST("/v1/api/user/login", proxyFunc(userServer.Login))
+	engine.POST("/v1/api/user/login_verify", proxyFunc(userServer.LoginVerify))
+	engine.GET("/v1/api/user/oidc/auth_url", proxyFunc(userServer.OidcAuthURL))
+	engine.POST("/v1/api/user/oidc/login", proxyFunc(userServer.OidcLogin))
+	engine.GET("/v1/api/user/oidc/link_url", cookieOnly(), proxyFunc(userServer.OidcLinkURL))
+	engine.POST("/v1/api/user/oidc/link", cookieOnly(), proxyFunc(userServer.OidcLink))
 	engine.POST("/v1/api/user/register", proxyFunc(userServer.Register))
-	engine.GET("/v1/api/user/profile", proxyFunc(userServer.GetUserProfile))
-
+	engine.GET("/v1/api/user/profile", cookieOnly(), proxyFunc(userServer.GetUserProfile))
+	engine.POST("/v1/api/user/save_profile", cookieOnly(), proxyFunc(userServer.SaveUserProfile))
+	engine.POST("/v1/api/user/upload_avatar", cookieOnly(), proxyFunc(userServer.UploadAvatar))
+	engine.POST("/v1/api/user/modify_password", cookieOnly(), proxyFunc(userServer.ModifyPassword))
+	deactivationServer := user_server.NewUserDeactivationServer()
+	engine.POST("/v1/api/user/deactivate", cookieOnly(), proxyFunc(deactivationServer.Deactivate))
+	engine.POST("/v1/api/user/reactivate", proxyFunc(deactivationServer.ReactivateSelf))
+	engine.POST("/v1/api/user/send_reset_code", proxyFunc(userServer.SendResetCode))
+	engine.POST("/v1/api/user/reset_password", proxyFunc(userServer.ResetPassword))
+	engine.GET("/v1/api/user/session_list", cookieOnly(), proxyFunc(userServer.SessionList))
+	engine.POST("/v1/api/user/revoke_session", cookieOnly(), proxyFunc(userServer.RevokeSession))
+	engine.POST("/v1/api/user/revoke_all_session", cookieOnly(), proxyFunc(userServer.RevokeAllSession))
 }
 
 func initUserTeam(engine *gin.Engine) {
 	userServer := user_server.NewUserTeamServer()
-	engine.GET("/v1/api/user_team/sub_user_list", proxyFunc(userServer.SubUserList))
-	engine.POST("/v1/api/user_team/add_sub_user", proxyFunc(userServer.AddSubUser))
-	engine.POST("/v1/api/user_team/del_sub_user", proxyFunc(userServer.DelSubUser))
+	group := engine.Group("/v1/api/user_team", authorize(user_dto.ResourceTeam))
+	group.GET("/sub_user_list", proxyFunc(userServer.SubUserList))
+	group.POST("/add_sub_user", proxyFunc(userServer.AddSubUser))
+	group.POST("/del_sub_user", proxyFunc(userServer.DelSubUser))
+	group.POST("/assign_role", proxyFunc(userServer.AssignRole))
+	group.POST("/force_logout", proxyFunc(userServer.ForceLogout))
+	group.GET("/lockout_list", proxyFunc(userServer.LockoutList))
+	group.GET("/grant_list", proxyFunc(userServer.GrantList))
+	group.POST("/add_grant", proxyFunc(userServer.AddGrant))
+	group.POST("/del_grant", proxyFunc(userServer.DelGrant))
+	deactivationServer := user_server.NewUserDeactivationServer()
+	group.POST("/deactivate_member", proxyFunc(deactivationServer.DeactivateMember))
+	group.GET("/deactivated_list", proxyFunc(deactivationServer.DeactivatedList))
+	group.POST("/reactivate", proxyFunc(deactivationServer.Reactivate))
+	group.POST("/invite", proxyFunc(userServer.Invite))
+	group.GET("/invitation_list", proxyFunc(userServer.InvitationList))
+}
+
+// initUserInvitation 被邀请人使用, 不校验团队角色
+func initUserInvitation(engine *gin.Engine) {
+	server := user_server.NewUserInvitationServer()
+	engine.GET("/v1/api/invitation/detail", proxyFunc(server.Detail))
+	engine.POST("/v1/api/invitation/accept", cookieOnly(), proxyFunc(server.Accept))
+	engine.POST("/v1/api/invitation/decline", cookieOnly(), proxyFunc(server.Decline))
+}
+
+// initTwoFactorRouter 两步验证只能在登录后自行管理
+func initTwoFactorRouter(engine *gin.Engine) {
+	server := user_server.NewUserTwoFactorServer()
+	group := engine.Group("/v1/api/two_factor", cookieOnly())
+	group.POST("/enroll", proxyFunc(server.Enroll))
+	group.POST("/activate", proxyFunc(server.Activate))
+	group.POST("/disable", proxyFunc(server.Disable))
+	group.POST("/recovery_codes", proxyFunc(server.RegenerateRecoveryCodes))
+}
+
+func initAuditRouter(engine *gin.Engine) {
+	server := audit_server.NewAuditServer()
+	group := engine.Group("/v1/api/audit", authorize(user_dto.ResourceAudit))
+	group.GET("/list", proxyFunc(server.List))
+}
+
+// initAdminRouter 平台管理接口, 只允许平台管理员登录后调用, 每次调用都记录审计
+func initAdminRouter(engine *gin.Engine) {
+	server := admin_server.NewAdminServer()
+	group := engine.Group("/v1/api/admin", cookieOnly(), platformAdmin())
+	group.GET("/user_list", proxyFunc(server.UserList))
+	group.GET("/user_detail", proxyFunc(server.UserDetail))
+	group.GET("/user_activity", proxyFunc(server.UserActivity))
+	group.POST("/disable_user", proxyFunc(server.DisableUser))
+	group.POST("/enable_user", proxyFunc(server.EnableUser))
+	group.POST("/impersonate", proxyFunc(server.Impersonate))
+	group.GET("/audit_list", proxyFunc(server.AuditList))
+}
+
+// initApiKeyRouter API Key 只能在登录后管理, 不能用 API Key 自身创建或轮换
+func initApiKeyRouter(engine *gin.Engine) {
+	server := api_key_server.NewApiKeyServer()
+	group := engine.Group("/v1/api/api_key", cookieOnly())
+	group.POST("/create", proxyFunc(server.Create))
+	group.GET("/list", proxyFunc(server.List))
+	group.POST("/rotate", proxyFunc(server.Rotate))
+	group.POST("/revoke", proxyFunc(server.Revoke))
 }
 
 func initFileApiRouter(router *gin.Engine) {
 	server := file_server.NewFileServer()
-	router.POST("/v1/api/upload_file", server.Upload)
+	router.POST("/v1/api/upload_file", authorize(user_dto.ResourceFile), server.Upload)
 }
 
 func initProductApiRouter(router *gin.Engine) {
 	server := product_server.NewProductServer()
-	router.POST("/v1/api/product/add", proxyFunc(server.Add))
+	group := router.Group("/v1/api/product", authorize(user_dto.ResourceProduct))
+	group.POST("/add", proxyFunc(server.Add))
+	group.GET("/list", proxyFunc(server.List))
+}
+
+func initOrderApiRouter(router *gin.Engine) {
+	server := order_server.NewOrderServer()
+	group := router.Group("/v1/api/order", authorize(user_dto.ResourceOrder))
+	group.POST("/add", proxyFunc(server.Add))
+	group.POST("/confirm", proxyFunc(server.Confirm))
+	group.GET("/detail", proxyFunc(server.Detail))
+	group.GET("/list", proxyFunc(server.List))
+	group.GET("/invoice", proxyFunc(server.Invoice))
+}
+
+func initFulfillmentApiRouter(router *gin.Engine) {
+	server := fulfillment_server.NewFulfillmentServer()
+	group := router.Group("/v1/api/fulfillment", authorize(user_dto.ResourceFulfillment))
+	group.POST("/generate_pick_list", proxyFunc(server.GeneratePickList))
+	group.GET("/pick_task_list", proxyFunc(server.PickTaskList))
+	group.POST("/claim_pick_task", proxyFunc(server.ClaimPickTask))
+	group.POST("/finish_pick_task", proxyFunc(server.FinishPickTask))
+	group.POST("/pack", proxyFunc(server.Pack))
+	group.POST("/ship", proxyFunc(server.Ship))
+	group.GET("/timeline", proxyFunc(server.Timeline))
+}
+
+func initPromotionApiRouter(router *gin.Engine) {
+	server := promotion_server.NewPromotionServer()
+	// 试算只读取促销规则, 销售角色也需要使用
+	router.POST("/v1/api/promotion/evaluate", authorizeAction(user_dto.ResourcePromotion, user_dto.ActionRead), proxyFunc(server.Evaluate))
+	group := router.Group("/v1/api/promotion", authorize(user_dto.ResourcePromotion))
+	group.POST("/add", proxyFunc(server.Add))
+	group.GET("/list", proxyFunc(server.List))
+	group.POST("/disable", proxyFunc(server.Disable))
+}
+
+func initPosApiRouter(router *gin.Engine) {
+	server := pos_server.NewPosServer()
+	group := router.Group("/v1/api/pos", authorize(user_dto.ResourcePos))
+	group.POST("/open_shift", proxyFunc(server.OpenShift))
+	group.GET("/current_shift", proxyFunc(server.CurrentShift))
+	group.POST("/close_shift", proxyFunc(server.CloseShift))
+	group.POST("/checkout", proxyFunc(server.Checkout))
+}
+
+func initQuotationApiRouter(router *gin.Engine) {
+	server := quotation_server.NewQuotationServer()
+	group := router.Group("/v1/api/quotation", authorize(user_dto.ResourceQuotation))
+	group.POST("/add", proxyFunc(server.Add))
+	group.POST("/revise", proxyFunc(server.Revise))
+	group.GET("/detail", proxyFunc(server.Detail))
+	group.GET("/list", proxyFunc(server.List))
+	group.GET("/versions", proxyFunc(server.Versions))
+	group.POST("/convert", proxyFunc(server.Convert))
+	group.POST("/cancel", proxyFunc(server.Cancel))
+}
+
+func initTaxApiRouter(router *gin.Engine) {
+	server := tax_server.NewTaxServer()
+	group := router.Group("/v1/api/tax", authorize(user_dto.ResourceTax))
+	group.POST("/save_rule", proxyFunc(server.SaveRule))
+	group.GET("/rule_list", proxyFunc(server.RuleList))
+	group.POST("/delete_rule", proxyFunc(server.DeleteRule))
+	group.GET("/setting", proxyFunc(server.Setting))
+	group.POST("/save_setting", proxyFunc(server.SaveSetting))
+	group.POST("/add_exemption", proxyFunc(server.AddExemption))
+	group.GET("/exemption_list", proxyFunc(server.ExemptionList))
+	group.POST("/delete_exemption", proxyFunc(server.DeleteExemption))
+}
+
+func initCurrencyApiRouter(router *gin.Engine) {
+	server := currency_server.NewCurrencyServer()
+	group := router.Group("/v1/api/currency", authorize(user_dto.ResourceCurrency))
+	group.GET("/base_currency", proxyFunc(server.BaseCurrency))
+	group.POST("/save_base_currency", proxyFunc(server.SaveBaseCurrency))
+	group.POST("/save_rate", proxyFunc(server.SaveRate))
+	group.POST("/import_rates", proxyFunc(server.ImportRates))
+	group.GET("/rate_list", proxyFunc(server.RateList))
+}
+
+func initReportApiRouter(router *gin.Engine) {
+	server := report_server.NewReportServer()
+	group := router.Group("/v1/api/report", authorize(user_dto.ResourceReport))
+	group.GET("/sales", proxyFunc(server.Sales))
+	group.GET("/inventory_valuation", proxyFunc(server.InventoryValuation))
+}
+
+func initWalletApiRouter(router *gin.Engine) {
+	server := wallet_server.NewWalletServer()
+	group := router.Group("/v1/api/wallet", authorize(user_dto.ResourceWallet))
+	group.GET("/detail", proxyFunc(server.Detail))
+	group.GET("/entry_list", proxyFunc(server.EntryList))
+	group.POST("/top_up", authorizeAction(user_dto.ResourceWallet, user_dto.ActionCredit), proxyFunc(server.TopUp))
+	group.POST("/deduct", proxyFunc(server.Deduct))
+	group.POST("/refund", authorizeAction(user_dto.ResourceWallet, user_dto.ActionCredit), proxyFunc(server.Refund))
+	group.POST("/reconcile", proxyFunc(server.Reconcile))
+	group.GET("/stat", proxyFunc(server.GetStat))
+	group.GET("/trend", proxyFunc(server.GetTrend))
 }
@@ -0,0 +1,238 @@
+package config
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sync"
+	"time"
+)
+
+const (
+	ProfileDev  = "dev"
+	ProfileTest = "test"
+	ProfileProd = "prod"
+)
+
+// Config 启动时按以下顺序加载, 后者覆盖前者: 代码中的默认值, 配置文件, 环境变量.
+// 配置文件由 SM_CONFIG_FILE 指定, 未指定时为工作目录或程序所在目录下的 conf/<profile>.yaml, profile 取自 SM_PROFILE, 默认 dev.
+// 标记为 secret 的字段可以写成 "env:变量名" 或 "file:文件路径", 由对应的 SecretProvider 读取
+type Config struct {
+	Profile  string         `yaml:"-"`
+	Server   ServerConfig   `yaml:"server"`
+	Log      LogConfig      `yaml:"log"`
+	Mysql    MysqlConfig    `yaml:"mysql"`
+	Redis    RedisConfig    `yaml:"redis"`
+	Oss      OssConfig      `yaml:"oss"`
+	Token    TokenConfig    `yaml:"token"`
+	Password PasswordConfig `yaml:"password"`
+	Message  MessageConfig  `yaml:"message"`
+	User     UserConfig     `yaml:"user"`
+	Oidc     OidcConfig     `yaml:"oidc"`
+}
+
+type ServerConfig struct {
+	Port     int    `yaml:"port" env:"SM_SERVER_PORT"`
+	TimeZone string `yaml:"time_zone" env:"SM_TIME_ZONE"`
+}
+
+type LogConfig struct {
+	// Level debug, info, warn, error
+	Level     string `yaml:"level" env:"SM_LOG_LEVEL"`
+	InfoPath  string `yaml:"info_path" env:"SM_LOG_INFO_PATH"`
+	ErrorPath string `yaml:"error_path" env:"SM_LOG_ERROR_PATH"`
+}
+
+type MysqlConfig struct {
+	Host     string `yaml:"host" env:"SM_DB_HOST"`
+	Port     int    `yaml:"port" env:"SM_DB_PORT"`
+	User     string `yaml:"user" env:"SM_DB_USER"`
+	Password string `yaml:"password" env:"SM_DB_PASSWORD" secret:"true"`
+	Database string `yaml:"database" env:"SM_DB_NAME"`
+	Params   string `yaml:"params" env:"SM_DB_PARAMS"`
+	// 连接池, 启动时创建一次, 所有请求和定时任务共用. 时长写作 "1h", "10m", 0 表示不限制
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"SM_DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"SM_DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"SM_DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"SM_DB_CONN_MAX_IDLE_TIME"`
+	// Debug 打印每条 SQL, 只建议在开发环境开启
+	Debug bool `yaml:"debug" env:"SM_DB_DEBUG"`
+}
+
+func (m *MysqlConfig) DSN() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s", m.User, m.Password, m.Host, m.Port, m.Database, m.Params)
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr" env:"SM_REDIS_ADDR"`
+	Password string `yaml:"password" env:"SM_REDIS_PASSWORD" secret:"true"`
+	DB       int    `yaml:"db" env:"SM_REDIS_DB"`
+	PoolSize int    `yaml:"pool_size" env:"SM_REDIS_POOL_SIZE"`
+}
+
+type OssConfig struct {
+	Endpoint        string `yaml:"endpoint" env:"SM_OSS_ENDPOINT"`
+	Bucket          string `yaml:"bucket" env:"SM_OSS_BUCKET"`
+	AccessKeyId     string `yaml:"access_key_id" env:"OSS_ACCESS_KEY_ID" secret:"true"`
+	AccessKeySecret string `yaml:"access_key_secret" env:"OSS_ACCESS_KEY_SECRET" secret:"true"`
+}
+
+// PublicURL 上传后对象的访问地址
+func (o *OssConfig) PublicURL(object string) string {
+	return "https://" + o.Bucket + "." + o.Endpoint + "/" + object
+}
+
+type TokenConfig struct {
+	// Secret 令牌签名密钥, 所有环境必须配置, 更换后已签发的令牌失效
+	Secret string `yaml:"secret" env:"SM_TOKEN_SECRET" secret:"true"`
+	// EncryptKey 加密需要还原的敏感数据(如 TOTP 密钥)的密钥, 与签名密钥分开配置, 更换后已加密的数据无法解密
+	EncryptKey string `yaml:"encrypt_key" env:"SM_TOKEN_ENCRYPT_KEY" secret:"true"`
+}
+
+// PasswordConfig argon2id 参数, 调整后旧哈希在下次登录时按新参数重新生成
+type PasswordConfig struct {
+	Argon2Time    uint32 `yaml:"argon2_time" env:"SM_PASSWORD_ARGON2_TIME"`
+	Argon2Memory  uint32 `yaml:"argon2_memory" env:"SM_PASSWORD_ARGON2_MEMORY"`
+	Argon2Threads uint8  `yaml:"argon2_threads" env:"SM_PASSWORD_ARGON2_THREADS"`
+}
+
+type MessageConfig struct {
+	// Sender 短信通道名称, EmailSender 邮件通道名称, 未注册的名称回退到日志通道
+	Sender      string `yaml:"sender" env:"SM_MESSAGE_SENDER"`
+	EmailSender string `yaml:"email_sender" env:"SM_MESSAGE_EMAIL_SENDER"`
+	LogPath     string `yaml:"log_path" env:"SM_MESSAGE_LOG_PATH"`
+}
+
+type UserConfig struct {
+	// RetentionDays 停用账号的保留天数
+	RetentionDays int `yaml:"retention_days" env:"SM_USER_RETENTION_DAYS"`
+}
+
+// OidcConfig Issuer 为空时不启用单点登录, 公开客户端可不填 ClientSecret
+type OidcConfig struct {
+	Issuer       string   `yaml:"issuer" env:"SM_OIDC_ISSUER"`
+	ClientId     string   `yaml:"client_id" env:"SM_OIDC_CLIENT_ID"`
+	ClientSecret string   `yaml:"client_secret" env:"SM_OIDC_CLIENT_SECRET" secret:"true"`
+	RedirectUrl  string   `yaml:"redirect_url" env:"SM_OIDC_REDIRECT_URL"`
+	Scopes       []string `yaml:"scopes" env:"SM_OIDC_SCOPES"`
+}
+
+func (c *Config) IsProd() bool {
+	return c.Profile == ProfileProd
+}
+
+var (
+	current  *Config
+	loadOnce sync.Once
+)
+
+// Get 第一次调用时加载并校验配置, 配置不正确时直接退出
+func Get() *Config {
+	loadOnce.Do(func() {
+		cfg, err := Load()
+		if err != nil {
+			log.Fatalf("load config failed, err:%v", err)
+		}
+		current = cfg
+	})
+	return current
+}
+
+func Load() (*Config, error) {
+	profile := os.Getenv("SM_PROFILE")
+	if profile == "" {
+		profile = ProfileDev
+	}
+	path := os.Getenv("SM_CONFIG_FILE")
+	if path == "" {
+		path = defaultConfigFile(profile)
+	}
+	return LoadFile(profile, path)
+}
+
+// defaultConfigFile 工作目录下没有配置文件时使用程序所在目录下的
+func defaultConfigFile(profile string) string {
+	path := filepath.Join("conf", profile+".yaml")
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(filepath.Dir(executable), path)
+}
+
+func LoadFile(profile string, path string) (*Config, error) {
+	cfg := defaultConfig()
+	cfg.Profile = profile
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file %s: %w", path, err)
+	}
+	err = yaml.Unmarshal(content, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	err = applyEnv(reflect.ValueOf(cfg).Elem())
+	if err != nil {
+		return nil, err
+	}
+	err = resolveSecrets(reflect.ValueOf(cfg).Elem())
+	if err != nil {
+		return nil, err
+	}
+	err = cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:     8080,
+			TimeZone: "Asia/Shanghai",
+		},
+		Log: LogConfig{
+			Level:     "info",
+			InfoPath:  "log/zap_info.txt",
+			ErrorPath: "log/zap_error.txt",
+		},
+		Mysql: MysqlConfig{
+			Port:            3306,
+			Params:          "charset=utf8mb4&parseTime=True&loc=Local",
+			MaxOpenConns:    50,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: time.Hour,
+			ConnMaxIdleTime: 10 * time.Minute,
+		},
+		Redis: RedisConfig{
+			PoolSize: runtime.NumCPU() * 10,
+		},
+		Oss: OssConfig{
+			Endpoint: "oss-cn-shenzhen.aliyuncs.com",
+		},
+		Password: PasswordConfig{
+			Argon2Time:    3,
+			Argon2Memory:  64 * 1024,
+			Argon2Threads: 2,
+		},
+		Message: MessageConfig{
+			Sender:      "log",
+			EmailSender: "log",
+			LogPath:     "log/message.txt",
+		},
+		User: UserConfig{
+			RetentionDays: 30,
+		},
+		Oidc: OidcConfig{
+			Scopes: []string{"email", "phone", "profile"},
+		},
+	}
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// minimalYaml 通过校验所需的最少配置, 其余取默认值
+const minimalYaml = `
+mysql:
+  host: 127.0.0.1
+  user: root
+  database: shop
+redis:
+  addr: 127.0.0.1:6379
+oss:
+  bucket: bucket
+token:
+  secret: token
+  encrypt_key: key
+`
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProfiles(t *testing.T) {
+	t.Setenv("SM_DB_PASSWORD", "db-secret")
+	t.Setenv("SM_REDIS_PASSWORD", "redis-secret")
+	t.Setenv("SM_TOKEN_SECRET", "token-secret")
+	t.Setenv("SM_TOKEN_ENCRYPT_KEY", "encrypt-secret")
+	tests := []struct {
+		profile      string
+		wantDatabase string
+	}{
+		{profile: ProfileDev, wantDatabase: "shop_management_dev"},
+		{profile: ProfileTest, wantDatabase: "shop_management_test"},
+	}
+	for _, tt := range tests {
+		cfg, err := LoadFile(tt.profile, filepath.Join("..", "conf", tt.profile+".yaml"))
+		if err != nil {
+			t.Errorf("%s: %v", tt.profile, err)
+			continue
+		}
+		if cfg.Profile != tt.profile || cfg.Mysql.Database != tt.wantDatabase {
+			t.Errorf("%s: profile %q database %q", tt.profile, cfg.Profile, cfg.Mysql.Database)
+		}
+	}
+}
+
+func TestLoadProdRequiresSecretFiles(t *testing.T) {
+	_, err := LoadFile(ProfileProd, filepath.Join("..", "conf", "prod.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "secret file:") {
+		t.Errorf("error = %v, want missing secret file", err)
+	}
+}
+
+func TestLoadSelectsProfile(t *testing.T) {
+	path := writeConfigFile(t, minimalYaml)
+	t.Setenv("SM_PROFILE", ProfileTest)
+	t.Setenv("SM_CONFIG_FILE", path)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Profile != ProfileTest {
+		t.Errorf("profile = %q", cfg.Profile)
+	}
+	// 未写在配置文件中的字段取默认值
+	if cfg.Server.Port != 8080 || cfg.Mysql.Port != 3306 || cfg.Mysql.ConnMaxLifetime != time.Hour || cfg.User.RetentionDays != 30 {
+		t.Errorf("defaults not applied: %+v", cfg)
+	}
+
+	t.Setenv("SM_CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, err = Load(); err == nil || !strings.Contains(err.Error(), "read config file") {
+		t.Errorf("error = %v, want read error", err)
+	}
+}
+
+func TestLoadEnvOverrides(t *testing.T) {
+	path := writeConfigFile(t, minimalYaml+`
+server:
+  port: 9000
+oidc:
+  scopes: [email]
+`)
+	tests := []struct {
+		env     string
+		value   string
+		get     func(cfg *Config) interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{env: "SM_SERVER_PORT", value: "9100", get: func(c *Config) interface{} { return c.Server.Port }, want: 9100},
+		{env: "SM_SERVER_PORT", value: " ", get: func(c *Config) interface{} { return c.Server.Port }, want: 9000},
+		{env: "SM_DB_HOST", value: "db.internal", get: func(c *Config) interface{} { return c.Mysql.Host }, want: "db.internal"},
+		{env: "SM_DB_DEBUG", value: "true", get: func(c *Config) interface{} { return c.Mysql.Debug }, want: true},
+		{env: "SM_DB_CONN_MAX_LIFETIME", value: "30m", get: func(c *Config) interface{} { return c.Mysql.ConnMaxLifetime }, want: 30 * time.Minute},
+		{env: "SM_PASSWORD_ARGON2_THREADS", value: "4", get: func(c *Config) interface{} { return c.Password.Argon2Threads }, want: uint8(4)},
+		{env: "SM_OIDC_SCOPES", value: "email, phone profile", get: func(c *Config) interface{} { return c.Oidc.Scopes },
+			want: []string{"email", "phone", "profile"}},
+		{env: "SM_SERVER_PORT", value: "abc", wantErr: "env SM_SERVER_PORT"},
+		{env: "SM_DB_CONN_MAX_LIFETIME", value: "3600", wantErr: "env SM_DB_CONN_MAX_LIFETIME"},
+		{env: "SM_PASSWORD_ARGON2_THREADS", value: "256", wantErr: "env SM_PASSWORD_ARGON2_THREADS"},
+		{env: "SM_DB_DEBUG", value: "yes", wantErr: "env SM_DB_DEBUG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.env, tt.value)
+			cfg, err := LoadFile(ProfileDev, path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.get(cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "db_password")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_SM_SECRET", "from-env")
+	tests := []struct {
+		password string
+		want     string
+		wantErr  string
+	}{
+		{password: "plain", want: "plain"},
+		{password: "env:TEST_SM_SECRET", want: "from-env"},
+		{password: "file:" + secretFile, want: "from-file"},
+		// 未注册的前缀按明文处理, 密码中可以包含冒号
+		{password: "p@ss:word", want: "p@ss:word"},
+		{password: "env:TEST_SM_SECRET_MISSING", wantErr: "secret env:TEST_SM_SECRET_MISSING"},
+		{password: "file:" + secretFile + ".missing", wantErr: "secret file:"},
+	}
+	for _, tt := range tests {
+		path := writeConfigFile(t, strings.Replace(minimalYaml, "redis:\n", "redis:\n  password: \""+tt.password+"\"\n", 1))
+		cfg, err := LoadFile(ProfileDev, path)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want containing %q", tt.password, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.password, err)
+			continue
+		}
+		if cfg.Redis.Password != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.password, cfg.Redis.Password, tt.want)
+		}
+	}
+}
+
+type staticSecretProvider map[string]string
+
+func (s staticSecretProvider) GetSecret(name string) (string, error) {
+	return s[name], nil
+}
+
+func TestRegisterSecretProvider(t *testing.T) {
+	RegisterSecretProvider("test-vault", staticSecretProvider{"db": "from-vault"})
+	got, err := ResolveSecret("test-vault:db")
+	if err != nil || got != "from-vault" {
+		t.Errorf("ResolveSecret = %q, %v", got, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func(profile string) *Config {
+		cfg := defaultConfig()
+		cfg.Profile = profile
+		cfg.Mysql.Host, cfg.Mysql.User, cfg.Mysql.Database = "127.0.0.1", "root", "shop"
+		cfg.Redis.Addr = "127.0.0.1:6379"
+		cfg.Oss.Bucket = "bucket"
+		cfg.Token.Secret, cfg.Token.EncryptKey = "token", "key"
+		return cfg
+	}
+	prod := func() *Config {
+		cfg := valid(ProfileProd)
+		cfg.Mysql.Password, cfg.Redis.Password = "db", "redis"
+		return cfg
+	}
+	tests := []struct {
+		name    string
+		cfg     *Config
+		modify  func(cfg *Config)
+		wantErr []string
+	}{
+		{name: "dev", cfg: valid(ProfileDev)},
+		{name: "prod", cfg: prod()},
+		{name: "unknown profile", cfg: valid("staging"), wantErr: []string{`unknown profile "staging"`}},
+		{name: "port", cfg: valid(ProfileDev), modify: func(c *Config) { c.Server.Port = 70000 }, wantErr: []string{"server.port"}},
+		{name: "time zone", cfg: valid(ProfileDev), modify: func(c *Config) { c.Server.TimeZone = "Mars/Base" }, wantErr: []string{"server.time_zone"}},
+		{name: "log level", cfg: valid(ProfileDev), modify: func(c *Config) { c.Log.Level = "trace" }, wantErr: []string{"log.level"}},
+		{name: "idle above open", cfg: valid(ProfileDev), modify: func(c *Config) { c.Mysql.MaxIdleConns = c.Mysql.MaxOpenConns + 1 },
+			wantErr: []string{"mysql.max_idle_conns"}},
+		{name: "negative lifetime", cfg: valid(ProfileDev), modify: func(c *Config) { c.Mysql.ConnMaxIdleTime = -time.Second },
+			wantErr: []string{"lifetimes"}},
+		{name: "oidc without client", cfg: valid(ProfileDev), modify: func(c *Config) { c.Oidc.Issuer = "https://idp.example.com" },
+			wantErr: []string{"oidc.client_id"}},
+		{name: "all problems reported", cfg: valid(ProfileDev), modify: func(c *Config) {
+			c.Mysql.Host = ""
+			c.Redis.Addr = ""
+			c.User.RetentionDays = 0
+		}, wantErr: []string{"mysql.host", "redis.addr", "user.retention_days"}},
+		{name: "token secrets", cfg: valid(ProfileDev), modify: func(c *Config) { c.Token.Secret, c.Token.EncryptKey = "", "" },
+			wantErr: []string{"token.secret", "token.encrypt_key"}},
+		{name: "prod secrets", cfg: valid(ProfileProd), wantErr: []string{"mysql.password", "redis.password"}},
+		{name: "prod debug log", cfg: prod(), modify: func(c *Config) { c.Log.Level = "debug" }, wantErr: []string{"debug not allowed"}},
+	}
+	for _, tt := range tests {
+		if tt.modify != nil {
+			tt.modify(tt.cfg)
+		}
+		err := tt.cfg.Validate()
+		if len(tt.wantErr) == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: want error", tt.name)
+			continue
+		}
+		for _, want := range tt.wantErr {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: error %q does not mention %q", tt.name, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv 用 env 标签指定的环境变量覆盖配置, 未设置或为空的变量不覆盖.
+// 列表类型的值以逗号或空格分隔, 时长写作 "1h", "10m"
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+		name := t.Field(i).Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			continue
+		}
+		if field.Type() == durationType {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("env %s: %w", name, err)
+			}
+			field.SetInt(int64(d))
+			continue
+		}
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Int, reflect.Int64:
+			n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("env %s: %w", name, err)
+			}
+			field.SetInt(n)
+		case reflect.Uint8, reflect.Uint32:
+			n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("env %s: %w", name, err)
+			}
+			field.SetUint(n)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("env %s: %w", name, err)
+			}
+			field.SetBool(b)
+		case reflect.Slice:
+			field.Set(reflect.ValueOf(strings.Fields(strings.ReplaceAll(value, ",", " "))))
+		default:
+			return fmt.Errorf("env %s: unsupported field type %s", name, field.Type())
+		}
+	}
+	return nil
+}
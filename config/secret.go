@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SecretProvider 按名称读取密钥. 引用写作 "<scheme>:<name>", 例如 "env:SM_DB_PASSWORD", "file:/run/secrets/db_password"
+type SecretProvider interface {
+	GetSecret(name string) (string, error)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"env":  envSecretProvider{},
+		"file": fileSecretProvider{},
+	}
+)
+
+// RegisterSecretProvider 注册其他密钥来源(如 Vault), 需在 App.Run 加载配置之前注册, 例如在 main 中
+func RegisterSecretProvider(scheme string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[scheme] = provider
+}
+
+// ResolveSecret 前缀不是已注册的 scheme 时按明文处理
+func ResolveSecret(value string) (string, error) {
+	scheme, name, ok := strings.Cut(value, ":")
+	if !ok {
+		return value, nil
+	}
+	secretProvidersMu.RLock()
+	provider, ok := secretProviders[scheme]
+	secretProvidersMu.RUnlock()
+	if !ok {
+		return value, nil
+	}
+	secret, err := provider.GetSecret(name)
+	if err != nil {
+		return "", fmt.Errorf("secret %s: %w", scheme+":"+name, err)
+	}
+	return secret, nil
+}
+
+func resolveSecrets(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := resolveSecrets(field); err != nil {
+				return err
+			}
+			continue
+		}
+		if t.Field(i).Tag.Get("secret") != "true" || field.Kind() != reflect.String || field.String() == "" {
+			continue
+		}
+		secret, err := ResolveSecret(field.String())
+		if err != nil {
+			return err
+		}
+		field.SetString(secret)
+	}
+	return nil
+}
+
+type envSecretProvider struct {
+}
+
+func (envSecretProvider) GetSecret(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("env %s not set", name)
+	}
+	return value, nil
+}
+
+// fileSecretProvider 读取整个文件, 去掉首尾空白, 适用于 docker/k8s 挂载的密钥文件
+type fileSecretProvider struct {
+}
+
+func (fileSecretProvider) GetSecret(name string) (string, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(content)), nil
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Validate 启动时校验配置, 一次返回所有问题
+func (c *Config) Validate() error {
+	problems := make([]string, 0)
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	check(c.Profile == ProfileDev || c.Profile == ProfileTest || c.Profile == ProfileProd, "unknown profile %q", c.Profile)
+	check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port out of range")
+	_, err := time.LoadLocation(c.Server.TimeZone)
+	check(err == nil, "server.time_zone %q invalid", c.Server.TimeZone)
+	check(c.Log.Level == "debug" || c.Log.Level == "info" || c.Log.Level == "warn" || c.Log.Level == "error",
+		"log.level %q invalid", c.Log.Level)
+	check(c.Log.InfoPath != "" && c.Log.ErrorPath != "", "log.info_path and log.error_path required")
+	check(c.Mysql.Host != "", "mysql.host required")
+	check(c.Mysql.Port > 0 && c.Mysql.Port < 65536, "mysql.port out of range")
+	check(c.Mysql.User != "", "mysql.user required")
+	check(c.Mysql.Database != "", "mysql.database required")
+	check(c.Mysql.MaxOpenConns > 0, "mysql.max_open_conns must be positive")
+	check(c.Mysql.MaxIdleConns >= 0 && c.Mysql.MaxIdleConns <= c.Mysql.MaxOpenConns,
+		"mysql.max_idle_conns must be between 0 and mysql.max_open_conns")
+	check(c.Mysql.ConnMaxLifetime >= 0 && c.Mysql.ConnMaxIdleTime >= 0, "mysql connection lifetimes must not be negative")
+	check(c.Redis.Addr != "", "redis.addr required")
+	check(c.Redis.PoolSize > 0, "redis.pool_size must be positive")
+	check(c.Oss.Endpoint != "" && c.Oss.Bucket != "", "oss.endpoint and oss.bucket required")
+	check(c.Password.Argon2Time > 0 && c.Password.Argon2Memory > 0 && c.Password.Argon2Threads > 0,
+		"password argon2 parameters must be positive")
+	check(c.Message.LogPath != "", "message.log_path required")
+	check(c.User.RetentionDays > 0, "user.retention_days must be positive")
+	// 多实例部署时各实例需要相同的密钥, 重启后已签发的令牌和已加密的数据仍需可用
+	check(c.Token.Secret != "", "token.secret required")
+	check(c.Token.EncryptKey != "", "token.encrypt_key required")
+	if c.Oidc.Issuer != "" {
+		check(c.Oidc.ClientId != "" && c.Oidc.RedirectUrl != "", "oidc.client_id and oidc.redirect_url required when oidc.issuer set")
+	}
+	// 生产环境的数据库和缓存不能免密
+	if c.IsProd() {
+		check(c.Mysql.Password != "", "mysql.password required in prod")
+		check(c.Redis.Password != "", "redis.password required in prod")
+		check(c.Log.Level != "debug", "log.level debug not allowed in prod")
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config (profile %s): %s", c.Profile, strings.Join(problems, "; "))
+	}
+	return nil
+}
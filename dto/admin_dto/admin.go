@@ -0,0 +1,48 @@
+package admin_dto
+
+import (
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/dto/user_dto"
+)
+
+type UserListReq struct {
+	Pager   *common_dto.Pager
+	Keyword string
+}
+
+type UserListResp struct {
+	Pager *common_dto.Pager
+	List  []*user_dto.User
+}
+
+// UserTeam 用户所在团队, 不是任何团队子账号的用户是自己团队的所有者
+type UserTeam struct {
+	OwnerId     string
+	OwnerName   string
+	Role        string
+	MemberCount int64
+}
+
+type UserDetail struct {
+	User     *user_dto.User
+	Team     *UserTeam
+	Sessions []*user_dto.Session
+}
+
+type UserActivityReq struct {
+	Pager  *common_dto.Pager
+	UserId string
+}
+
+type DisableUserReq struct {
+	UserId string
+	Reason string
+}
+
+type EnableUserReq struct {
+	UserId string
+}
+
+type ImpersonateReq struct {
+	UserId string
+}
@@ -0,0 +1,70 @@
+package api_key_dto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+const (
+	ApiKeyStatusActive  = 1
+	ApiKeyStatusRevoked = 2
+)
+
+// 签名请求头. 服务端只保存密钥的摘要, 请求需同时携带密钥, 并用密钥对请求签名
+const (
+	HeaderAccessKey = "X-Api-Key"
+	HeaderSecret    = "X-Api-Secret"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+type ApiKey struct {
+	ID           string
+	UserID       string
+	Name         string
+	AccessKey    string
+	SecretHash   string
+	Scopes       []string
+	Status       int
+	LastUsedTime *time.Time
+	// Secret 仅在创建和轮换时返回一次
+	Secret     string
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+// HasScope 权限范围格式为 "资源:read" 或 "资源:write", 写权限包含读权限
+func (a *ApiKey) HasScope(resource string, action string) bool {
+	for _, scope := range a.Scopes {
+		if scope == resource+":"+action || scope == resource+":write" {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateApiKeyReq struct {
+	Name   string
+	Scopes []string
+}
+
+// SignedRequest 参与签名的请求内容
+type SignedRequest struct {
+	AccessKey string
+	Secret    string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Method    string
+	Path      string
+	Body      []byte
+}
+
+// StringToSign 签名原文: 方法, 路径(含查询参数), 时间戳, nonce 与请求体 SHA-256 十六进制摘要, 以换行分隔
+func (s *SignedRequest) StringToSign() string {
+	bodyHash := sha256.Sum256(s.Body)
+	return strings.Join([]string{s.Method, s.Path, s.Timestamp, s.Nonce, hex.EncodeToString(bodyHash[:])}, "\n")
+}
@@ -0,0 +1,42 @@
+package audit_dto
+
+import (
+	"github.com/shop_management/dto/common_dto"
+	"time"
+)
+
+type AuditLog struct {
+	ID        string
+	TeamID    string
+	ActorID   string
+	ActorName string
+	// ImpersonatorID 平台管理员模拟登录时的实际操作人
+	ImpersonatorID string
+	Action         string
+	EntityType     string
+	EntityID       string
+	BeforeData     string
+	AfterData      string
+	Ip             string
+	RequestID      string
+	CreateTime     time.Time
+}
+
+// AuditListReq 时间范围为左闭右开, 零值表示不限制
+type AuditListReq struct {
+	Pager      *common_dto.Pager
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	RequestID  string
+	StartTime  time.Time
+	EndTime    time.Time
+	// AllTeams 查询全部团队的记录, 仅供平台管理员使用
+	AllTeams bool
+}
+
+type AuditListResp struct {
+	Pager *common_dto.Pager
+	List  []*AuditLog
+}
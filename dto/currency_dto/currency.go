@@ -0,0 +1,34 @@
+package currency_dto
+
+import "time"
+
+// DefaultBaseCurrency 团队未设置本位币时默认使用人民币
+const DefaultBaseCurrency = "CNY"
+
+const (
+	RateSourceManual = 1
+	RateSourceImport = 2
+)
+
+type ExchangeRate struct {
+	ID           string
+	UserID       string
+	FromCurrency string
+	ToCurrency   string
+	// Rate 1 单位 FromCurrency 可兑换的 ToCurrency 数量
+	Rate          float64
+	EffectiveDate time.Time
+	Source        int
+	CreateTime    time.Time
+}
+
+type CurrencySetting struct {
+	UserID       string
+	BaseCurrency string
+}
+
+type RateListReq struct {
+	UserID       string
+	FromCurrency string
+	ToCurrency   string
+}
@@ -0,0 +1,83 @@
+package fulfillment_dto
+
+import "time"
+
+const (
+	PickTaskStatusPending = 1
+	PickTaskStatusClaimed = 2
+	PickTaskStatusPicked  = 3
+)
+
+type PickTask struct {
+	ID         string
+	OrderID    string
+	Warehouse  string
+	StoragePos string
+	Status     int
+	AssigneeID string
+	ClaimTime  *time.Time
+	PickedTime *time.Time
+	Items      []*PickTaskItem
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+type PickTaskItem struct {
+	ID          string
+	TaskID      string
+	OrderItemID string
+	ProductID   string
+	ProductName string
+	StorageCode string
+	Quantity    int
+}
+
+type PickTaskListReq struct {
+	OrderID    string
+	AssigneeID string
+	Status     int
+	// Warehouses 子账号有仓库授权时只查询被授权仓库的任务
+	Warehouses []string
+}
+
+type PackReq struct {
+	OrderID      string
+	StorageCodes []string
+}
+
+type PackRecord struct {
+	ID           string
+	OrderID      string
+	PackerID     string
+	ScannedCodes []string
+	PackTime     time.Time
+}
+
+type ShipReq struct {
+	OrderID    string
+	Carrier    string
+	TrackingNo string
+}
+
+type Shipment struct {
+	ID         string
+	OrderID    string
+	Carrier    string
+	TrackingNo string
+	ShipperID  string
+	ShipTime   time.Time
+}
+
+// FulfillmentTimeline 订单各履约环节的时间点, 耗时单位为秒
+type FulfillmentTimeline struct {
+	OrderID        string
+	ConfirmTime    *time.Time
+	FirstClaimTime *time.Time
+	LastPickedTime *time.Time
+	PackTime       *time.Time
+	ShipTime       *time.Time
+	PickSeconds    int64
+	PackSeconds    int64
+	ShipSeconds    int64
+	TotalSeconds   int64
+}
@@ -0,0 +1,92 @@
+package order_dto
+
+import (
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/util"
+	"time"
+)
+
+const (
+	OrderStatusDraft     = 1
+	OrderStatusConfirmed = 2
+	OrderStatusPicking   = 3
+	OrderStatusPicked    = 4
+	OrderStatusPacked    = 5
+	OrderStatusShipped   = 6
+	// OrderStatusCompleted 门店收银订单, 当场结算并交付
+	OrderStatusCompleted = 7
+	OrderStatusCanceled  = 9
+)
+
+type SalesOrder struct {
+	ID      string
+	TeamID  string
+	OrderNo string
+	// UserID 创建订单的用户, 团队成员都可以查看和处理订单
+	UserID       string
+	CustomerID   string
+	CustomerName string
+	Remark       string
+	Status       int
+	// Currency 订单所有金额的币种, 下单时取团队本位币
+	Currency string
+	// TotalAmount 含税应付金额
+	TotalAmount    util.Money
+	DiscountAmount util.Money
+	NetAmount      util.Money
+	TaxAmount      util.Money
+	ConfirmTime    *time.Time
+	Items          []*SalesOrderItem
+	CreateTime     time.Time
+	ModifyTime     time.Time
+}
+
+type SalesOrderItem struct {
+	ID             string
+	OrderID        string
+	ProductID      string
+	Quantity       int
+	UnitPrice      util.Money
+	DiscountAmount util.Money
+	Amount         util.Money
+	TaxRate        float64
+	NetAmount      util.Money
+	TaxAmount      util.Money
+	CreateTime     time.Time
+	ModifyTime     time.Time
+}
+
+// AddOrderItemReq 单价取商品售价, 不接受客户端传入
+type AddOrderItemReq struct {
+	ProductID string
+	Quantity  int
+}
+
+type AddOrderReq struct {
+	CustomerID   string
+	CustomerName string
+	Remark       string
+	CouponCodes  []string
+	Items        []*AddOrderItemReq
+}
+
+// Invoice 发票按税率汇总不含税金额与税额
+type Invoice struct {
+	InvoiceNo    string
+	Order        *SalesOrder
+	NetAmount    util.Money
+	TaxAmount    util.Money
+	TotalAmount  util.Money
+	TaxBreakdown []*tax_dto.TaxBreakdown
+}
+
+type OrderListReq struct {
+	Pager  *common_dto.Pager
+	Status int
+}
+
+type OrderListResp struct {
+	Pager *common_dto.Pager
+	List  []*SalesOrder
+}
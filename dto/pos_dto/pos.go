@@ -0,0 +1,78 @@
+package pos_dto
+
+import (
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/util"
+	"time"
+)
+
+const (
+	ShiftStatusOpen   = 1
+	ShiftStatusClosed = 2
+)
+
+const (
+	TenderTypeCash   = "cash"
+	TenderTypeCard   = "card"
+	TenderTypeWechat = "wechat"
+	TenderTypeAlipay = "alipay"
+)
+
+type PosShift struct {
+	ID             string
+	UserID         string
+	Status         int
+	Currency       string
+	OpeningCash    util.Money
+	ClosingCash    util.Money
+	ExpectedCash   util.Money
+	CashDifference util.Money
+	OpenTime       time.Time
+	CloseTime      *time.Time
+}
+
+type PosPayment struct {
+	ID           string
+	ShiftID      string
+	OrderID      string
+	TenderType   string
+	Amount       util.Money
+	ChangeAmount util.Money
+}
+
+type CheckoutItem struct {
+	Code     string
+	Quantity int
+}
+
+type Tender struct {
+	TenderType string
+	Amount     util.Money
+}
+
+type CheckoutReq struct {
+	CustomerID  string
+	CouponCodes []string
+	Items       []*CheckoutItem
+	Tenders     []*Tender
+}
+
+type Receipt struct {
+	OrderID        string
+	ReceiptNo      string
+	ShiftID        string
+	CashierID      string
+	Currency       string
+	Lines          []*promotion_dto.EvaluateLine
+	OriginalAmount util.Money
+	DiscountAmount util.Money
+	NetAmount      util.Money
+	TaxAmount      util.Money
+	TaxBreakdown   []*tax_dto.TaxBreakdown
+	TotalAmount    util.Money
+	PaidAmount     util.Money
+	ChangeAmount   util.Money
+	Tenders        []*Tender
+	CheckoutTime   time.Time
+}
@@ -2,29 +2,43 @@ package product_dto
 
 import (
 	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/util"
 	"time"
 )
 
 type Product struct {
-	ID               string
-	ImageURL         string
-	StorageCode      string
-	StoragePos       string
-	Name             string
-	Color            string
-	BasePrice        float64
-	CostPrice        float64
-	PurchasePrice    float64
+	ID          string
+	ImageURL    string
+	StorageCode string
+	Barcode     string
+	StoragePos  string
+	// Warehouse 所在仓库编码, 可以按仓库对子账号授权
+	Warehouse string
+	Name      string
+	Color     string
+	Category  string
+	BasePrice util.Money
+	// SaleCurrency BasePrice 的币种
+	SaleCurrency  string
+	CostPrice     util.Money
+	PurchasePrice util.Money
+	// PurchaseCurrency CostPrice 与 PurchasePrice 的币种, 从海外工厂采购时可能与销售币种不同
+	PurchaseCurrency string
 	Factory          string
 	Stock            int
 	InProductionNums int
 	InOrderNums      int
+	HeldNums         int
 	CreateTime       time.Time
 	ModifyTime       time.Time
 }
 
 type ProductListReq struct {
-	Pager *common_dto.Pager `json:"pager"`
+	Pager     *common_dto.Pager `json:"pager"`
+	Category  string            `json:"category"`
+	Warehouse string            `json:"warehouse"`
+	// Warehouses 子账号有仓库授权时只查询被授权的仓库
+	Warehouses []string `json:"warehouses"`
 }
 
 type ProductListResp struct {
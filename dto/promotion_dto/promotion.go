@@ -0,0 +1,123 @@
+package promotion_dto
+
+import (
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/util"
+	"time"
+)
+
+const (
+	// PromotionTypePercentOff 类目折扣, Category 为空时对全部商品生效
+	PromotionTypePercentOff = 1
+	// PromotionTypeBuyXGetY 买 X 送 Y, 同一商品每满 X+Y 件免 Y 件
+	PromotionTypeBuyXGetY = 2
+	// PromotionTypeTiered 按单品数量阶梯折扣
+	PromotionTypeTiered = 3
+	// PromotionTypeCoupon 满减优惠券, 按金额比例分摊到各行
+	PromotionTypeCoupon = 4
+)
+
+const (
+	PromotionStatusEnabled  = 1
+	PromotionStatusDisabled = 2
+)
+
+type PromotionTier struct {
+	MinQuantity int     `json:"min_quantity"`
+	Percent     float64 `json:"percent"`
+}
+
+type PromotionRule struct {
+	Percent        float64          `json:"percent,omitempty"`
+	BuyQuantity    int              `json:"buy_quantity,omitempty"`
+	FreeQuantity   int              `json:"free_quantity,omitempty"`
+	Tiers          []*PromotionTier `json:"tiers,omitempty"`
+	Amount         util.Money       `json:"amount,omitempty"`
+	MinOrderAmount util.Money       `json:"min_order_amount,omitempty"`
+}
+
+type Promotion struct {
+	ID         string
+	UserID     string
+	Name       string
+	Type       int
+	Category   string
+	Rule       *PromotionRule
+	CouponCode string
+	StartTime  time.Time
+	EndTime    time.Time
+	UsageLimit int
+	UsedCount  int
+	Stackable  bool
+	Priority   int
+	Status     int
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+type CouponRedemption struct {
+	ID          string
+	PromotionID string
+	CustomerID  string
+	OrderID     string
+	CouponCode  string
+	Amount      util.Money
+	CreateTime  time.Time
+}
+
+type EvaluateItem struct {
+	ProductID string
+	Quantity  int
+}
+
+type EvaluateReq struct {
+	CustomerID  string
+	CouponCodes []string
+	Items       []*EvaluateItem
+}
+
+type AppliedDiscount struct {
+	PromotionID   string
+	PromotionName string
+	Type          int
+	CouponCode    string
+	Amount        util.Money
+}
+
+type EvaluateLine struct {
+	ProductID      string
+	ProductName    string
+	Category       string
+	Quantity       int
+	UnitPrice      util.Money
+	OriginalAmount util.Money
+	DiscountAmount util.Money
+	FinalAmount    util.Money
+	Discounts      []*AppliedDiscount
+	TaxRate        float64
+	NetAmount      util.Money
+	TaxAmount      util.Money
+	GrossAmount    util.Money
+}
+
+type RejectedCoupon struct {
+	CouponCode string
+	Reason     string
+}
+
+type EvaluateResp struct {
+	CustomerID      string
+	Currency        string
+	Lines           []*EvaluateLine
+	OriginalAmount  util.Money
+	DiscountAmount  util.Money
+	FinalAmount     util.Money
+	RejectedCoupons []*RejectedCoupon
+	// 税额按折扣后的金额计算, GrossAmount 为客户应付金额
+	PriceIncludesTax bool
+	TaxExempt        bool
+	NetAmount        util.Money
+	TaxAmount        util.Money
+	GrossAmount      util.Money
+	TaxBreakdown     []*tax_dto.TaxBreakdown
+}
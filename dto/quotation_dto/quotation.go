@@ -0,0 +1,77 @@
+package quotation_dto
+
+import (
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/util"
+	"time"
+)
+
+const (
+	QuotationStatusActive     = 1
+	QuotationStatusExpired    = 2
+	QuotationStatusConverted  = 3
+	QuotationStatusSuperseded = 4
+	QuotationStatusCanceled   = 5
+)
+
+type Quotation struct {
+	ID           string
+	TeamID       string
+	QuotationNo  string
+	Version      int
+	UserID       string
+	CustomerID   string
+	CustomerName string
+	Remark       string
+	Status       int
+	Currency     string
+	HoldStock    bool
+	TotalAmount  util.Money
+	NetAmount    util.Money
+	TaxAmount    util.Money
+	ValidUntil   time.Time
+	OrderID      string
+	Items        []*QuotationItem
+	CreateTime   time.Time
+	ModifyTime   time.Time
+}
+
+type QuotationItem struct {
+	ID          string
+	QuotationID string
+	ProductID   string
+	Quantity    int
+	UnitPrice   util.Money
+	Amount      util.Money
+	TaxRate     float64
+	NetAmount   util.Money
+	TaxAmount   util.Money
+}
+
+type SaveQuotationItemReq struct {
+	ProductID string
+	Quantity  int
+	UnitPrice util.Money
+}
+
+type SaveQuotationReq struct {
+	// QuotationID 不为空时表示修订该报价单, 生成新版本
+	QuotationID  string
+	CustomerID   string
+	CustomerName string
+	Remark       string
+	HoldStock    bool
+	ValidUntil   time.Time
+	Items        []*SaveQuotationItemReq
+}
+
+type QuotationListReq struct {
+	Pager       *common_dto.Pager
+	QuotationNo string
+	Status      int
+}
+
+type QuotationListResp struct {
+	Pager *common_dto.Pager
+	List  []*Quotation
+}
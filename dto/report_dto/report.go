@@ -0,0 +1,46 @@
+package report_dto
+
+import (
+	"github.com/shop_management/util"
+	"time"
+)
+
+type SalesReportReq struct {
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+type SalesReportDay struct {
+	Date        string
+	OrderCount  int
+	NetAmount   util.Money
+	TaxAmount   util.Money
+	TotalAmount util.Money
+}
+
+// SalesReport 所有金额按下单当日汇率换算为团队本位币
+type SalesReport struct {
+	Currency    string
+	Days        []*SalesReportDay
+	OrderCount  int
+	NetAmount   util.Money
+	TaxAmount   util.Money
+	TotalAmount util.Money
+}
+
+type InventoryValuationLine struct {
+	ProductID        string
+	Name             string
+	Stock            int
+	UnitCost         util.Money
+	UnitCostCurrency string
+	Amount           util.Money
+}
+
+// InventoryValuation 库存按成本价以当日汇率换算为团队本位币
+type InventoryValuation struct {
+	Currency    string
+	Date        string
+	Lines       []*InventoryValuationLine
+	TotalAmount util.Money
+}
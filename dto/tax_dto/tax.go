@@ -0,0 +1,68 @@
+package tax_dto
+
+import (
+	"github.com/shop_management/util"
+	"time"
+)
+
+type TaxRule struct {
+	ID     string
+	UserID string
+	Name   string
+	// Category 为空表示默认税率, 未单独配置税率的类目使用默认税率
+	Category   string
+	Rate       float64
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+type TaxSetting struct {
+	UserID string
+	// PriceIncludesTax 为 true 时商品售价为含税价, 否则为不含税价, 税额另外加收
+	PriceIncludesTax bool
+}
+
+type TaxExemption struct {
+	ID         string
+	UserID     string
+	CustomerID string
+	Reason     string
+	CreateTime time.Time
+}
+
+type CalculateLine struct {
+	ProductID string
+	Category  string
+	// Amount 折扣后的行金额, 含税与否取决于 TaxSetting
+	Amount util.Money
+}
+
+type CalculateReq struct {
+	CustomerID string
+	Lines      []*CalculateLine
+}
+
+type TaxLine struct {
+	ProductID   string
+	Category    string
+	TaxRate     float64
+	NetAmount   util.Money
+	TaxAmount   util.Money
+	GrossAmount util.Money
+}
+
+type TaxBreakdown struct {
+	TaxRate   float64
+	NetAmount util.Money
+	TaxAmount util.Money
+}
+
+type CalculateResp struct {
+	PriceIncludesTax bool
+	Exempt           bool
+	Lines            []*TaxLine
+	Breakdown        []*TaxBreakdown
+	NetAmount        util.Money
+	TaxAmount        util.Money
+	GrossAmount      util.Money
+}
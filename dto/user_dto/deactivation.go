@@ -0,0 +1,25 @@
+package user_dto
+
+import "time"
+
+type DeactivateReq struct {
+	Password string `json:"password"`
+}
+
+type DeactivateMemberReq struct {
+	Id string `json:"id"`
+}
+
+type ReactivateReq struct {
+	UserId string `json:"user_id"`
+}
+
+// DeactivatedUser 已停用的子账号, PurgeTime 之后数据被清除, 不能再恢复
+type DeactivatedUser struct {
+	UserId          string
+	Name            string
+	Phone           string
+	Role            string
+	DeactivatedTime time.Time
+	PurgeTime       time.Time
+}
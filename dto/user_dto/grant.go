@@ -0,0 +1,40 @@
+package user_dto
+
+// 细粒度授权在角色权限之外针对具体资源生效, 只能授予子账号, 团队所有者不受限制
+const (
+	// GrantResourceWarehouse 子账号有仓库授权时, 只能读写被授权仓库的商品和拣货任务, 没有任何仓库授权时不限制
+	GrantResourceWarehouse = "warehouse"
+	// GrantResourceProduct 商品上的功能授权, ResourceID 固定为 GrantAllResources
+	GrantResourceProduct = "product"
+
+	GrantAllResources = "*"
+)
+
+const (
+	// ActionViewCost 查看商品成本价和采购价, 团队所有者和经理默认拥有
+	ActionViewCost = "view_cost"
+)
+
+type UserGrant struct {
+	ID           string
+	UserID       string
+	ResourceType string
+	ResourceID   string
+	Action       string
+}
+
+// GrantListReq Id 为子账号在 user_team 中的记录id, 与 AssignRoleReq 一致
+type GrantListReq struct {
+	Id string
+}
+
+type AddGrantReq struct {
+	Id           string
+	ResourceType string
+	ResourceID   string
+	Action       string
+}
+
+type DelGrantReq struct {
+	Id string
+}
@@ -0,0 +1,51 @@
+package user_dto
+
+import (
+	"github.com/shop_management/dto/common_dto"
+	"time"
+)
+
+// 邀请状态, 已过期的邀请在库中仍为待处理, 查询时按 ExpireTime 判断
+const (
+	InvitationStatusPending  = 1
+	InvitationStatusAccepted = 2
+	InvitationStatusDeclined = 3
+	InvitationStatusExpired  = 4
+)
+
+type Invitation struct {
+	ID          string
+	UserID      string
+	InviterName string
+	Phone       string
+	Email       string
+	Role        string
+	Status      int
+	InviteeID   string
+	// Token 仅在创建邀请时返回, 服务端不保存
+	Token      string
+	ExpireTime time.Time
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+func (i *Invitation) IsExpired(now time.Time) bool {
+	return i.Status == InvitationStatusPending && now.After(i.ExpireTime)
+}
+
+type InviteReq struct {
+	Phone string
+	Email string
+	Role  string
+}
+
+type InvitationListReq struct {
+	Pager  *common_dto.Pager
+	UserID string
+	Status int
+}
+
+type InvitationListResp struct {
+	Pager *common_dto.Pager
+	List  []*Invitation
+}
@@ -0,0 +1,27 @@
+package user_dto
+
+import (
+	"github.com/shop_management/dto/common_dto"
+	"time"
+)
+
+type LoginLockout struct {
+	ID          string
+	TeamID      string
+	UserID      string
+	Phone       string
+	Ip          string
+	UserAgent   string
+	LockedUntil time.Time
+	CreateTime  time.Time
+}
+
+type LoginLockoutListReq struct {
+	Pager  *common_dto.Pager
+	UserID string
+}
+
+type LoginLockoutListResp struct {
+	Pager *common_dto.Pager
+	List  []*LoginLockout
+}
@@ -0,0 +1,16 @@
+package user_dto
+
+type UserIdentity struct {
+	ID      string
+	UserID  string
+	Issuer  string
+	Subject string
+	Email   string
+	Phone   string
+}
+
+// OidcLoginReq 身份提供方回调到前端后, 前端提交的授权码和 state, 登录和关联共用
+type OidcLoginReq struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
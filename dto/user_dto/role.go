@@ -0,0 +1,46 @@
+package user_dto
+
+// 团队角色, 团队所有者不在 user_team 中存储记录, 其余角色记录在子账号所在的 user_team 行上
+const (
+	RoleOwner          = "owner"
+	RoleManager        = "manager"
+	RoleWarehouseClerk = "warehouse_clerk"
+	RoleSales          = "sales"
+	RoleReadOnly       = "read_only"
+)
+
+// 权限按路由分组划分资源, GET 请求视为读, 其他请求视为写
+const (
+	ResourceProduct     = "product"
+	ResourceOrder       = "order"
+	ResourceFulfillment = "fulfillment"
+	ResourcePromotion   = "promotion"
+	ResourcePos         = "pos"
+	ResourceQuotation   = "quotation"
+	ResourceTax         = "tax"
+	ResourceCurrency    = "currency"
+	ResourceReport      = "report"
+	ResourceFile        = "file"
+	ResourceTeam        = "team"
+	ResourceWallet      = "wallet"
+	// ResourceAudit 不分配给任何角色, 只有团队所有者可以查看
+	ResourceAudit = "audit"
+)
+
+const (
+	ActionRead  = "read"
+	ActionWrite = "write"
+	// ActionCredit 钱包充值和退款, 团队所有者和经理拥有, 其他角色的写权限只能扣款
+	ActionCredit = "credit"
+)
+
+// TeamRole 当前登录用户在团队中的身份, OwnerId 为团队所有者的用户id
+type TeamRole struct {
+	UserId  string
+	OwnerId string
+	Role    string
+}
+
+func (t *TeamRole) IsOwner() bool {
+	return t.Role == RoleOwner
+}
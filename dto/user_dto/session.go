@@ -0,0 +1,25 @@
+package user_dto
+
+import "time"
+
+// Session 一次登录对应一个会话, ID 由 token 哈希得到, 不会泄露 token 本身
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	UserAgent  string    `json:"user_agent"`
+	Ip         string    `json:"ip"`
+	CreateTime time.Time `json:"create_time"`
+	ExpireTime time.Time `json:"expire_time"`
+	// ImpersonatorID 平台管理员模拟登录时的管理员id
+	ImpersonatorID string    `json:"impersonator_id,omitempty"`
+	LastSeenTime   time.Time `json:"-"`
+	Current        bool      `json:"-"`
+}
+
+type RevokeSessionReq struct {
+	ID string `json:"id"`
+}
+
+type ForceLogoutReq struct {
+	Id string `json:"id"`
+}
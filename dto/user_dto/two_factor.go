@@ -0,0 +1,37 @@
+package user_dto
+
+// 两步验证状态, 扫码后需校验一次动态码才会启用
+const (
+	TwoFactorStatusPending = 1
+	TwoFactorStatusEnabled = 2
+)
+
+type TwoFactor struct {
+	ID     string
+	UserID string
+	// Secret 明文密钥, 加解密在仓储层完成
+	Secret string
+	Status int
+}
+
+// TwoFactorEnrollment 开启两步验证时返回给用户, 密钥只在此时展示
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code"`
+}
+
+// LoginResult 开启两步验证的用户密码校验通过后只返回 ChallengeToken, 校验动态码后才登录
+type LoginResult struct {
+	UserId            string
+	TwoFactorRequired bool
+	ChallengeToken    string
+}
+
+type LoginVerifyReq struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
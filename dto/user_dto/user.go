@@ -2,28 +2,44 @@ package user_dto
 
 import (
 	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/util"
 	"time"
 )
 
 type User struct {
-	Id         string
-	Name       string
-	Email      string
-	Password   string
-	ApiKey     string
-	ApiSecret  string
-	AvatarUrl  string
-	Phone      string
-	Balance    float64
-	Currency   string
-	CreateTime time.Time
-	ModifyTime time.Time
+	Id        string
+	Name      string
+	Email     string
+	Password  string
+	ApiKey    string
+	ApiSecret string
+	AvatarUrl string
+	Phone     string
+	Balance   util.Money
+	Currency  string
+	// DeletedTime 不为空表示账号已停用
+	DeletedTime       *time.Time
+	DeactivatedTeamId string
+	DeactivatedRole   string
+	DisabledTime      *time.Time
+	PlatformAdmin     bool
+	CreateTime        time.Time
+	ModifyTime        time.Time
+}
+
+func (u *User) IsDeactivated() bool {
+	return u.DeletedTime != nil
+}
+
+func (u *User) IsDisabled() bool {
+	return u.DisabledTime != nil
 }
 
 type SubUser struct {
 	Id        string
 	UserId    string
 	SubUserId string
+	Role      string
 	Name      string
 	Phone     string
 }
@@ -38,6 +54,7 @@ type RegisterUserReq struct {
 	Phone           string `json:"phone"`
 	Password        string `json:"password"`
 	ConfirmPassword string `json:"confirm_password"`
+	InviteToken     string `json:"invite_token"`
 }
 
 type ModifyUserPasswordReq struct {
@@ -47,29 +64,38 @@ type ModifyUserPasswordReq struct {
 	ConfirmPassword string `json:"confirm_password"`
 }
 
+type SendResetCodeReq struct {
+	Phone string `json:"phone"`
+}
+
+type ResetPasswordReq struct {
+	Phone           string `json:"phone"`
+	Code            string `json:"code"`
+	NewPassword     string `json:"new_password"`
+	ConfirmPassword string `json:"confirm_password"`
+}
+
 type UserProfile struct {
 	UserId    string
 	AvatarUrl string
 	Name      string
 	Email     string
 	Phone     string
+	Role      string
 	IsAdmin   bool
 }
 
-type GenerateApiSecret struct {
-	UserId string `json:"user_id"`
-	ApiKey string `json:"api_key"`
-}
-
-type GetApiSecret struct {
-	ApiKey    string `json:"api_key"`
-	ApiSecret string `json:"api_secret"`
+type SaveUserProfileReq struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
 }
 
 type UserListReq struct {
 	Pager   *common_dto.Pager `json:"pager"`
 	Name    string            `json:"name" form:"name"`
 	UserIds []string          `json:"user_ids"`
+	// Keyword 按名称或手机号模糊查询
+	Keyword string `json:"keyword"`
 }
 
 type UserListResp struct {
@@ -89,6 +115,12 @@ type SubUserListResp struct {
 
 type AddSubUserReq struct {
 	Phone string `json:"phone"`
+	Role  string `json:"role"`
+}
+
+type AssignRoleReq struct {
+	Id   string `json:"id"`
+	Role string `json:"role"`
 }
 
 type DelSubUserReq struct {
@@ -0,0 +1,89 @@
+package wallet_dto
+
+import (
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/util"
+	"time"
+)
+
+// 钱包所属对象, 团队成员或客户
+const (
+	OwnerTypeUser     = "user"
+	OwnerTypeCustomer = "customer"
+)
+
+const (
+	EntryTypeTopUp     = "top_up"
+	EntryTypeDeduction = "deduction"
+	EntryTypeRefund    = "refund"
+)
+
+type Wallet struct {
+	ID         string
+	OwnerType  string
+	OwnerID    string
+	Currency   string
+	Balance    util.Money
+	CreateTime time.Time
+}
+
+type WalletEntry struct {
+	ID           string
+	WalletID     string
+	Type         string
+	Amount       util.Money
+	BalanceAfter util.Money
+	RefEntryID   string
+	Reference    string
+	Remark       string
+	OperatorID   string
+	CreateTime   time.Time
+}
+
+type WalletOwner struct {
+	OwnerType string
+	OwnerID   string
+}
+
+// AddWalletEntryReq 充值或扣款, Amount 为正数
+type AddWalletEntryReq struct {
+	WalletOwner
+	Amount    util.Money
+	Reference string
+	Remark    string
+}
+
+// RefundReq 按扣款流水退款, 累计退款不能超过扣款金额
+type RefundReq struct {
+	EntryID string
+	Amount  util.Money
+	Remark  string
+}
+
+type WalletEntryListReq struct {
+	WalletOwner
+	Pager *common_dto.Pager
+	Type  string
+}
+
+type WalletEntryListResp struct {
+	Pager *common_dto.Pager
+	List  []*WalletEntry
+}
+
+// WalletStatistic 消费金额为扣款减去退款
+type WalletStatistic struct {
+	Currency      string
+	TotalUsage    util.Money
+	LastWeekUsage util.Money
+}
+
+type WalletTrendReq struct {
+	WalletOwner
+	Days int
+}
+
+type WalletTrendItem struct {
+	Day   string
+	Usage util.Money
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// ApiKey 不保存明文密钥, 只保存密钥的 SHA-256 摘要
+type ApiKey struct {
+	BaseModel
+	ID           string     `gorm:"type:varchar(36);primaryKey"`
+	UserID       string     `gorm:"type:varchar(36);index"`
+	Name         string     `gorm:"type:varchar(255)"`
+	AccessKey    string     `gorm:"type:varchar(64);uniqueIndex"`
+	SecretHash   string     `gorm:"type:varchar(64)"`
+	Scopes       string     `gorm:"type:varchar(1024)"`
+	Status       int        `gorm:"type:int"`
+	LastUsedTime *time.Time `gorm:"type:datetime"`
+	CreateTime   time.Time  `gorm:"type:datetime"`
+	ModifyTime   time.Time  `gorm:"type:datetime"`
+}
+
+func (a *ApiKey) TableName() string {
+	return "api_key"
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// AuditLog 数据变更记录, 由 util.RegisterAudit 注册的回调写入
+type AuditLog struct {
+	ID      string `gorm:"type:varchar(36);primaryKey"`
+	TeamID  string `gorm:"type:varchar(36);index"`
+	ActorID string `gorm:"type:varchar(36);index"`
+	// ImpersonatorID 平台管理员模拟登录时的实际操作人
+	ImpersonatorID string    `gorm:"type:varchar(36)"`
+	Action         string    `gorm:"type:varchar(16)"`
+	EntityType     string    `gorm:"type:varchar(64);index:idx_audit_entity"`
+	EntityID       string    `gorm:"type:varchar(64);index:idx_audit_entity"`
+	BeforeData     string    `gorm:"type:text"`
+	AfterData      string    `gorm:"type:text"`
+	Ip             string    `gorm:"type:varchar(64)"`
+	RequestID      string    `gorm:"type:varchar(64);index"`
+	CreateTime     time.Time `gorm:"type:datetime;index"`
+}
+
+func (a *AuditLog) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+type ExchangeRate struct {
+	BaseModel
+	ID            string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID        string    `gorm:"type:varchar(36);index"`
+	UserID        string    `gorm:"type:varchar(36);uniqueIndex:uk_user_pair_date"`
+	FromCurrency  string    `gorm:"type:varchar(8);uniqueIndex:uk_user_pair_date"`
+	ToCurrency    string    `gorm:"type:varchar(8);uniqueIndex:uk_user_pair_date"`
+	Rate          float64   `gorm:"type:decimal(18,8)"`
+	EffectiveDate time.Time `gorm:"type:date;uniqueIndex:uk_user_pair_date"`
+	Source        int       `gorm:"type:int"`
+	CreateTime    time.Time `gorm:"type:datetime"`
+	ModifyTime    time.Time `gorm:"type:datetime"`
+}
+
+func (e *ExchangeRate) TableName() string {
+	return "exchange_rate"
+}
+
+type CurrencySetting struct {
+	BaseModel
+	ID           string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID       string    `gorm:"type:varchar(36);index"`
+	UserID       string    `gorm:"type:varchar(36);uniqueIndex"`
+	BaseCurrency string    `gorm:"type:varchar(8)"`
+	CreateTime   time.Time `gorm:"type:datetime"`
+	ModifyTime   time.Time `gorm:"type:datetime"`
+}
+
+func (c *CurrencySetting) TableName() string {
+	return "currency_setting"
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+type PickTask struct {
+	BaseModel
+	ID         string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID     string     `gorm:"type:varchar(36);index"`
+	OrderID    string     `gorm:"type:varchar(36)"`
+	Warehouse  string     `gorm:"type:varchar(64)"`
+	StoragePos string     `gorm:"type:varchar(255)"`
+	Status     int        `gorm:"type:int"`
+	AssigneeID string     `gorm:"type:varchar(36)"`
+	ClaimTime  *time.Time `gorm:"type:datetime"`
+	PickedTime *time.Time `gorm:"type:datetime"`
+	CreateTime time.Time  `gorm:"type:datetime"`
+	ModifyTime time.Time  `gorm:"type:datetime"`
+}
+
+func (p *PickTask) TableName() string {
+	return "pick_task"
+}
+
+type PickTaskItem struct {
+	BaseModel
+	ID          string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID      string    `gorm:"type:varchar(36);index"`
+	TaskID      string    `gorm:"type:varchar(36)"`
+	OrderItemID string    `gorm:"type:varchar(36)"`
+	ProductID   string    `gorm:"type:varchar(36)"`
+	StorageCode string    `gorm:"type:varchar(255)"`
+	Quantity    int       `gorm:"type:int"`
+	CreateTime  time.Time `gorm:"type:datetime"`
+	ModifyTime  time.Time `gorm:"type:datetime"`
+}
+
+func (p *PickTaskItem) TableName() string {
+	return "pick_task_item"
+}
+
+type PackRecord struct {
+	BaseModel
+	ID           string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID       string    `gorm:"type:varchar(36);index"`
+	OrderID      string    `gorm:"type:varchar(36)"`
+	PackerID     string    `gorm:"type:varchar(36)"`
+	ScannedCodes string    `gorm:"type:text"`
+	PackTime     time.Time `gorm:"type:datetime"`
+	CreateTime   time.Time `gorm:"type:datetime"`
+	ModifyTime   time.Time `gorm:"type:datetime"`
+}
+
+func (p *PackRecord) TableName() string {
+	return "pack_record"
+}
+
+type Shipment struct {
+	BaseModel
+	ID         string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID     string    `gorm:"type:varchar(36);index"`
+	OrderID    string    `gorm:"type:varchar(36)"`
+	Carrier    string    `gorm:"type:varchar(64)"`
+	TrackingNo string    `gorm:"type:varchar(128)"`
+	ShipperID  string    `gorm:"type:varchar(36)"`
+	ShipTime   time.Time `gorm:"type:datetime"`
+	CreateTime time.Time `gorm:"type:datetime"`
+	ModifyTime time.Time `gorm:"type:datetime"`
+}
+
+func (s *Shipment) TableName() string {
+	return "shipment"
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// LoginLockout 账号因登录失败次数过多被锁定的记录, TeamID 为被锁定用户所在团队, 供团队所有者查看
+type LoginLockout struct {
+	BaseModel
+	ID          string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID      string    `gorm:"type:varchar(36);index"`
+	UserID      string    `gorm:"type:varchar(36);index"`
+	Phone       string    `gorm:"type:varchar(32)"`
+	Ip          string    `gorm:"type:varchar(64)"`
+	UserAgent   string    `gorm:"type:varchar(512)"`
+	LockedUntil time.Time `gorm:"type:datetime"`
+	CreateTime  time.Time `gorm:"type:datetime"`
+	ModifyTime  time.Time `gorm:"type:datetime"`
+}
+
+func (l *LoginLockout) TableName() string {
+	return "login_lockout"
+}
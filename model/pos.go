@@ -0,0 +1,44 @@
+package model
+
+import (
+	"github.com/shop_management/util"
+	"time"
+)
+
+type PosShift struct {
+	BaseModel
+	ID             string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID         string     `gorm:"type:varchar(36);index"`
+	UserID         string     `gorm:"type:varchar(36)"`
+	Status         int        `gorm:"type:int"`
+	Currency       string     `gorm:"type:varchar(8)"`
+	OpeningCash    util.Money `gorm:"type:decimal(10,2)"`
+	ClosingCash    util.Money `gorm:"type:decimal(10,2)"`
+	ExpectedCash   util.Money `gorm:"type:decimal(10,2)"`
+	CashDifference util.Money `gorm:"type:decimal(10,2)"`
+	OpenTime       time.Time  `gorm:"type:datetime"`
+	CloseTime      *time.Time `gorm:"type:datetime"`
+	CreateTime     time.Time  `gorm:"type:datetime"`
+	ModifyTime     time.Time  `gorm:"type:datetime"`
+}
+
+func (p *PosShift) TableName() string {
+	return "pos_shift"
+}
+
+type PosPayment struct {
+	BaseModel
+	ID           string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID       string     `gorm:"type:varchar(36);index"`
+	ShiftID      string     `gorm:"type:varchar(36)"`
+	OrderID      string     `gorm:"type:varchar(36)"`
+	TenderType   string     `gorm:"type:varchar(32)"`
+	Amount       util.Money `gorm:"type:decimal(10,2)"`
+	ChangeAmount util.Money `gorm:"type:decimal(10,2)"`
+	CreateTime   time.Time  `gorm:"type:datetime"`
+	ModifyTime   time.Time  `gorm:"type:datetime"`
+}
+
+func (p *PosPayment) TableName() string {
+	return "pos_payment"
+}
@@ -1,24 +1,34 @@
 package model
 
-import "time"
+import (
+	"github.com/shop_management/util"
+	"time"
+)
 
 type Product struct {
 	BaseModel
-	ID               string    `gorm:"type:varchar(36);primaryKey"`
-	ImageURL         string    `gorm:"type:text"`
-	StorageCode      string    `gorm:"type:varchar(255)"`
-	StoragePos       string    `gorm:"type:varchar(255)"`
-	Name             string    `gorm:"type:varchar(255)"`
-	Color            string    `gorm:"type:varchar(255)"`
-	BasePrice        float64   `gorm:"type:decimal(10,2)"`
-	CostPrice        float64   `gorm:"type:decimal(10,2)"`
-	PurchasePrice    float64   `gorm:"type:decimal(10,2)"`
-	Factory          string    `gorm:"type:varchar(255)"`
-	Stock            int       `gorm:"type:int"`
-	InProductionNums int       `gorm:"type:int"`
-	InOrderNums      int       `gorm:"type:int"`
-	CreateTime       time.Time `gorm:"type:datetime"`
-	ModifyTime       time.Time `gorm:"type:datetime"`
+	ID               string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID           string     `gorm:"type:varchar(36);index"`
+	ImageURL         string     `gorm:"type:text"`
+	StorageCode      string     `gorm:"type:varchar(255)"`
+	Barcode          string     `gorm:"type:varchar(64)"`
+	StoragePos       string     `gorm:"type:varchar(255)"`
+	Warehouse        string     `gorm:"type:varchar(64);index"`
+	Name             string     `gorm:"type:varchar(255)"`
+	Color            string     `gorm:"type:varchar(255)"`
+	Category         string     `gorm:"type:varchar(255)"`
+	BasePrice        util.Money `gorm:"type:decimal(10,2)"`
+	SaleCurrency     string     `gorm:"type:varchar(8)"`
+	CostPrice        util.Money `gorm:"type:decimal(10,2)"`
+	PurchasePrice    util.Money `gorm:"type:decimal(10,2)"`
+	PurchaseCurrency string     `gorm:"type:varchar(8)"`
+	Factory          string     `gorm:"type:varchar(255)"`
+	Stock            int        `gorm:"type:int"`
+	InProductionNums int        `gorm:"type:int"`
+	InOrderNums      int        `gorm:"type:int"`
+	HeldNums         int        `gorm:"type:int"`
+	CreateTime       time.Time  `gorm:"type:datetime"`
+	ModifyTime       time.Time  `gorm:"type:datetime"`
 }
 
 func (p *Product) TableName() string {
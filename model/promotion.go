@@ -0,0 +1,48 @@
+package model
+
+import (
+	"github.com/shop_management/util"
+	"time"
+)
+
+type Promotion struct {
+	BaseModel
+	ID         string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID     string    `gorm:"type:varchar(36);index"`
+	UserID     string    `gorm:"type:varchar(36)"`
+	Name       string    `gorm:"type:varchar(255)"`
+	Type       int       `gorm:"type:int"`
+	Category   string    `gorm:"type:varchar(255)"`
+	Rule       string    `gorm:"type:text"`
+	CouponCode string    `gorm:"type:varchar(64)"`
+	StartTime  time.Time `gorm:"type:datetime"`
+	EndTime    time.Time `gorm:"type:datetime"`
+	UsageLimit int       `gorm:"type:int"`
+	UsedCount  int       `gorm:"type:int"`
+	Stackable  bool      `gorm:"type:tinyint(1)"`
+	Priority   int       `gorm:"type:int"`
+	Status     int       `gorm:"type:int"`
+	CreateTime time.Time `gorm:"type:datetime"`
+	ModifyTime time.Time `gorm:"type:datetime"`
+}
+
+func (p *Promotion) TableName() string {
+	return "promotion"
+}
+
+type CouponRedemption struct {
+	BaseModel
+	ID          string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID      string     `gorm:"type:varchar(36);index"`
+	PromotionID string     `gorm:"type:varchar(36);uniqueIndex:uk_promotion_customer"`
+	CustomerID  string     `gorm:"type:varchar(64);uniqueIndex:uk_promotion_customer"`
+	OrderID     string     `gorm:"type:varchar(36)"`
+	CouponCode  string     `gorm:"type:varchar(64)"`
+	Amount      util.Money `gorm:"type:decimal(10,2)"`
+	CreateTime  time.Time  `gorm:"type:datetime"`
+	ModifyTime  time.Time  `gorm:"type:datetime"`
+}
+
+func (c *CouponRedemption) TableName() string {
+	return "coupon_redemption"
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"github.com/shop_management/util"
+	"time"
+)
+
+type Quotation struct {
+	BaseModel
+	ID           string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID       string     `gorm:"type:varchar(36);index"`
+	QuotationNo  string     `gorm:"type:varchar(64)"`
+	Version      int        `gorm:"type:int"`
+	UserID       string     `gorm:"type:varchar(36)"`
+	CustomerID   string     `gorm:"type:varchar(64)"`
+	CustomerName string     `gorm:"type:varchar(255)"`
+	Remark       string     `gorm:"type:varchar(255)"`
+	Status       int        `gorm:"type:int"`
+	Currency     string     `gorm:"type:varchar(8)"`
+	HoldStock    bool       `gorm:"type:tinyint(1)"`
+	TotalAmount  util.Money `gorm:"type:decimal(10,2)"`
+	NetAmount    util.Money `gorm:"type:decimal(10,2)"`
+	TaxAmount    util.Money `gorm:"type:decimal(10,2)"`
+	ValidUntil   time.Time  `gorm:"type:datetime"`
+	OrderID      string     `gorm:"type:varchar(36)"`
+	CreateTime   time.Time  `gorm:"type:datetime"`
+	ModifyTime   time.Time  `gorm:"type:datetime"`
+}
+
+func (q *Quotation) TableName() string {
+	return "quotation"
+}
+
+type QuotationItem struct {
+	BaseModel
+	ID          string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID      string     `gorm:"type:varchar(36);index"`
+	QuotationID string     `gorm:"type:varchar(36)"`
+	ProductID   string     `gorm:"type:varchar(36)"`
+	Quantity    int        `gorm:"type:int"`
+	UnitPrice   util.Money `gorm:"type:decimal(10,2)"`
+	Amount      util.Money `gorm:"type:decimal(10,2)"`
+	TaxRate     float64    `gorm:"type:decimal(6,3)"`
+	NetAmount   util.Money `gorm:"type:decimal(10,2)"`
+	TaxAmount   util.Money `gorm:"type:decimal(10,2)"`
+	CreateTime  time.Time  `gorm:"type:datetime"`
+	ModifyTime  time.Time  `gorm:"type:datetime"`
+}
+
+func (q *QuotationItem) TableName() string {
+	return "quotation_item"
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"github.com/shop_management/util"
+	"time"
+)
+
+type SalesOrder struct {
+	BaseModel
+	ID             string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID         string     `gorm:"type:varchar(36);index"`
+	OrderNo        string     `gorm:"type:varchar(64)"`
+	UserID         string     `gorm:"type:varchar(36)"`
+	CustomerID     string     `gorm:"type:varchar(64)"`
+	CustomerName   string     `gorm:"type:varchar(255)"`
+	Remark         string     `gorm:"type:varchar(255)"`
+	Status         int        `gorm:"type:int"`
+	Currency       string     `gorm:"type:varchar(8)"`
+	TotalAmount    util.Money `gorm:"type:decimal(10,2)"`
+	DiscountAmount util.Money `gorm:"type:decimal(10,2)"`
+	NetAmount      util.Money `gorm:"type:decimal(10,2)"`
+	TaxAmount      util.Money `gorm:"type:decimal(10,2)"`
+	ConfirmTime    *time.Time `gorm:"type:datetime"`
+	CreateTime     time.Time  `gorm:"type:datetime"`
+	ModifyTime     time.Time  `gorm:"type:datetime"`
+}
+
+func (s *SalesOrder) TableName() string {
+	return "sales_order"
+}
+
+type SalesOrderItem struct {
+	BaseModel
+	ID             string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID         string     `gorm:"type:varchar(36);index"`
+	OrderID        string     `gorm:"type:varchar(36)"`
+	ProductID      string     `gorm:"type:varchar(36)"`
+	Quantity       int        `gorm:"type:int"`
+	UnitPrice      util.Money `gorm:"type:decimal(10,2)"`
+	DiscountAmount util.Money `gorm:"type:decimal(10,2)"`
+	Amount         util.Money `gorm:"type:decimal(10,2)"`
+	TaxRate        float64    `gorm:"type:decimal(6,3)"`
+	NetAmount      util.Money `gorm:"type:decimal(10,2)"`
+	TaxAmount      util.Money `gorm:"type:decimal(10,2)"`
+	CreateTime     time.Time  `gorm:"type:datetime"`
+	ModifyTime     time.Time  `gorm:"type:datetime"`
+}
+
+func (s *SalesOrderItem) TableName() string {
+	return "sales_order_item"
+}
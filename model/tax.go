@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+type TaxRule struct {
+	BaseModel
+	ID         string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID     string    `gorm:"type:varchar(36);index"`
+	UserID     string    `gorm:"type:varchar(36);uniqueIndex:uk_user_category"`
+	Name       string    `gorm:"type:varchar(255)"`
+	Category   string    `gorm:"type:varchar(255);uniqueIndex:uk_user_category"`
+	Rate       float64   `gorm:"type:decimal(6,3)"`
+	CreateTime time.Time `gorm:"type:datetime"`
+	ModifyTime time.Time `gorm:"type:datetime"`
+}
+
+func (t *TaxRule) TableName() string {
+	return "tax_rule"
+}
+
+type TaxSetting struct {
+	BaseModel
+	ID               string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID           string    `gorm:"type:varchar(36);index"`
+	UserID           string    `gorm:"type:varchar(36);uniqueIndex"`
+	PriceIncludesTax bool      `gorm:"type:tinyint(1)"`
+	CreateTime       time.Time `gorm:"type:datetime"`
+	ModifyTime       time.Time `gorm:"type:datetime"`
+}
+
+func (t *TaxSetting) TableName() string {
+	return "tax_setting"
+}
+
+type TaxExemption struct {
+	BaseModel
+	ID         string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID     string    `gorm:"type:varchar(36);index"`
+	UserID     string    `gorm:"type:varchar(36);uniqueIndex:uk_user_customer"`
+	CustomerID string    `gorm:"type:varchar(64);uniqueIndex:uk_user_customer"`
+	Reason     string    `gorm:"type:varchar(255)"`
+	CreateTime time.Time `gorm:"type:datetime"`
+	ModifyTime time.Time `gorm:"type:datetime"`
+}
+
+func (t *TaxExemption) TableName() string {
+	return "tax_exemption"
+}
@@ -4,14 +4,23 @@ import "time"
 
 type User struct {
 	BaseModel
-	Id         string
-	Name       string
-	Email      string
-	Password   string
-	AvatarUrl  string
-	Phone      string
-	CreateTime time.Time
-	ModifyTime time.Time
+	Id        string
+	Name      string
+	Email     string
+	Password  string
+	AvatarUrl string
+	Phone     string
+	// DeletedTime 停用时间, 停用的账号在保留期过后由定时任务清除
+	DeletedTime *time.Time
+	// DeactivatedTeamId 停用前所在团队(团队所有者的用户id), 由该团队所有者恢复
+	DeactivatedTeamId string
+	DeactivatedRole   string
+	// DisabledTime 不为空表示被平台管理员禁用, 禁用期间不能登录
+	DisabledTime *time.Time
+	// PlatformAdmin 平台管理员, 只能在数据库中设置
+	PlatformAdmin bool
+	CreateTime    time.Time
+	ModifyTime    time.Time
 }
 
 func (u *User) TableName() string {
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// UserGrant 子账号在具体资源上的授权, 同一子账号的同一授权只保存一条
+type UserGrant struct {
+	BaseModel
+	ID           string    `gorm:"type:varchar(36);primaryKey"`
+	TeamID       string    `gorm:"type:varchar(36);index"`
+	UserID       string    `gorm:"type:varchar(36);uniqueIndex:uk_user_grant"`
+	ResourceType string    `gorm:"type:varchar(32);uniqueIndex:uk_user_grant"`
+	ResourceID   string    `gorm:"type:varchar(64);uniqueIndex:uk_user_grant"`
+	Action       string    `gorm:"type:varchar(32);uniqueIndex:uk_user_grant"`
+	CreateTime   time.Time `gorm:"type:datetime"`
+	ModifyTime   time.Time `gorm:"type:datetime"`
+}
+
+func (u *UserGrant) TableName() string {
+	return "user_grant"
+}
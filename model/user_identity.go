@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// UserIdentity 用户在外部身份提供方(OIDC)的账号, 同一提供方以 Issuer + Subject 唯一确定
+type UserIdentity struct {
+	BaseModel
+	ID         string    `gorm:"type:varchar(36);primaryKey"`
+	UserID     string    `gorm:"type:varchar(36);index"`
+	Issuer     string    `gorm:"type:varchar(255);uniqueIndex:uk_identity_subject"`
+	Subject    string    `gorm:"type:varchar(255);uniqueIndex:uk_identity_subject"`
+	Email      string    `gorm:"type:varchar(255)"`
+	Phone      string    `gorm:"type:varchar(32)"`
+	CreateTime time.Time `gorm:"type:datetime"`
+	ModifyTime time.Time `gorm:"type:datetime"`
+}
+
+func (u *UserIdentity) TableName() string {
+	return "user_identity"
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// UserInvitation 团队邀请, 按团队所有者记录, 不参与团队数据隔离(被邀请人需要跨团队读取)
+type UserInvitation struct {
+	BaseModel
+	ID         string    `gorm:"type:varchar(36);primaryKey"`
+	UserID     string    `gorm:"type:varchar(36);index"`
+	Phone      string    `gorm:"type:varchar(32)"`
+	Email      string    `gorm:"type:varchar(255)"`
+	Role       string    `gorm:"type:varchar(32)"`
+	Status     int       `gorm:"type:int"`
+	InviteeID  string    `gorm:"type:varchar(36)"`
+	ExpireTime time.Time `gorm:"type:datetime"`
+	CreateTime time.Time `gorm:"type:datetime"`
+	ModifyTime time.Time `gorm:"type:datetime"`
+}
+
+func (u *UserInvitation) TableName() string {
+	return "user_invitation"
+}
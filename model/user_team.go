@@ -7,6 +7,7 @@ type UserTeam struct {
 	ID         string    `json:"id"`
 	UserID     string    `json:"user_id"`
 	SubUserID  string    `json:"sub_user_id"`
+	Role       string    `json:"role" gorm:"type:varchar(32)"`
 	CreateTime time.Time `json:"create_time"`
 	ModifyTime time.Time `json:"modify_time"`
 }
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// UserTwoFactor 用户的 TOTP 两步验证配置, Secret 为加密后的密钥
+type UserTwoFactor struct {
+	BaseModel
+	ID         string    `gorm:"type:varchar(36);primaryKey"`
+	UserID     string    `gorm:"type:varchar(36);uniqueIndex"`
+	Secret     string    `gorm:"type:varchar(255)"`
+	Status     int       `gorm:"type:int"`
+	CreateTime time.Time `gorm:"type:datetime"`
+	ModifyTime time.Time `gorm:"type:datetime"`
+}
+
+func (u *UserTwoFactor) TableName() string {
+	return "user_two_factor"
+}
+
+// UserRecoveryCode 两步验证的一次性恢复码, 只保存哈希
+type UserRecoveryCode struct {
+	BaseModel
+	ID         string     `gorm:"type:varchar(36);primaryKey"`
+	UserID     string     `gorm:"type:varchar(36);index"`
+	CodeHash   string     `gorm:"type:varchar(64)"`
+	UsedTime   *time.Time `gorm:"type:datetime"`
+	CreateTime time.Time  `gorm:"type:datetime"`
+	ModifyTime time.Time  `gorm:"type:datetime"`
+}
+
+func (u *UserRecoveryCode) TableName() string {
+	return "user_recovery_code"
+}
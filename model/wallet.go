@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/shop_management/util"
+	"time"
+)
+
+// Wallet 预付费钱包, Balance 是流水金额之和的缓存, 与流水在同一事务中更新
+type Wallet struct {
+	BaseModel
+	ID         string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID     string     `gorm:"type:varchar(36);uniqueIndex:uk_wallet_owner"`
+	OwnerType  string     `gorm:"type:varchar(16);uniqueIndex:uk_wallet_owner"`
+	OwnerID    string     `gorm:"type:varchar(64);uniqueIndex:uk_wallet_owner"`
+	Currency   string     `gorm:"type:varchar(8)"`
+	Balance    util.Money `gorm:"type:decimal(12,2)"`
+	CreateTime time.Time  `gorm:"type:datetime"`
+	ModifyTime time.Time  `gorm:"type:datetime"`
+}
+
+func (w *Wallet) TableName() string {
+	return "wallet"
+}
+
+// WalletEntry 钱包流水, 只新增不修改. 充值和退款的金额为正, 扣款为负
+type WalletEntry struct {
+	BaseModel
+	ID           string     `gorm:"type:varchar(36);primaryKey"`
+	TeamID       string     `gorm:"type:varchar(36);index"`
+	WalletID     string     `gorm:"type:varchar(36);index:idx_wallet_entry_time"`
+	Type         string     `gorm:"type:varchar(16)"`
+	Amount       util.Money `gorm:"type:decimal(12,2)"`
+	BalanceAfter util.Money `gorm:"type:decimal(12,2)"`
+	// RefEntryID 退款对应的扣款流水
+	RefEntryID string    `gorm:"type:varchar(36);index"`
+	Reference  string    `gorm:"type:varchar(64)"`
+	Remark     string    `gorm:"type:varchar(255)"`
+	OperatorID string    `gorm:"type:varchar(36)"`
+	CreateTime time.Time `gorm:"type:datetime;index:idx_wallet_entry_time"`
+	ModifyTime time.Time `gorm:"type:datetime"`
+}
+
+func (w *WalletEntry) TableName() string {
+	return "wallet_entry"
+}
@@ -0,0 +1,51 @@
+package admin_po
+
+import (
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/user_po"
+)
+
+type UserListReq struct {
+	Keyword string `form:"keyword"`
+}
+
+type User struct {
+	Id              string `json:"id"`
+	Name            string `json:"name"`
+	Phone           string `json:"phone"`
+	Email           string `json:"email"`
+	AvatarUrl       string `json:"avatar_url"`
+	PlatformAdmin   bool   `json:"platform_admin"`
+	Disabled        bool   `json:"disabled"`
+	DisabledTime    string `json:"disabled_time"`
+	Deactivated     bool   `json:"deactivated"`
+	DeactivatedTime string `json:"deactivated_time"`
+	CreateTime      string `json:"create_time"`
+}
+
+type UserListResp struct {
+	Pager *common_po.Pager `json:"pager"`
+	List  []*User          `json:"list"`
+}
+
+type UserReq struct {
+	UserId string `form:"user_id" json:"user_id" binding:"required"`
+}
+
+type UserTeam struct {
+	OwnerId     string `json:"owner_id"`
+	OwnerName   string `json:"owner_name"`
+	Role        string `json:"role"`
+	MemberCount int64  `json:"member_count"`
+}
+
+type UserDetailResp struct {
+	User     *User              `json:"user"`
+	Team     *UserTeam          `json:"team"`
+	Sessions []*user_po.Session `json:"sessions"`
+}
+
+type DisableUserReq struct {
+	UserId string `json:"user_id" binding:"required"`
+	Reason string `json:"reason" binding:"required,max=200"`
+}
@@ -0,0 +1,25 @@
+package api_key_po
+
+type CreateApiKeyReq struct {
+	Name   string   `json:"name" binding:"required,max=255"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+type ApiKeyIdReq struct {
+	ID string `json:"id" binding:"required"`
+}
+
+type ApiKey struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	AccessKey    string   `json:"access_key"`
+	Secret       string   `json:"secret,omitempty"`
+	Scopes       []string `json:"scopes"`
+	Status       int      `json:"status"`
+	LastUsedTime string   `json:"last_used_time"`
+	CreateTime   string   `json:"create_time"`
+}
+
+type ApiKeyListResp struct {
+	List []*ApiKey `json:"list"`
+}
@@ -0,0 +1,35 @@
+package audit_po
+
+import "github.com/shop_management/po/common_po"
+
+// AuditListReq 日期格式 2006-01-02, 结束日期包含当天
+type AuditListReq struct {
+	ActorId    string `form:"actor_id"`
+	Action     string `form:"action" binding:"omitempty,oneof=create update delete search view disable enable impersonate"`
+	EntityType string `form:"entity_type"`
+	EntityId   string `form:"entity_id"`
+	RequestId  string `form:"request_id"`
+	StartDate  string `form:"start_date"`
+	EndDate    string `form:"end_date"`
+}
+
+type AuditLog struct {
+	Id        string `json:"id"`
+	ActorId   string `json:"actor_id"`
+	ActorName string `json:"actor_name"`
+	// ImpersonatorId 平台管理员模拟登录时的实际操作人
+	ImpersonatorId string `json:"impersonator_id"`
+	Action         string `json:"action"`
+	EntityType     string `json:"entity_type"`
+	EntityId       string `json:"entity_id"`
+	Before         string `json:"before"`
+	After          string `json:"after"`
+	Ip             string `json:"ip"`
+	RequestId      string `json:"request_id"`
+	CreateTime     string `json:"create_time"`
+}
+
+type AuditListResp struct {
+	Pager *common_po.Pager `json:"pager"`
+	List  []*AuditLog      `json:"list"`
+}
@@ -0,0 +1,38 @@
+package currency_po
+
+type BaseCurrency struct {
+	BaseCurrency string `json:"base_currency" binding:"required"`
+}
+
+type SaveRateReq struct {
+	FromCurrency  string  `json:"from_currency" binding:"required"`
+	ToCurrency    string  `json:"to_currency" binding:"required"`
+	Rate          float64 `json:"rate" binding:"required"`
+	EffectiveDate string  `json:"effective_date" binding:"required"`
+}
+
+type ImportRatesReq struct {
+	Url string `json:"url" binding:"required"`
+}
+
+type ImportRatesResp struct {
+	Imported int `json:"imported"`
+}
+
+type RateListReq struct {
+	FromCurrency string `form:"from_currency"`
+	ToCurrency   string `form:"to_currency"`
+}
+
+type ExchangeRate struct {
+	ID            string  `json:"id"`
+	FromCurrency  string  `json:"from_currency"`
+	ToCurrency    string  `json:"to_currency"`
+	Rate          float64 `json:"rate"`
+	EffectiveDate string  `json:"effective_date"`
+	Source        int     `json:"source"`
+}
+
+type RateListResp struct {
+	List []*ExchangeRate `json:"list"`
+}
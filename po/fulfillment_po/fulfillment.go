@@ -0,0 +1,75 @@
+package fulfillment_po
+
+type PickTaskIdReq struct {
+	TaskID string `json:"task_id" binding:"required"`
+}
+
+type PickTaskListReq struct {
+	OrderID string `form:"order_id"`
+	Status  int    `form:"status"`
+}
+
+type PickTaskItem struct {
+	ID          string `json:"id"`
+	OrderItemID string `json:"order_item_id"`
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	StorageCode string `json:"storage_code"`
+	Quantity    int    `json:"quantity"`
+}
+
+type PickTask struct {
+	ID         string          `json:"id"`
+	OrderID    string          `json:"order_id"`
+	Warehouse  string          `json:"warehouse"`
+	StoragePos string          `json:"storage_pos"`
+	Status     int             `json:"status"`
+	AssigneeID string          `json:"assignee_id"`
+	ClaimTime  string          `json:"claim_time"`
+	PickedTime string          `json:"picked_time"`
+	Items      []*PickTaskItem `json:"items"`
+}
+
+type PickTaskListResp struct {
+	List []*PickTask `json:"list"`
+}
+
+type PackReq struct {
+	OrderID      string   `json:"order_id" binding:"required"`
+	StorageCodes []string `json:"storage_codes" binding:"required,min=1"`
+}
+
+type PackResp struct {
+	ID       string `json:"id"`
+	OrderID  string `json:"order_id"`
+	PackerID string `json:"packer_id"`
+	PackTime string `json:"pack_time"`
+}
+
+type ShipReq struct {
+	OrderID    string `json:"order_id" binding:"required"`
+	Carrier    string `json:"carrier" binding:"required,max=64"`
+	TrackingNo string `json:"tracking_no" binding:"required,max=128"`
+}
+
+type ShipResp struct {
+	ID         string `json:"id"`
+	OrderID    string `json:"order_id"`
+	Carrier    string `json:"carrier"`
+	TrackingNo string `json:"tracking_no"`
+	ShipperID  string `json:"shipper_id"`
+	ShipTime   string `json:"ship_time"`
+}
+
+type TimelineResp struct {
+	OrderID        string `json:"order_id"`
+	ConfirmTime    string `json:"confirm_time"`
+	FirstClaimTime string `json:"first_claim_time"`
+	LastPickedTime string `json:"last_picked_time"`
+	PackTime       string `json:"pack_time"`
+	ShipTime       string `json:"ship_time"`
+	PickSeconds    int64  `json:"pick_seconds"`
+	PackSeconds    int64  `json:"pack_seconds"`
+	ShipSeconds    int64  `json:"ship_seconds"`
+	TotalSeconds   int64  `json:"total_seconds"`
+}
@@ -0,0 +1,77 @@
+package order_po
+
+import (
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/util"
+)
+
+type AddOrderItemReq struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
+}
+
+type AddOrderReq struct {
+	CustomerID   string             `json:"customer_id"`
+	CustomerName string             `json:"customer_name"`
+	Remark       string             `json:"remark"`
+	CouponCodes  []string           `json:"coupon_codes"`
+	Items        []*AddOrderItemReq `json:"items" binding:"required,min=1,dive"`
+}
+
+type OrderIdReq struct {
+	OrderID string `json:"order_id" form:"order_id" binding:"required"`
+}
+
+type OrderListReq struct {
+	Status int `form:"status"`
+}
+
+type SalesOrderItem struct {
+	ID             string     `json:"id"`
+	ProductID      string     `json:"product_id"`
+	Quantity       int        `json:"quantity"`
+	UnitPrice      util.Money `json:"unit_price"`
+	DiscountAmount util.Money `json:"discount_amount"`
+	Amount         util.Money `json:"amount"`
+	TaxRate        float64    `json:"tax_rate"`
+	NetAmount      util.Money `json:"net_amount"`
+	TaxAmount      util.Money `json:"tax_amount"`
+}
+
+type SalesOrder struct {
+	ID             string            `json:"id"`
+	OrderNo        string            `json:"order_no"`
+	UserID         string            `json:"user_id"`
+	CustomerID     string            `json:"customer_id"`
+	CustomerName   string            `json:"customer_name"`
+	Remark         string            `json:"remark"`
+	Status         int               `json:"status"`
+	Currency       string            `json:"currency"`
+	TotalAmount    util.Money        `json:"total_amount"`
+	DiscountAmount util.Money        `json:"discount_amount"`
+	NetAmount      util.Money        `json:"net_amount"`
+	TaxAmount      util.Money        `json:"tax_amount"`
+	ConfirmTime    string            `json:"confirm_time"`
+	CreateTime     string            `json:"create_time"`
+	Items          []*SalesOrderItem `json:"items,omitempty"`
+}
+
+type TaxBreakdown struct {
+	TaxRate   float64    `json:"tax_rate"`
+	NetAmount util.Money `json:"net_amount"`
+	TaxAmount util.Money `json:"tax_amount"`
+}
+
+type Invoice struct {
+	InvoiceNo    string          `json:"invoice_no"`
+	Order        *SalesOrder     `json:"order"`
+	NetAmount    util.Money      `json:"net_amount"`
+	TaxAmount    util.Money      `json:"tax_amount"`
+	TotalAmount  util.Money      `json:"total_amount"`
+	TaxBreakdown []*TaxBreakdown `json:"tax_breakdown"`
+}
+
+type OrderListResp struct {
+	Pager *common_po.Pager `json:"pager"`
+	List  []*SalesOrder    `json:"list"`
+}
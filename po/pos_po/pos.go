@@ -0,0 +1,63 @@
+package pos_po
+
+import (
+	"github.com/shop_management/po/promotion_po"
+	"github.com/shop_management/util"
+)
+
+type OpenShiftReq struct {
+	OpeningCash util.Money `json:"opening_cash" binding:"min=0"`
+}
+
+type CloseShiftReq struct {
+	ClosingCash util.Money `json:"closing_cash" binding:"min=0"`
+}
+
+type PosShift struct {
+	ID             string     `json:"id"`
+	UserID         string     `json:"user_id"`
+	Status         int        `json:"status"`
+	Currency       string     `json:"currency"`
+	OpeningCash    util.Money `json:"opening_cash"`
+	ClosingCash    util.Money `json:"closing_cash"`
+	ExpectedCash   util.Money `json:"expected_cash"`
+	CashDifference util.Money `json:"cash_difference"`
+	OpenTime       string     `json:"open_time"`
+	CloseTime      string     `json:"close_time"`
+}
+
+type CheckoutItem struct {
+	Code     string `json:"code" binding:"required"`
+	Quantity int    `json:"quantity"`
+}
+
+type Tender struct {
+	TenderType string     `json:"tender_type" binding:"required,oneof=cash card wechat alipay"`
+	Amount     util.Money `json:"amount" binding:"required,gt=0"`
+}
+
+type CheckoutReq struct {
+	CustomerID  string          `json:"customer_id"`
+	CouponCodes []string        `json:"coupon_codes"`
+	Items       []*CheckoutItem `json:"items" binding:"required,min=1,dive"`
+	Tenders     []*Tender       `json:"tenders" binding:"required,min=1,dive"`
+}
+
+type Receipt struct {
+	OrderID        string                       `json:"order_id"`
+	ReceiptNo      string                       `json:"receipt_no"`
+	ShiftID        string                       `json:"shift_id"`
+	CashierID      string                       `json:"cashier_id"`
+	Currency       string                       `json:"currency"`
+	Lines          []*promotion_po.EvaluateLine `json:"lines"`
+	OriginalAmount util.Money                   `json:"original_amount"`
+	DiscountAmount util.Money                   `json:"discount_amount"`
+	NetAmount      util.Money                   `json:"net_amount"`
+	TaxAmount      util.Money                   `json:"tax_amount"`
+	TaxBreakdown   []*promotion_po.TaxBreakdown `json:"tax_breakdown"`
+	TotalAmount    util.Money                   `json:"total_amount"`
+	PaidAmount     util.Money                   `json:"paid_amount"`
+	ChangeAmount   util.Money                   `json:"change_amount"`
+	Tenders        []*Tender                    `json:"tenders"`
+	CheckoutTime   string                       `json:"checkout_time"`
+}
@@ -1,17 +1,38 @@
 package product_po
 
+import (
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/util"
+)
+
 type Product struct {
-	ID               string  `json:"id,omitempty"`
-	ImageURL         string  `json:"image_url,omitempty"`
-	StorageCode      string  `json:"storage_code,omitempty"`
-	StoragePos       string  `json:"storage_pos,omitempty"`
-	Name             string  `json:"name,omitempty"`
-	Color            string  `json:"color,omitempty"`
-	BasePrice        float64 `json:"base_price,omitempty"`
-	CostPrice        float64 `json:"cost_price,omitempty"`
-	PurchasePrice    float64 `json:"purchase_price,omitempty"`
-	Factory          string  `json:"factory,omitempty"`
-	Stock            int     `json:"stock,omitempty"`
-	InProductionNums int     `json:"in_production_nums,omitempty"`
-	InOrderNums      int     `json:"in_order_nums,omitempty"`
+	ID               string     `json:"id,omitempty"`
+	ImageURL         string     `json:"image_url,omitempty"`
+	StorageCode      string     `json:"storage_code,omitempty"`
+	Barcode          string     `json:"barcode,omitempty"`
+	StoragePos       string     `json:"storage_pos,omitempty"`
+	Warehouse        string     `json:"warehouse,omitempty"`
+	Name             string     `json:"name,omitempty"`
+	Color            string     `json:"color,omitempty"`
+	Category         string     `json:"category,omitempty"`
+	BasePrice        util.Money `json:"base_price,omitempty"`
+	SaleCurrency     string     `json:"sale_currency,omitempty"`
+	CostPrice        util.Money `json:"cost_price,omitempty"`
+	PurchasePrice    util.Money `json:"purchase_price,omitempty"`
+	PurchaseCurrency string     `json:"purchase_currency,omitempty"`
+	Factory          string     `json:"factory,omitempty"`
+	Stock            int        `json:"stock,omitempty"`
+	InProductionNums int        `json:"in_production_nums,omitempty"`
+	InOrderNums      int        `json:"in_order_nums,omitempty"`
+	HeldNums         int        `json:"held_nums,omitempty"`
+}
+
+type ProductListReq struct {
+	Category  string `form:"category"`
+	Warehouse string `form:"warehouse"`
+}
+
+type ProductListResp struct {
+	Pager *common_po.Pager `json:"pager"`
+	List  []*Product       `json:"list"`
 }
@@ -0,0 +1,116 @@
+package promotion_po
+
+import "github.com/shop_management/util"
+
+type PromotionTier struct {
+	MinQuantity int     `json:"min_quantity"`
+	Percent     float64 `json:"percent"`
+}
+
+type PromotionRule struct {
+	Percent        float64          `json:"percent,omitempty"`
+	BuyQuantity    int              `json:"buy_quantity,omitempty"`
+	FreeQuantity   int              `json:"free_quantity,omitempty"`
+	Tiers          []*PromotionTier `json:"tiers,omitempty"`
+	Amount         util.Money       `json:"amount,omitempty"`
+	MinOrderAmount util.Money       `json:"min_order_amount,omitempty"`
+}
+
+type AddPromotionReq struct {
+	Name       string         `json:"name" binding:"required,max=255"`
+	Type       int            `json:"type" binding:"required,oneof=1 2 3 4"`
+	Category   string         `json:"category"`
+	Rule       *PromotionRule `json:"rule" binding:"required"`
+	CouponCode string         `json:"coupon_code" binding:"max=64"`
+	StartTime  string         `json:"start_time" binding:"required"`
+	EndTime    string         `json:"end_time" binding:"required"`
+	UsageLimit int            `json:"usage_limit" binding:"min=0"`
+	Stackable  bool           `json:"stackable"`
+	Priority   int            `json:"priority"`
+}
+
+type PromotionIdReq struct {
+	ID string `json:"id" binding:"required"`
+}
+
+type Promotion struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Type       int            `json:"type"`
+	Category   string         `json:"category"`
+	Rule       *PromotionRule `json:"rule"`
+	CouponCode string         `json:"coupon_code"`
+	StartTime  string         `json:"start_time"`
+	EndTime    string         `json:"end_time"`
+	UsageLimit int            `json:"usage_limit"`
+	UsedCount  int            `json:"used_count"`
+	Stackable  bool           `json:"stackable"`
+	Priority   int            `json:"priority"`
+	Status     int            `json:"status"`
+}
+
+type PromotionListResp struct {
+	List []*Promotion `json:"list"`
+}
+
+type EvaluateItem struct {
+	ProductID string     `json:"product_id" binding:"required"`
+	Quantity  int        `json:"quantity" binding:"required,min=1"`
+	UnitPrice util.Money `json:"unit_price"`
+}
+
+type EvaluateReq struct {
+	CustomerID  string          `json:"customer_id"`
+	CouponCodes []string        `json:"coupon_codes"`
+	Items       []*EvaluateItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type AppliedDiscount struct {
+	PromotionID   string     `json:"promotion_id"`
+	PromotionName string     `json:"promotion_name"`
+	Type          int        `json:"type"`
+	CouponCode    string     `json:"coupon_code,omitempty"`
+	Amount        util.Money `json:"amount"`
+}
+
+type EvaluateLine struct {
+	ProductID      string             `json:"product_id"`
+	ProductName    string             `json:"product_name"`
+	Category       string             `json:"category"`
+	Quantity       int                `json:"quantity"`
+	UnitPrice      util.Money         `json:"unit_price"`
+	OriginalAmount util.Money         `json:"original_amount"`
+	DiscountAmount util.Money         `json:"discount_amount"`
+	FinalAmount    util.Money         `json:"final_amount"`
+	Discounts      []*AppliedDiscount `json:"discounts"`
+	TaxRate        float64            `json:"tax_rate"`
+	NetAmount      util.Money         `json:"net_amount"`
+	TaxAmount      util.Money         `json:"tax_amount"`
+	GrossAmount    util.Money         `json:"gross_amount"`
+}
+
+type RejectedCoupon struct {
+	CouponCode string `json:"coupon_code"`
+	Reason     string `json:"reason"`
+}
+
+type EvaluateResp struct {
+	Currency         string            `json:"currency"`
+	Lines            []*EvaluateLine   `json:"lines"`
+	OriginalAmount   util.Money        `json:"original_amount"`
+	DiscountAmount   util.Money        `json:"discount_amount"`
+	FinalAmount      util.Money        `json:"final_amount"`
+	RejectedCoupons  []*RejectedCoupon `json:"rejected_coupons"`
+	PriceIncludesTax bool              `json:"price_includes_tax"`
+	TaxExempt        bool              `json:"tax_exempt"`
+	NetAmount        util.Money        `json:"net_amount"`
+	TaxAmount        util.Money        `json:"tax_amount"`
+	GrossAmount      util.Money        `json:"gross_amount"`
+	TaxBreakdown     []*TaxBreakdown   `json:"tax_breakdown"`
+}
+
+type TaxBreakdown struct {
+	TaxRate   float64    `json:"tax_rate"`
+	NetAmount util.Money `json:"net_amount"`
+	TaxAmount util.Money `json:"tax_amount"`
+}
@@ -0,0 +1,75 @@
+package quotation_po
+
+import (
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/util"
+)
+
+type SaveQuotationItemReq struct {
+	ProductID string     `json:"product_id" binding:"required"`
+	Quantity  int        `json:"quantity" binding:"required,min=1"`
+	UnitPrice util.Money `json:"unit_price"`
+}
+
+type AddQuotationReq struct {
+	CustomerID   string                  `json:"customer_id"`
+	CustomerName string                  `json:"customer_name"`
+	Remark       string                  `json:"remark"`
+	HoldStock    bool                    `json:"hold_stock"`
+	ValidUntil   string                  `json:"valid_until" binding:"required"`
+	Items        []*SaveQuotationItemReq `json:"items" binding:"required,min=1,dive"`
+}
+
+type ReviseQuotationReq struct {
+	QuotationID string `json:"quotation_id" binding:"required"`
+	AddQuotationReq
+}
+
+type QuotationIdReq struct {
+	QuotationID string `json:"quotation_id" form:"quotation_id" binding:"required"`
+}
+
+type QuotationListReq struct {
+	QuotationNo string `form:"quotation_no"`
+	Status      int    `form:"status"`
+}
+
+type QuotationItem struct {
+	ID        string     `json:"id"`
+	ProductID string     `json:"product_id"`
+	Quantity  int        `json:"quantity"`
+	UnitPrice util.Money `json:"unit_price"`
+	Amount    util.Money `json:"amount"`
+	TaxRate   float64    `json:"tax_rate"`
+	NetAmount util.Money `json:"net_amount"`
+	TaxAmount util.Money `json:"tax_amount"`
+}
+
+type Quotation struct {
+	ID           string           `json:"id"`
+	QuotationNo  string           `json:"quotation_no"`
+	Version      int              `json:"version"`
+	UserID       string           `json:"user_id"`
+	CustomerID   string           `json:"customer_id"`
+	CustomerName string           `json:"customer_name"`
+	Remark       string           `json:"remark"`
+	Status       int              `json:"status"`
+	Currency     string           `json:"currency"`
+	HoldStock    bool             `json:"hold_stock"`
+	TotalAmount  util.Money       `json:"total_amount"`
+	NetAmount    util.Money       `json:"net_amount"`
+	TaxAmount    util.Money       `json:"tax_amount"`
+	ValidUntil   string           `json:"valid_until"`
+	OrderID      string           `json:"order_id"`
+	CreateTime   string           `json:"create_time"`
+	Items        []*QuotationItem `json:"items,omitempty"`
+}
+
+type QuotationListResp struct {
+	Pager *common_po.Pager `json:"pager"`
+	List  []*Quotation     `json:"list"`
+}
+
+type QuotationVersionsResp struct {
+	List []*Quotation `json:"list"`
+}
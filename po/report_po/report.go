@@ -0,0 +1,41 @@
+package report_po
+
+import "github.com/shop_management/util"
+
+type SalesReportReq struct {
+	StartDate string `form:"start_date" binding:"required"`
+	EndDate   string `form:"end_date" binding:"required"`
+}
+
+type SalesReportDay struct {
+	Date        string     `json:"date"`
+	OrderCount  int        `json:"order_count"`
+	NetAmount   util.Money `json:"net_amount"`
+	TaxAmount   util.Money `json:"tax_amount"`
+	TotalAmount util.Money `json:"total_amount"`
+}
+
+type SalesReport struct {
+	Currency    string            `json:"currency"`
+	Days        []*SalesReportDay `json:"days"`
+	OrderCount  int               `json:"order_count"`
+	NetAmount   util.Money        `json:"net_amount"`
+	TaxAmount   util.Money        `json:"tax_amount"`
+	TotalAmount util.Money        `json:"total_amount"`
+}
+
+type InventoryValuationLine struct {
+	ProductID        string     `json:"product_id"`
+	Name             string     `json:"name"`
+	Stock            int        `json:"stock"`
+	UnitCost         util.Money `json:"unit_cost"`
+	UnitCostCurrency string     `json:"unit_cost_currency"`
+	Amount           util.Money `json:"amount"`
+}
+
+type InventoryValuation struct {
+	Currency    string                    `json:"currency"`
+	Date        string                    `json:"date"`
+	Lines       []*InventoryValuationLine `json:"lines"`
+	TotalAmount util.Money                `json:"total_amount"`
+}
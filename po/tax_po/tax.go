@@ -0,0 +1,47 @@
+package tax_po
+
+type SaveTaxRuleReq struct {
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Rate     float64 `json:"rate" binding:"min=0,max=100"`
+}
+
+type TaxRuleIdReq struct {
+	ID string `json:"id" binding:"required"`
+}
+
+type TaxRule struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Category   string  `json:"category"`
+	Rate       float64 `json:"rate"`
+	ModifyTime string  `json:"modify_time"`
+}
+
+type TaxRuleListResp struct {
+	List []*TaxRule `json:"list"`
+}
+
+type TaxSetting struct {
+	PriceIncludesTax bool `json:"price_includes_tax"`
+}
+
+type AddTaxExemptionReq struct {
+	CustomerID string `json:"customer_id" binding:"required"`
+	Reason     string `json:"reason"`
+}
+
+type CustomerIdReq struct {
+	CustomerID string `json:"customer_id" binding:"required"`
+}
+
+type TaxExemption struct {
+	ID         string `json:"id"`
+	CustomerID string `json:"customer_id"`
+	Reason     string `json:"reason"`
+	CreateTime string `json:"create_time"`
+}
+
+type TaxExemptionListResp struct {
+	List []*TaxExemption `json:"list"`
+}
@@ -0,0 +1,26 @@
+package user_po
+
+type DeactivateReq struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type DeactivateMemberReq struct {
+	Id string `json:"id" binding:"required"`
+}
+
+type ReactivateReq struct {
+	UserId string `json:"user_id" binding:"required"`
+}
+
+type DeactivatedUser struct {
+	UserId          string `json:"user_id"`
+	Name            string `json:"name"`
+	Phone           string `json:"phone"`
+	Role            string `json:"role"`
+	DeactivatedTime string `json:"deactivated_time"`
+	PurgeTime       string `json:"purge_time"`
+}
+
+type DeactivatedUserListResp struct {
+	List []*DeactivatedUser `json:"list"`
+}
@@ -0,0 +1,29 @@
+package user_po
+
+type UserGrant struct {
+	Id           string `json:"id"`
+	SubUserId    string `json:"sub_user_id"`
+	ResourceType string `json:"resource_type"`
+	ResourceId   string `json:"resource_id"`
+	Action       string `json:"action"`
+}
+
+type GrantListReq struct {
+	Id string `form:"id" binding:"required"`
+}
+
+type GrantListResp struct {
+	List []*UserGrant `json:"list"`
+}
+
+// AddGrantReq 商品功能授权可以不填 resource_id
+type AddGrantReq struct {
+	Id           string `json:"id" binding:"required"`
+	ResourceType string `json:"resource_type" binding:"required,oneof=warehouse product"`
+	ResourceId   string `json:"resource_id" binding:"max=64"`
+	Action       string `json:"action" binding:"required,oneof=read write view_cost"`
+}
+
+type DelGrantReq struct {
+	Id string `json:"id" binding:"required"`
+}
@@ -0,0 +1,10 @@
+package user_po
+
+type OidcAuthURLResp struct {
+	AuthUrl string `json:"auth_url"`
+}
+
+type OidcLoginReq struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
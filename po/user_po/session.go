@@ -0,0 +1,25 @@
+package user_po
+
+type Session struct {
+	ID        string `json:"id"`
+	UserAgent string `json:"user_agent"`
+	Ip        string `json:"ip"`
+	Current   bool   `json:"current"`
+	// Impersonated 平台管理员模拟登录的会话
+	Impersonated bool   `json:"impersonated"`
+	CreateTime   string `json:"create_time"`
+	LastSeenTime string `json:"last_seen_time"`
+	ExpireTime   string `json:"expire_time"`
+}
+
+type SessionListResp struct {
+	List []*Session `json:"list"`
+}
+
+type RevokeSessionReq struct {
+	ID string `json:"id" binding:"required"`
+}
+
+type ForceLogoutReq struct {
+	Id string `json:"id" binding:"required"`
+}
@@ -0,0 +1,35 @@
+package user_po
+
+import "github.com/shop_management/po/common_po"
+
+type InviteReq struct {
+	Phone string `json:"phone"`
+	Email string `json:"email" binding:"omitempty,email"`
+	Role  string `json:"role"`
+}
+
+type InvitationTokenReq struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+type InvitationListReq struct {
+	Status int `form:"status"`
+}
+
+type Invitation struct {
+	ID          string `json:"id"`
+	InviterName string `json:"inviter_name,omitempty"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	Status      int    `json:"status"`
+	InviteeID   string `json:"invitee_id"`
+	Token       string `json:"token,omitempty"`
+	ExpireTime  string `json:"expire_time"`
+	CreateTime  string `json:"create_time"`
+}
+
+type InvitationListResp struct {
+	Pager *common_po.Pager `json:"pager"`
+	List  []*Invitation    `json:"list"`
+}
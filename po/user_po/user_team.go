@@ -8,6 +8,7 @@ type SubUser struct {
 	Id        string `json:"id"`
 	SubUserId string `json:"sub_user_id"`
 	UserId    string `json:"user_id"`
+	Role      string `json:"role"`
 	Name      string `json:"name"`
 	Phone     string `json:"phone"`
 }
@@ -19,8 +20,33 @@ type SubUserListResp struct {
 
 type AddSubUserReq struct {
 	Phone string `json:"phone"`
+	Role  string `json:"role"`
+}
+
+type AssignRoleReq struct {
+	Id   string `json:"id" binding:"required"`
+	Role string `json:"role" binding:"required"`
 }
 
 type DelSubUserReq struct {
 	Id string `json:"id"`
 }
+
+type LoginLockoutListReq struct {
+	UserId string `form:"user_id"`
+}
+
+type LoginLockout struct {
+	Id          string `json:"id"`
+	UserId      string `json:"user_id"`
+	Phone       string `json:"phone"`
+	Ip          string `json:"ip"`
+	UserAgent   string `json:"user_agent"`
+	LockedUntil string `json:"locked_until"`
+	CreateTime  string `json:"create_time"`
+}
+
+type LoginLockoutListResp struct {
+	Pager *common_po.Pager `json:"pager"`
+	List  []*LoginLockout  `json:"list"`
+}
@@ -0,0 +1,75 @@
+package wallet_po
+
+import (
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/util"
+)
+
+type WalletOwner struct {
+	OwnerType string `form:"owner_type" json:"owner_type" binding:"required,oneof=user customer"`
+	OwnerId   string `form:"owner_id" json:"owner_id" binding:"required,max=64"`
+}
+
+type Wallet struct {
+	Id        string     `json:"id"`
+	OwnerType string     `json:"owner_type"`
+	OwnerId   string     `json:"owner_id"`
+	Currency  string     `json:"currency"`
+	Balance   util.Money `json:"balance"`
+}
+
+type WalletEntry struct {
+	Id           string     `json:"id"`
+	Type         string     `json:"type"`
+	Amount       util.Money `json:"amount"`
+	BalanceAfter util.Money `json:"balance_after"`
+	RefEntryId   string     `json:"ref_entry_id"`
+	Reference    string     `json:"reference"`
+	Remark       string     `json:"remark"`
+	OperatorId   string     `json:"operator_id"`
+	CreateTime   string     `json:"create_time"`
+}
+
+type AddWalletEntryReq struct {
+	WalletOwner
+	Amount    util.Money `json:"amount" binding:"required,gt=0"`
+	Reference string     `json:"reference" binding:"max=64"`
+	Remark    string     `json:"remark" binding:"max=255"`
+}
+
+type RefundReq struct {
+	EntryId string     `json:"entry_id" binding:"required"`
+	Amount  util.Money `json:"amount" binding:"required,gt=0"`
+	Remark  string     `json:"remark" binding:"max=255"`
+}
+
+type WalletEntryListReq struct {
+	WalletOwner
+	Type string `form:"type" binding:"omitempty,oneof=top_up deduction refund"`
+}
+
+type WalletEntryListResp struct {
+	Pager *common_po.Pager `json:"pager"`
+	List  []*WalletEntry   `json:"list"`
+}
+
+type GetStatResp struct {
+	TotalUsage            util.Money `json:"total_usage"`
+	TotalUsageCurrency    string     `json:"total_usage_currency"`
+	LastWeekUsage         util.Money `json:"last_week_usage"`
+	LastWeekUsageCurrency string     `json:"last_week_usage_currency"`
+}
+
+type GetTrendReq struct {
+	WalletOwner
+	Days int `form:"days" binding:"omitempty,min=1,max=90"`
+}
+
+type GetTrendResp struct {
+	Data []*TrendItem `json:"data"`
+}
+
+type TrendItem struct {
+	Day        string     `json:"day"`
+	TotalCount util.Money `json:"total_count"`
+}
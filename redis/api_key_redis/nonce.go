@@ -0,0 +1,26 @@
+package api_key_redis
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/vars"
+	"time"
+)
+
+func getNonceKey(accessKey string, nonce string) string {
+	return "api_key:nonce:" + accessKey + ":" + nonce
+}
+
+// UseNonce 记录已使用的 nonce, 有效期内同一 nonce 再次出现视为重放
+func UseNonce(ctx *gin.Context, accessKey string, nonce string, expire time.Duration) error {
+	ok, err := vars.RedisClient.SetNX(ctx, getNonceKey(accessKey, nonce), 1, expire).Result()
+	if err != nil {
+		vars.Log.Errorf("UseNonce setnx err:%v, access_key:%s", err, accessKey)
+		return sm_error.NewHttpError(error_code.RedisErr)
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.ApiKeyNonceReused)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package user_redis
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/vars"
+	"strconv"
+	"time"
+)
+
+const (
+	loginBlockDelay = "delay"
+	loginBlockLock  = "lock"
+)
+
+// loginLimit 登录失败限制: window 内失败超过 freeAttempts 次后, 每次失败需等待的时间翻倍(最长 maxDelay),
+// 失败达到 lockThreshold 次后锁定 lockDuration
+type loginLimit struct {
+	name          string
+	window        time.Duration
+	freeAttempts  int64
+	maxDelay      time.Duration
+	lockThreshold int64
+	lockDuration  time.Duration
+}
+
+var (
+	phoneLoginLimit = &loginLimit{
+		name:          "phone",
+		window:        15 * time.Minute,
+		freeAttempts:  3,
+		maxDelay:      time.Minute,
+		lockThreshold: 10,
+		lockDuration:  15 * time.Minute,
+	}
+	// 同一 IP 会尝试多个手机号, 阈值相应放宽
+	ipLoginLimit = &loginLimit{
+		name:          "ip",
+		window:        15 * time.Minute,
+		freeAttempts:  10,
+		maxDelay:      time.Minute,
+		lockThreshold: 50,
+		lockDuration:  15 * time.Minute,
+	}
+)
+
+func getLoginFailKey(name string, value string) string {
+	return "login_fail:" + name + ":" + value
+}
+
+func getLoginBlockKey(name string, value string) string {
+	return "login_block:" + name + ":" + value
+}
+
+// CheckLoginAllowed 手机号或 IP 处于等待或锁定期间时拒绝登录
+func CheckLoginAllowed(ctx *gin.Context, phone string, ip string) error {
+	if err := phoneLoginLimit.check(ctx, phone); err != nil {
+		return err
+	}
+	return ipLoginLimit.check(ctx, ip)
+}
+
+// RecordLoginFailure 记录一次登录失败, 该手机号因本次失败被锁定时返回解锁时间, 否则返回零值
+func RecordLoginFailure(ctx *gin.Context, phone string, ip string) (time.Time, error) {
+	phoneLocked, err := phoneLoginLimit.fail(ctx, phone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ipLocked, err := ipLoginLimit.fail(ctx, ip)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if ipLocked {
+		vars.Log.Warnf("RecordLoginFailure ip locked, ip:%s", ip)
+	}
+	if !phoneLocked {
+		return time.Time{}, nil
+	}
+	return time.Now().Add(phoneLoginLimit.lockDuration), nil
+}
+
+// ClearLoginFailure 登录成功后清除该手机号的失败记录, IP 的记录保留到窗口过期
+func ClearLoginFailure(ctx *gin.Context, phone string) {
+	err := vars.RedisClient.Del(ctx, getLoginFailKey(phoneLoginLimit.name, phone), getLoginBlockKey(phoneLoginLimit.name, phone)).Err()
+	if err != nil {
+		vars.Log.Errorf("ClearLoginFailure err:%v, phone:%s", err, phone)
+	}
+}
+
+func (l *loginLimit) check(ctx *gin.Context, value string) error {
+	redisClient := vars.RedisClient
+	key := getLoginBlockKey(l.name, value)
+	block, err := redisClient.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		vars.Log.Errorf("loginLimit.check get err:%v, %s:%s", err, l.name, value)
+		return sm_error.NewHttpError(error_code.RedisErr)
+	}
+	ttl, err := redisClient.TTL(ctx, key).Result()
+	if err != nil || ttl <= 0 {
+		ttl = time.Second
+	}
+	if block == loginBlockLock {
+		return sm_error.NewHttpError(error_code.UserLoginLocked,
+			fmt.Sprintf("登录失败次数过多, 账号已临时锁定, 请%d分钟后再试", int64((ttl+time.Minute-1)/time.Minute)))
+	}
+	return sm_error.NewHttpError(error_code.UserLoginTooFrequent,
+		fmt.Sprintf("登录失败次数过多, 请%d秒后再试", int64((ttl+time.Second-1)/time.Second)))
+}
+
+// fail 以有序集合记录窗口内每次失败的时间, 实现滑动窗口计数
+func (l *loginLimit) fail(ctx *gin.Context, value string) (bool, error) {
+	redisClient := vars.RedisClient
+	key := getLoginFailKey(l.name, value)
+	now := time.Now()
+	pipe := redisClient.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-l.window).UnixNano(), 10))
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixNano()), Member: strconv.FormatInt(now.UnixNano(), 10)})
+	count := pipe.ZCard(ctx, key)
+	pipe.Expire(ctx, key, l.window)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		vars.Log.Errorf("loginLimit.fail err:%v, %s:%s", err, l.name, value)
+		return false, sm_error.NewHttpError(error_code.RedisErr)
+	}
+	failures := count.Val()
+	if failures >= l.lockThreshold {
+		// 锁定后重新计数, 解锁后不会因为旧的失败记录立即再次锁定
+		redisClient.Set(ctx, getLoginBlockKey(l.name, value), loginBlockLock, l.lockDuration)
+		redisClient.Del(ctx, key)
+		return true, nil
+	}
+	if failures > l.freeAttempts {
+		delay := l.maxDelay
+		if shift := failures - l.freeAttempts - 1; shift < 16 && time.Second<<shift < l.maxDelay {
+			delay = time.Second << shift
+		}
+		redisClient.Set(ctx, getLoginBlockKey(l.name, value), loginBlockDelay, delay)
+	}
+	return false, nil
+}
@@ -0,0 +1,52 @@
+package user_redis
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/vars"
+	"time"
+)
+
+// oidcStateExpire 跳转到身份提供方登录的有效期
+const oidcStateExpire = 10 * time.Minute
+
+func getOidcStateKey(state string) string {
+	return "oidc:state:" + state
+}
+
+// SetOidcState 保存一次单点登录的 PKCE code_verifier 和 nonce, 回调时按 state 取回.
+// linkUserId 不为空表示已登录用户发起的关联, 而不是登录
+func SetOidcState(ctx *gin.Context, state string, verifier string, nonce string, linkUserId string) error {
+	redisClient := vars.RedisClient
+	key := getOidcStateKey(state)
+	pipe := redisClient.TxPipeline()
+	pipe.HSet(ctx, key, "verifier", verifier, "nonce", nonce, "link_user_id", linkUserId)
+	pipe.Expire(ctx, key, oidcStateExpire)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		vars.Log.Errorf("SetOidcState err:%v", err)
+		return sm_error.NewHttpError(error_code.RedisErr)
+	}
+	return nil
+}
+
+// TakeOidcState 取出并删除 state, 同一 state 只能使用一次
+func TakeOidcState(ctx *gin.Context, state string) (verifier string, nonce string, linkUserId string, err error) {
+	redisClient := vars.RedisClient
+	key := getOidcStateKey(state)
+	pipe := redisClient.TxPipeline()
+	get := pipe.HGetAll(ctx, key)
+	pipe.Del(ctx, key)
+	_, err = pipe.Exec(ctx)
+	if err != nil && err != redis.Nil {
+		vars.Log.Errorf("TakeOidcState err:%v", err)
+		return "", "", "", sm_error.NewHttpError(error_code.RedisErr)
+	}
+	values := get.Val()
+	if values["verifier"] == "" {
+		return "", "", "", sm_error.NewHttpError(error_code.OidcStateInvalid)
+	}
+	return values["verifier"], values["nonce"], values["link_user_id"], nil
+}
@@ -0,0 +1,102 @@
+package user_redis
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/vars"
+	"time"
+)
+
+const (
+	resetCodeExpire      = 10 * time.Minute
+	resetCodeInterval    = time.Minute
+	resetCodeDailyWindow = 24 * time.Hour
+	resetCodeDailyLimit  = 10
+	resetCodeMaxAttempts = 5
+)
+
+func getResetCodeKey(phone string) string {
+	return "reset_code:phone:" + phone
+}
+
+func getResetCodeAttemptKey(phone string) string {
+	return "reset_code:attempt:" + phone
+}
+
+func getResetCodeIntervalKey(phone string) string {
+	return "reset_code:interval:" + phone
+}
+
+func getResetCodeDailyKey(phone string) string {
+	return "reset_code:daily:" + phone
+}
+
+// AllowSendResetCode 同一手机号每分钟最多发送一次, 每24小时最多发送 resetCodeDailyLimit 次
+func AllowSendResetCode(ctx *gin.Context, phone string) error {
+	redisClient := vars.RedisClient
+	ok, err := redisClient.SetNX(ctx, getResetCodeIntervalKey(phone), 1, resetCodeInterval).Result()
+	if err != nil {
+		vars.Log.Errorf("AllowSendResetCode setnx err:%v, phone:%s", err, phone)
+		return sm_error.NewHttpError(error_code.RedisErr)
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.UserResetCodeTooFrequent)
+	}
+	count, err := redisClient.Incr(ctx, getResetCodeDailyKey(phone)).Result()
+	if err != nil {
+		vars.Log.Errorf("AllowSendResetCode incr err:%v, phone:%s", err, phone)
+		return sm_error.NewHttpError(error_code.RedisErr)
+	}
+	if count == 1 {
+		redisClient.Expire(ctx, getResetCodeDailyKey(phone), resetCodeDailyWindow)
+	}
+	if count > resetCodeDailyLimit {
+		return sm_error.NewHttpError(error_code.UserResetCodeTooFrequent)
+	}
+	return nil
+}
+
+func SetResetCode(ctx *gin.Context, phone string, code string) error {
+	redisClient := vars.RedisClient
+	err := redisClient.Set(ctx, getResetCodeKey(phone), code, resetCodeExpire).Err()
+	if err != nil {
+		vars.Log.Errorf("SetResetCode err:%v, phone:%s", err, phone)
+		return sm_error.NewHttpError(error_code.RedisErr)
+	}
+	redisClient.Del(ctx, getResetCodeAttemptKey(phone))
+	return nil
+}
+
+// CheckResetCode 校验验证码, 连续输错 resetCodeMaxAttempts 次后验证码作废
+func CheckResetCode(ctx *gin.Context, phone string, code string) error {
+	redisClient := vars.RedisClient
+	stored, err := redisClient.Get(ctx, getResetCodeKey(phone)).Result()
+	if err == redis.Nil {
+		return sm_error.NewHttpError(error_code.UserResetCodeIncorrect)
+	}
+	if err != nil {
+		vars.Log.Errorf("CheckResetCode get err:%v, phone:%s", err, phone)
+		return sm_error.NewHttpError(error_code.RedisErr)
+	}
+	if subtle.ConstantTimeCompare([]byte(stored), []byte(code)) == 1 {
+		return nil
+	}
+	attempts, err := redisClient.Incr(ctx, getResetCodeAttemptKey(phone)).Result()
+	if err == nil && attempts == 1 {
+		redisClient.Expire(ctx, getResetCodeAttemptKey(phone), resetCodeExpire)
+	}
+	if attempts >= resetCodeMaxAttempts {
+		ClearResetCode(ctx, phone)
+	}
+	return sm_error.NewHttpError(error_code.UserResetCodeIncorrect)
+}
+
+func ClearResetCode(ctx *gin.Context, phone string) {
+	err := vars.RedisClient.Del(ctx, getResetCodeKey(phone), getResetCodeAttemptKey(phone)).Err()
+	if err != nil {
+		vars.Log.Errorf("ClearResetCode err:%v, phone:%s", err, phone)
+	}
+}
@@ -0,0 +1,86 @@
+package user_redis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/vars"
+	"time"
+)
+
+const (
+	loginChallengeExpire      = 5 * time.Minute
+	loginChallengeMaxAttempts = 5
+	// totpStepExpire 覆盖动态码允许的时钟误差范围, 期间同一动态码不能再次使用
+	totpStepExpire = 2 * time.Minute
+)
+
+func getLoginChallengeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "login_challenge:" + hex.EncodeToString(sum[:])
+}
+
+func getTotpStepKey(userId string, step int64) string {
+	return fmt.Sprintf("totp:used:%s:%d", userId, step)
+}
+
+// SetLoginChallenge 密码校验通过但还需要校验动态码的登录, 有效期 loginChallengeExpire
+func SetLoginChallenge(ctx *gin.Context, token string, userId string, phone string) error {
+	redisClient := vars.RedisClient
+	key := getLoginChallengeKey(token)
+	pipe := redisClient.TxPipeline()
+	pipe.HSet(ctx, key, "user_id", userId, "phone", phone, "attempts", 0)
+	pipe.Expire(ctx, key, loginChallengeExpire)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		vars.Log.Errorf("SetLoginChallenge err:%v, user_id:%s", err, userId)
+		return sm_error.NewHttpError(error_code.RedisErr)
+	}
+	return nil
+}
+
+func GetLoginChallenge(ctx *gin.Context, token string) (userId string, phone string, err error) {
+	values, err := vars.RedisClient.HGetAll(ctx, getLoginChallengeKey(token)).Result()
+	if err != nil {
+		vars.Log.Errorf("GetLoginChallenge err:%v", err)
+		return "", "", sm_error.NewHttpError(error_code.RedisErr)
+	}
+	if values["user_id"] == "" {
+		return "", "", sm_error.NewHttpError(error_code.UserTwoFactorChallengeInvalid)
+	}
+	return values["user_id"], values["phone"], nil
+}
+
+// FailLoginChallenge 动态码错误次数达到上限后需要重新输入密码
+func FailLoginChallenge(ctx *gin.Context, token string) {
+	redisClient := vars.RedisClient
+	key := getLoginChallengeKey(token)
+	attempts, err := redisClient.HIncrBy(ctx, key, "attempts", 1).Result()
+	if err != nil {
+		vars.Log.Errorf("FailLoginChallenge err:%v", err)
+		return
+	}
+	if attempts >= loginChallengeMaxAttempts {
+		redisClient.Del(ctx, key)
+	}
+}
+
+func ClearLoginChallenge(ctx *gin.Context, token string) {
+	err := vars.RedisClient.Del(ctx, getLoginChallengeKey(token)).Err()
+	if err != nil {
+		vars.Log.Errorf("ClearLoginChallenge err:%v", err)
+	}
+}
+
+// UseTotpStep 记录已使用的动态码时间步, 已使用过返回 false
+func UseTotpStep(ctx *gin.Context, userId string, step int64) (bool, error) {
+	ok, err := vars.RedisClient.SetNX(ctx, getTotpStepKey(userId, step), 1, totpStepExpire).Result()
+	if err != nil {
+		vars.Log.Errorf("UseTotpStep err:%v, user_id:%s", err, userId)
+		return false, sm_error.NewHttpError(error_code.RedisErr)
+	}
+	return ok, nil
+}
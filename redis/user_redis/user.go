@@ -1,59 +1,168 @@
 package user_redis
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"github.com/gin-gonic/gin"
-	"github.com/redis/go-redis/v9"
+	"github.com/shop_management/dto/user_dto"
 	"github.com/shop_management/sm_error"
 	"github.com/shop_management/sm_error/error_code"
 	"github.com/shop_management/vars"
+	"sort"
+	"strconv"
 	"time"
 )
 
-func getUserIdKey(userId string) string {
-	return "session:user_id:" + userId
+// getTokenKey 以会话id而不是 token 作为 key, redis 中不保存 token 明文
+func getTokenKey(sessionId string) string {
+	return "session:token:" + sessionId
 }
 
-func getTokenKey(token string) string {
-	return "session:token:" + token
+// getUserSessionKey 用户的全部会话, field 为会话id, value 为会话信息
+func getUserSessionKey(userId string) string {
+	return "session:user:" + userId
 }
 
-func ClearSession(ctx *gin.Context, userId string) error {
+// getLastSeenKey 会话最近访问时间, 与会话信息分开存放, 每次请求只需写一个 field
+func getLastSeenKey(userId string) string {
+	return "session:last_seen:" + userId
+}
+
+// getImpersonatorKey 模拟登录会话对应的平台管理员, 校验 token 时一并读取
+func getImpersonatorKey(sessionId string) string {
+	return "session:impersonator:" + sessionId
+}
+
+// GetSessionId 由 token 计算会话id
+func GetSessionId(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:16])
+}
+
+// SetSession 新增一个会话, 不影响该用户在其他设备上的会话
+func SetSession(ctx *gin.Context, token string, session *user_dto.Session) error {
 	redisClient := vars.RedisClient
-	tokenResp := redisClient.Get(ctx, getUserIdKey(userId))
-	if tokenResp.Err() != nil {
-		return nil
+	session.ID = GetSessionId(token)
+	expire := time.Until(session.ExpireTime)
+	data, err := json.Marshal(session)
+	if err != nil {
+		vars.Log.Errorf("SetSession marshal err:%v, user_id:%s", err, session.UserID)
+		return sm_error.NewHttpError(error_code.ServerInternalError)
 	}
-	token := tokenResp.Val()
-	err := redisClient.Del(ctx, getTokenKey(token)).Err()
+	if session.ImpersonatorID != "" {
+		err = redisClient.Set(ctx, getImpersonatorKey(session.ID), session.ImpersonatorID, expire).Err()
+		if err != nil {
+			vars.Log.Errorf("SetSession impersonator err:%v, user_id:%s", err, session.UserID)
+			return sm_error.NewHttpError(error_code.RedisErr)
+		}
+	}
+	err = redisClient.Set(ctx, getTokenKey(session.ID), session.UserID, expire).Err()
 	if err != nil {
-		vars.Log.Errorf("ClearSession del token err:%v", err)
+		vars.Log.Errorf("SetSession session_user_map err:%v, user_id:%s", err, session.UserID)
+		return sm_error.NewHttpError(error_code.RedisErr)
 	}
-	err = redisClient.Del(ctx, getUserIdKey(userId)).Err()
+	pipe := redisClient.TxPipeline()
+	pipe.HSet(ctx, getUserSessionKey(session.UserID), session.ID, data)
+	pipe.HSet(ctx, getLastSeenKey(session.UserID), session.ID, session.CreateTime.Unix())
+	// 会话有效期相同, 最新的会话最晚过期, 会话集合的有效期跟随最新的会话
+	pipe.Expire(ctx, getUserSessionKey(session.UserID), expire)
+	pipe.Expire(ctx, getLastSeenKey(session.UserID), expire)
+	_, err = pipe.Exec(ctx)
 	if err != nil {
-		vars.Log.Errorf("ClearSession del userId err:%v", err)
+		vars.Log.Errorf("SetSession user_session_map err:%v, user_id:%s", err, session.UserID)
+		return sm_error.NewHttpError(error_code.RedisErr)
 	}
 	return nil
 }
 
-func SetSession(ctx *gin.Context, userId string, token string, expireTime int64) error {
+// ListSession 用户当前有效的会话, 按创建时间倒序, 顺带清理已过期的会话
+func ListSession(ctx *gin.Context, userId string) ([]*user_dto.Session, error) {
+	redisClient := vars.RedisClient
+	values, err := redisClient.HGetAll(ctx, getUserSessionKey(userId)).Result()
+	if err != nil {
+		vars.Log.Errorf("ListSession err:%v, user_id:%s", err, userId)
+		return nil, sm_error.NewHttpError(error_code.RedisErr)
+	}
+	lastSeen, err := redisClient.HGetAll(ctx, getLastSeenKey(userId)).Result()
+	if err != nil {
+		vars.Log.Errorf("ListSession last_seen err:%v, user_id:%s", err, userId)
+		return nil, sm_error.NewHttpError(error_code.RedisErr)
+	}
+	now := time.Now()
+	list := make([]*user_dto.Session, 0, len(values))
+	expired := make([]string, 0)
+	for id, value := range values {
+		session := &user_dto.Session{}
+		if err := json.Unmarshal([]byte(value), session); err != nil || !session.ExpireTime.After(now) {
+			expired = append(expired, id)
+			continue
+		}
+		session.LastSeenTime = session.CreateTime
+		if unix, err := strconv.ParseInt(lastSeen[id], 10, 64); err == nil {
+			session.LastSeenTime = time.Unix(unix, 0)
+		}
+		list = append(list, session)
+	}
+	if len(expired) > 0 {
+		redisClient.HDel(ctx, getUserSessionKey(userId), expired...)
+		redisClient.HDel(ctx, getLastSeenKey(userId), expired...)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreateTime.After(list[j].CreateTime)
+	})
+	return list, nil
+}
+
+// DelSession 注销用户的某个会话, 会话不存在时返回 false
+func DelSession(ctx *gin.Context, userId string, sessionId string) (bool, error) {
+	redisClient := vars.RedisClient
+	count, err := redisClient.HDel(ctx, getUserSessionKey(userId), sessionId).Result()
+	if err != nil {
+		vars.Log.Errorf("DelSession err:%v, user_id:%s", err, userId)
+		return false, sm_error.NewHttpError(error_code.RedisErr)
+	}
+	redisClient.HDel(ctx, getLastSeenKey(userId), sessionId)
+	err = redisClient.Del(ctx, getTokenKey(sessionId), getImpersonatorKey(sessionId)).Err()
+	if err != nil {
+		vars.Log.Errorf("DelSession del token err:%v, user_id:%s", err, userId)
+		return false, sm_error.NewHttpError(error_code.RedisErr)
+	}
+	return count > 0, nil
+}
+
+// ClearSession 注销用户在所有设备上的会话
+func ClearSession(ctx *gin.Context, userId string) error {
 	redisClient := vars.RedisClient
-	err := redisClient.Set(ctx, getUserIdKey(userId), token, time.Duration(expireTime)*time.Second).Err()
+	ids, err := redisClient.HKeys(ctx, getUserSessionKey(userId)).Result()
 	if err != nil {
-		vars.Log.Errorf("SetSession user_session_map err:%v, data:%s,%s", err, userId, token)
+		vars.Log.Errorf("ClearSession get sessions err:%v, user_id:%s", err, userId)
 		return sm_error.NewHttpError(error_code.RedisErr)
 	}
-	err = redisClient.Set(ctx, getTokenKey(token), userId, time.Duration(expireTime)*time.Second).Err()
+	keys := []string{getUserSessionKey(userId), getLastSeenKey(userId)}
+	for _, id := range ids {
+		keys = append(keys, getTokenKey(id), getImpersonatorKey(id))
+	}
+	err = redisClient.Del(ctx, keys...).Err()
 	if err != nil {
-		vars.Log.Errorf("SetSession session_user_map err:%v, data:%s,%s", err, userId, token)
+		vars.Log.Errorf("ClearSession del err:%v, user_id:%s", err, userId)
 		return sm_error.NewHttpError(error_code.RedisErr)
 	}
 	return nil
 }
 
-func CheckToken(ctx *gin.Context, token string, userId string) error {
-	get := vars.RedisClient.Get(ctx, getTokenKey(token))
-	if get.Err() == redis.Nil || get.Val() == "" || get.Val() != userId {
-		return sm_error.NewHttpError(error_code.UserTokenError)
+// CheckToken 校验 token 并记录会话最近访问时间, 模拟登录的会话同时返回平台管理员id
+func CheckToken(ctx *gin.Context, token string, userId string) (string, error) {
+	sessionId := GetSessionId(token)
+	values, err := vars.RedisClient.MGet(ctx, getTokenKey(sessionId), getImpersonatorKey(sessionId)).Result()
+	if err != nil {
+		vars.Log.Errorf("CheckToken err:%v, user_id:%s", err, userId)
+		return "", sm_error.NewHttpError(error_code.UserTokenError)
 	}
-	return nil
+	if value, _ := values[0].(string); value == "" || value != userId {
+		return "", sm_error.NewHttpError(error_code.UserTokenError)
+	}
+	vars.RedisClient.HSet(ctx, getLastSeenKey(userId), sessionId, time.Now().Unix())
+	impersonatorId, _ := values[1].(string)
+	return impersonatorId, nil
 }
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/api_key_dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ApiKeyRepo interface {
+	Add(ctx *gin.Context, db *gorm.DB, dto *api_key_dto.ApiKey) error
+	GetById(ctx *gin.Context, db *gorm.DB, id string) (*api_key_dto.ApiKey, error)
+	GetByAccessKey(ctx *gin.Context, db *gorm.DB, accessKey string) (*api_key_dto.ApiKey, error)
+	ListByUser(ctx *gin.Context, db *gorm.DB, userId string) ([]*api_key_dto.ApiKey, error)
+	UpdateSecret(ctx *gin.Context, db *gorm.DB, id string, hash string) error
+	UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, status int) error
+	UpdateLastUsed(ctx *gin.Context, db *gorm.DB, id string, at time.Time) error
+	RevokeByUser(ctx *gin.Context, db *gorm.DB, userId string) error
+	DeleteByUser(ctx *gin.Context, db *gorm.DB, userId string) error
+}
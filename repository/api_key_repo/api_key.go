@@ -0,0 +1,114 @@
+package api_key_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/api_key_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/api_key_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type apiKeyRepoImpl struct {
+}
+
+func NewApiKeyRepoImpl() repository.ApiKeyRepo {
+	return &apiKeyRepoImpl{}
+}
+
+func (a *apiKeyRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *api_key_dto.ApiKey) error {
+	m := api_key_assembly.ConvertApiKeyDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("apiKeyRepoImpl.Add error:%v,name: %v", err, dto.Name)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	dto.CreateTime = m.CreateTime
+	return nil
+}
+
+func (a *apiKeyRepoImpl) GetById(ctx *gin.Context, db *gorm.DB, id string) (*api_key_dto.ApiKey, error) {
+	return a.getOne(db.Where("id = ?", id))
+}
+
+func (a *apiKeyRepoImpl) GetByAccessKey(ctx *gin.Context, db *gorm.DB, accessKey string) (*api_key_dto.ApiKey, error) {
+	return a.getOne(db.Where("access_key = ?", accessKey))
+}
+
+func (a *apiKeyRepoImpl) ListByUser(ctx *gin.Context, db *gorm.DB, userId string) ([]*api_key_dto.ApiKey, error) {
+	mList := make([]*model.ApiKey, 0)
+	err := db.Where("user_id = ?", userId).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("apiKeyRepoImpl.ListByUser error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*api_key_dto.ApiKey, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, api_key_assembly.ConvertApiKeyModelToDto(m))
+	}
+	return list, nil
+}
+
+func (a *apiKeyRepoImpl) UpdateSecret(ctx *gin.Context, db *gorm.DB, id string, hash string) error {
+	err := db.Model(&model.ApiKey{}).Where("id = ?", id).Update("secret_hash", hash).Error
+	if err != nil {
+		vars.Log.Errorf("apiKeyRepoImpl.UpdateSecret error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (a *apiKeyRepoImpl) UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, status int) error {
+	err := db.Model(&model.ApiKey{}).Where("id = ?", id).Update("status", status).Error
+	if err != nil {
+		vars.Log.Errorf("apiKeyRepoImpl.UpdateStatus error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (a *apiKeyRepoImpl) UpdateLastUsed(ctx *gin.Context, db *gorm.DB, id string, at time.Time) error {
+	err := db.Model(&model.ApiKey{}).Where("id = ?", id).Update("last_used_time", at).Error
+	if err != nil {
+		vars.Log.Errorf("apiKeyRepoImpl.UpdateLastUsed error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (a *apiKeyRepoImpl) getOne(db *gorm.DB) (*api_key_dto.ApiKey, error) {
+	m := &model.ApiKey{}
+	err := db.First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("apiKeyRepoImpl.getOne error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return api_key_assembly.ConvertApiKeyModelToDto(m), nil
+}
+
+func (a *apiKeyRepoImpl) RevokeByUser(ctx *gin.Context, db *gorm.DB, userId string) error {
+	err := db.Model(&model.ApiKey{}).Where("user_id = ?", userId).Update("status", api_key_dto.ApiKeyStatusRevoked).Error
+	if err != nil {
+		vars.Log.Errorf("apiKeyRepoImpl.RevokeByUser error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (a *apiKeyRepoImpl) DeleteByUser(ctx *gin.Context, db *gorm.DB, userId string) error {
+	err := db.Where("user_id = ?", userId).Delete(&model.ApiKey{}).Error
+	if err != nil {
+		vars.Log.Errorf("apiKeyRepoImpl.DeleteByUser error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package api_key_assembly
+
+import (
+	"github.com/shop_management/dto/api_key_dto"
+	"github.com/shop_management/model"
+	"strings"
+)
+
+func ConvertApiKeyDtoToModel(a *api_key_dto.ApiKey) *model.ApiKey {
+	return &model.ApiKey{
+		ID:           a.ID,
+		UserID:       a.UserID,
+		Name:         a.Name,
+		AccessKey:    a.AccessKey,
+		SecretHash:   a.SecretHash,
+		Scopes:       strings.Join(a.Scopes, ","),
+		Status:       a.Status,
+		LastUsedTime: a.LastUsedTime,
+		CreateTime:   a.CreateTime,
+		ModifyTime:   a.ModifyTime,
+	}
+}
+
+func ConvertApiKeyModelToDto(a *model.ApiKey) *api_key_dto.ApiKey {
+	scopes := make([]string, 0)
+	if a.Scopes != "" {
+		scopes = strings.Split(a.Scopes, ",")
+	}
+	return &api_key_dto.ApiKey{
+		ID:           a.ID,
+		UserID:       a.UserID,
+		Name:         a.Name,
+		AccessKey:    a.AccessKey,
+		SecretHash:   a.SecretHash,
+		Scopes:       scopes,
+		Status:       a.Status,
+		LastUsedTime: a.LastUsedTime,
+		CreateTime:   a.CreateTime,
+		ModifyTime:   a.ModifyTime,
+	}
+}
@@ -0,0 +1,19 @@
+package audit_assembly
+
+import (
+	"github.com/jinzhu/copier"
+	"github.com/shop_management/dto/audit_dto"
+	"github.com/shop_management/model"
+)
+
+func ConvertAuditModelToDto(m *model.AuditLog) *audit_dto.AuditLog {
+	convertRes := &audit_dto.AuditLog{}
+	_ = copier.Copy(convertRes, m)
+	return convertRes
+}
+
+func ConvertAuditDtoToModel(a *audit_dto.AuditLog) *model.AuditLog {
+	convertRes := &model.AuditLog{}
+	_ = copier.Copy(convertRes, a)
+	return convertRes
+}
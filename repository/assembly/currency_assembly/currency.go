@@ -0,0 +1,32 @@
+package currency_assembly
+
+import (
+	"github.com/shop_management/dto/currency_dto"
+	"github.com/shop_management/model"
+)
+
+func ConvertERDtoToModel(e *currency_dto.ExchangeRate) *model.ExchangeRate {
+	return &model.ExchangeRate{
+		ID:            e.ID,
+		UserID:        e.UserID,
+		FromCurrency:  e.FromCurrency,
+		ToCurrency:    e.ToCurrency,
+		Rate:          e.Rate,
+		EffectiveDate: e.EffectiveDate,
+		Source:        e.Source,
+		CreateTime:    e.CreateTime,
+	}
+}
+
+func ConvertERModelToDto(m *model.ExchangeRate) *currency_dto.ExchangeRate {
+	return &currency_dto.ExchangeRate{
+		ID:            m.ID,
+		UserID:        m.UserID,
+		FromCurrency:  m.FromCurrency,
+		ToCurrency:    m.ToCurrency,
+		Rate:          m.Rate,
+		EffectiveDate: m.EffectiveDate,
+		Source:        m.Source,
+		CreateTime:    m.CreateTime,
+	}
+}
@@ -0,0 +1,104 @@
+package fulfillment_assembly
+
+import (
+	"github.com/shop_management/dto/fulfillment_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/util"
+)
+
+func ConvertPTDtoToModel(t *fulfillment_dto.PickTask) *model.PickTask {
+	return &model.PickTask{
+		ID:         t.ID,
+		OrderID:    t.OrderID,
+		Warehouse:  t.Warehouse,
+		StoragePos: t.StoragePos,
+		Status:     t.Status,
+		AssigneeID: t.AssigneeID,
+		ClaimTime:  t.ClaimTime,
+		PickedTime: t.PickedTime,
+		CreateTime: t.CreateTime,
+		ModifyTime: t.ModifyTime,
+	}
+}
+
+func ConvertPTModelToDto(t *model.PickTask) *fulfillment_dto.PickTask {
+	return &fulfillment_dto.PickTask{
+		ID:         t.ID,
+		OrderID:    t.OrderID,
+		Warehouse:  t.Warehouse,
+		StoragePos: t.StoragePos,
+		Status:     t.Status,
+		AssigneeID: t.AssigneeID,
+		ClaimTime:  t.ClaimTime,
+		PickedTime: t.PickedTime,
+		Items:      make([]*fulfillment_dto.PickTaskItem, 0),
+		CreateTime: t.CreateTime,
+		ModifyTime: t.ModifyTime,
+	}
+}
+
+func ConvertPTIDtoToModel(i *fulfillment_dto.PickTaskItem) *model.PickTaskItem {
+	return &model.PickTaskItem{
+		ID:          i.ID,
+		TaskID:      i.TaskID,
+		OrderItemID: i.OrderItemID,
+		ProductID:   i.ProductID,
+		StorageCode: i.StorageCode,
+		Quantity:    i.Quantity,
+	}
+}
+
+func ConvertPTIModelToDto(i *model.PickTaskItem) *fulfillment_dto.PickTaskItem {
+	return &fulfillment_dto.PickTaskItem{
+		ID:          i.ID,
+		TaskID:      i.TaskID,
+		OrderItemID: i.OrderItemID,
+		ProductID:   i.ProductID,
+		StorageCode: i.StorageCode,
+		Quantity:    i.Quantity,
+	}
+}
+
+func ConvertPRDtoToModel(r *fulfillment_dto.PackRecord) *model.PackRecord {
+	return &model.PackRecord{
+		ID:           r.ID,
+		OrderID:      r.OrderID,
+		PackerID:     r.PackerID,
+		ScannedCodes: util.MarshalToStringNoErr(r.ScannedCodes),
+		PackTime:     r.PackTime,
+	}
+}
+
+func ConvertPRModelToDto(r *model.PackRecord) *fulfillment_dto.PackRecord {
+	codes := make([]string, 0)
+	util.UnMarshalToStringNoErr(r.ScannedCodes, &codes)
+	return &fulfillment_dto.PackRecord{
+		ID:           r.ID,
+		OrderID:      r.OrderID,
+		PackerID:     r.PackerID,
+		ScannedCodes: codes,
+		PackTime:     r.PackTime,
+	}
+}
+
+func ConvertSDtoToModel(s *fulfillment_dto.Shipment) *model.Shipment {
+	return &model.Shipment{
+		ID:         s.ID,
+		OrderID:    s.OrderID,
+		Carrier:    s.Carrier,
+		TrackingNo: s.TrackingNo,
+		ShipperID:  s.ShipperID,
+		ShipTime:   s.ShipTime,
+	}
+}
+
+func ConvertSModelToDto(s *model.Shipment) *fulfillment_dto.Shipment {
+	return &fulfillment_dto.Shipment{
+		ID:         s.ID,
+		OrderID:    s.OrderID,
+		Carrier:    s.Carrier,
+		TrackingNo: s.TrackingNo,
+		ShipperID:  s.ShipperID,
+		ShipTime:   s.ShipTime,
+	}
+}
@@ -0,0 +1,83 @@
+package order_assembly
+
+import (
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/model"
+)
+
+func ConvertSODtoToModel(o *order_dto.SalesOrder) *model.SalesOrder {
+	return &model.SalesOrder{
+		ID:             o.ID,
+		TeamID:         o.TeamID,
+		OrderNo:        o.OrderNo,
+		UserID:         o.UserID,
+		CustomerID:     o.CustomerID,
+		CustomerName:   o.CustomerName,
+		Remark:         o.Remark,
+		Status:         o.Status,
+		Currency:       o.Currency,
+		TotalAmount:    o.TotalAmount,
+		DiscountAmount: o.DiscountAmount,
+		NetAmount:      o.NetAmount,
+		TaxAmount:      o.TaxAmount,
+		ConfirmTime:    o.ConfirmTime,
+		CreateTime:     o.CreateTime,
+		ModifyTime:     o.ModifyTime,
+	}
+}
+
+func ConvertSOModelToDto(o *model.SalesOrder) *order_dto.SalesOrder {
+	return &order_dto.SalesOrder{
+		ID:             o.ID,
+		TeamID:         o.TeamID,
+		OrderNo:        o.OrderNo,
+		UserID:         o.UserID,
+		CustomerID:     o.CustomerID,
+		CustomerName:   o.CustomerName,
+		Remark:         o.Remark,
+		Status:         o.Status,
+		Currency:       o.Currency,
+		TotalAmount:    o.TotalAmount,
+		DiscountAmount: o.DiscountAmount,
+		NetAmount:      o.NetAmount,
+		TaxAmount:      o.TaxAmount,
+		ConfirmTime:    o.ConfirmTime,
+		Items:          make([]*order_dto.SalesOrderItem, 0),
+		CreateTime:     o.CreateTime,
+		ModifyTime:     o.ModifyTime,
+	}
+}
+
+func ConvertSOIDtoToModel(i *order_dto.SalesOrderItem) *model.SalesOrderItem {
+	return &model.SalesOrderItem{
+		ID:             i.ID,
+		OrderID:        i.OrderID,
+		ProductID:      i.ProductID,
+		Quantity:       i.Quantity,
+		UnitPrice:      i.UnitPrice,
+		DiscountAmount: i.DiscountAmount,
+		Amount:         i.Amount,
+		TaxRate:        i.TaxRate,
+		NetAmount:      i.NetAmount,
+		TaxAmount:      i.TaxAmount,
+		CreateTime:     i.CreateTime,
+		ModifyTime:     i.ModifyTime,
+	}
+}
+
+func ConvertSOIModelToDto(i *model.SalesOrderItem) *order_dto.SalesOrderItem {
+	return &order_dto.SalesOrderItem{
+		ID:             i.ID,
+		OrderID:        i.OrderID,
+		ProductID:      i.ProductID,
+		Quantity:       i.Quantity,
+		UnitPrice:      i.UnitPrice,
+		DiscountAmount: i.DiscountAmount,
+		Amount:         i.Amount,
+		TaxRate:        i.TaxRate,
+		NetAmount:      i.NetAmount,
+		TaxAmount:      i.TaxAmount,
+		CreateTime:     i.CreateTime,
+		ModifyTime:     i.ModifyTime,
+	}
+}
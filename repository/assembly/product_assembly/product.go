@@ -0,0 +1,32 @@
+package product_assembly
+
+import (
+	"github.com/shop_management/dto/product_dto"
+	"github.com/shop_management/model"
+)
+
+func ConvertPModelToDto(m *model.Product) *product_dto.Product {
+	return &product_dto.Product{
+		ID:               m.ID,
+		ImageURL:         m.ImageURL,
+		StorageCode:      m.StorageCode,
+		Barcode:          m.Barcode,
+		StoragePos:       m.StoragePos,
+		Warehouse:        m.Warehouse,
+		Name:             m.Name,
+		Color:            m.Color,
+		Category:         m.Category,
+		BasePrice:        m.BasePrice,
+		SaleCurrency:     m.SaleCurrency,
+		CostPrice:        m.CostPrice,
+		PurchasePrice:    m.PurchasePrice,
+		PurchaseCurrency: m.PurchaseCurrency,
+		Factory:          m.Factory,
+		Stock:            m.Stock,
+		InProductionNums: m.InProductionNums,
+		InOrderNums:      m.InOrderNums,
+		HeldNums:         m.HeldNums,
+		CreateTime:       m.CreateTime,
+		ModifyTime:       m.ModifyTime,
+	}
+}
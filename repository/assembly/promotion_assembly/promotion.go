@@ -0,0 +1,75 @@
+package promotion_assembly
+
+import (
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/util"
+)
+
+func ConvertPDtoToModel(p *promotion_dto.Promotion) *model.Promotion {
+	return &model.Promotion{
+		ID:         p.ID,
+		UserID:     p.UserID,
+		Name:       p.Name,
+		Type:       p.Type,
+		Category:   p.Category,
+		Rule:       util.MarshalToStringNoErr(p.Rule),
+		CouponCode: p.CouponCode,
+		StartTime:  p.StartTime,
+		EndTime:    p.EndTime,
+		UsageLimit: p.UsageLimit,
+		UsedCount:  p.UsedCount,
+		Stackable:  p.Stackable,
+		Priority:   p.Priority,
+		Status:     p.Status,
+		CreateTime: p.CreateTime,
+		ModifyTime: p.ModifyTime,
+	}
+}
+
+func ConvertPModelToDto(p *model.Promotion) *promotion_dto.Promotion {
+	rule := &promotion_dto.PromotionRule{}
+	util.UnMarshalToStringNoErr(p.Rule, rule)
+	return &promotion_dto.Promotion{
+		ID:         p.ID,
+		UserID:     p.UserID,
+		Name:       p.Name,
+		Type:       p.Type,
+		Category:   p.Category,
+		Rule:       rule,
+		CouponCode: p.CouponCode,
+		StartTime:  p.StartTime,
+		EndTime:    p.EndTime,
+		UsageLimit: p.UsageLimit,
+		UsedCount:  p.UsedCount,
+		Stackable:  p.Stackable,
+		Priority:   p.Priority,
+		Status:     p.Status,
+		CreateTime: p.CreateTime,
+		ModifyTime: p.ModifyTime,
+	}
+}
+
+func ConvertCRDtoToModel(r *promotion_dto.CouponRedemption) *model.CouponRedemption {
+	return &model.CouponRedemption{
+		ID:          r.ID,
+		PromotionID: r.PromotionID,
+		CustomerID:  r.CustomerID,
+		OrderID:     r.OrderID,
+		CouponCode:  r.CouponCode,
+		Amount:      r.Amount,
+		CreateTime:  r.CreateTime,
+	}
+}
+
+func ConvertCRModelToDto(r *model.CouponRedemption) *promotion_dto.CouponRedemption {
+	return &promotion_dto.CouponRedemption{
+		ID:          r.ID,
+		PromotionID: r.PromotionID,
+		CustomerID:  r.CustomerID,
+		OrderID:     r.OrderID,
+		CouponCode:  r.CouponCode,
+		Amount:      r.Amount,
+		CreateTime:  r.CreateTime,
+	}
+}
@@ -0,0 +1,81 @@
+package quotation_assembly
+
+import (
+	"github.com/shop_management/dto/quotation_dto"
+	"github.com/shop_management/model"
+)
+
+func ConvertQuotationDtoToModel(q *quotation_dto.Quotation) *model.Quotation {
+	return &model.Quotation{
+		ID:           q.ID,
+		TeamID:       q.TeamID,
+		QuotationNo:  q.QuotationNo,
+		Version:      q.Version,
+		UserID:       q.UserID,
+		CustomerID:   q.CustomerID,
+		CustomerName: q.CustomerName,
+		Remark:       q.Remark,
+		Status:       q.Status,
+		Currency:     q.Currency,
+		HoldStock:    q.HoldStock,
+		TotalAmount:  q.TotalAmount,
+		NetAmount:    q.NetAmount,
+		TaxAmount:    q.TaxAmount,
+		ValidUntil:   q.ValidUntil,
+		OrderID:      q.OrderID,
+		CreateTime:   q.CreateTime,
+		ModifyTime:   q.ModifyTime,
+	}
+}
+
+func ConvertQuotationModelToDto(q *model.Quotation) *quotation_dto.Quotation {
+	return &quotation_dto.Quotation{
+		ID:           q.ID,
+		TeamID:       q.TeamID,
+		QuotationNo:  q.QuotationNo,
+		Version:      q.Version,
+		UserID:       q.UserID,
+		CustomerID:   q.CustomerID,
+		CustomerName: q.CustomerName,
+		Remark:       q.Remark,
+		Status:       q.Status,
+		Currency:     q.Currency,
+		HoldStock:    q.HoldStock,
+		TotalAmount:  q.TotalAmount,
+		NetAmount:    q.NetAmount,
+		TaxAmount:    q.TaxAmount,
+		ValidUntil:   q.ValidUntil,
+		OrderID:      q.OrderID,
+		Items:        make([]*quotation_dto.QuotationItem, 0),
+		CreateTime:   q.CreateTime,
+		ModifyTime:   q.ModifyTime,
+	}
+}
+
+func ConvertQuotationItemDtoToModel(i *quotation_dto.QuotationItem) *model.QuotationItem {
+	return &model.QuotationItem{
+		ID:          i.ID,
+		QuotationID: i.QuotationID,
+		ProductID:   i.ProductID,
+		Quantity:    i.Quantity,
+		UnitPrice:   i.UnitPrice,
+		Amount:      i.Amount,
+		TaxRate:     i.TaxRate,
+		NetAmount:   i.NetAmount,
+		TaxAmount:   i.TaxAmount,
+	}
+}
+
+func ConvertQuotationItemModelToDto(i *model.QuotationItem) *quotation_dto.QuotationItem {
+	return &quotation_dto.QuotationItem{
+		ID:          i.ID,
+		QuotationID: i.QuotationID,
+		ProductID:   i.ProductID,
+		Quantity:    i.Quantity,
+		UnitPrice:   i.UnitPrice,
+		Amount:      i.Amount,
+		TaxRate:     i.TaxRate,
+		NetAmount:   i.NetAmount,
+		TaxAmount:   i.TaxAmount,
+	}
+}
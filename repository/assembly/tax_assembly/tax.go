@@ -0,0 +1,50 @@
+package tax_assembly
+
+import (
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/model"
+)
+
+func ConvertTRDtoToModel(t *tax_dto.TaxRule) *model.TaxRule {
+	return &model.TaxRule{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		Category:   t.Category,
+		Rate:       t.Rate,
+		CreateTime: t.CreateTime,
+		ModifyTime: t.ModifyTime,
+	}
+}
+
+func ConvertTRModelToDto(t *model.TaxRule) *tax_dto.TaxRule {
+	return &tax_dto.TaxRule{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		Name:       t.Name,
+		Category:   t.Category,
+		Rate:       t.Rate,
+		CreateTime: t.CreateTime,
+		ModifyTime: t.ModifyTime,
+	}
+}
+
+func ConvertTEDtoToModel(t *tax_dto.TaxExemption) *model.TaxExemption {
+	return &model.TaxExemption{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		CustomerID: t.CustomerID,
+		Reason:     t.Reason,
+		CreateTime: t.CreateTime,
+	}
+}
+
+func ConvertTEModelToDto(t *model.TaxExemption) *tax_dto.TaxExemption {
+	return &tax_dto.TaxExemption{
+		ID:         t.ID,
+		UserID:     t.UserID,
+		CustomerID: t.CustomerID,
+		Reason:     t.Reason,
+		CreateTime: t.CreateTime,
+	}
+}
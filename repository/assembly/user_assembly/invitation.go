@@ -0,0 +1,36 @@
+package user_assembly
+
+import (
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/model"
+)
+
+func ConvertInvitationDtoToModel(i *user_dto.Invitation) *model.UserInvitation {
+	return &model.UserInvitation{
+		ID:         i.ID,
+		UserID:     i.UserID,
+		Phone:      i.Phone,
+		Email:      i.Email,
+		Role:       i.Role,
+		Status:     i.Status,
+		InviteeID:  i.InviteeID,
+		ExpireTime: i.ExpireTime,
+		CreateTime: i.CreateTime,
+		ModifyTime: i.ModifyTime,
+	}
+}
+
+func ConvertInvitationModelToDto(i *model.UserInvitation) *user_dto.Invitation {
+	return &user_dto.Invitation{
+		ID:         i.ID,
+		UserID:     i.UserID,
+		Phone:      i.Phone,
+		Email:      i.Email,
+		Role:       i.Role,
+		Status:     i.Status,
+		InviteeID:  i.InviteeID,
+		ExpireTime: i.ExpireTime,
+		CreateTime: i.CreateTime,
+		ModifyTime: i.ModifyTime,
+	}
+}
@@ -0,0 +1,32 @@
+package user_assembly
+
+import (
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/model"
+)
+
+func ConvertLoginLockoutDtoToModel(l *user_dto.LoginLockout) *model.LoginLockout {
+	return &model.LoginLockout{
+		ID:          l.ID,
+		TeamID:      l.TeamID,
+		UserID:      l.UserID,
+		Phone:       l.Phone,
+		Ip:          l.Ip,
+		UserAgent:   l.UserAgent,
+		LockedUntil: l.LockedUntil,
+		CreateTime:  l.CreateTime,
+	}
+}
+
+func ConvertLoginLockoutModelToDto(l *model.LoginLockout) *user_dto.LoginLockout {
+	return &user_dto.LoginLockout{
+		ID:          l.ID,
+		TeamID:      l.TeamID,
+		UserID:      l.UserID,
+		Phone:       l.Phone,
+		Ip:          l.Ip,
+		UserAgent:   l.UserAgent,
+		LockedUntil: l.LockedUntil,
+		CreateTime:  l.CreateTime,
+	}
+}
@@ -7,26 +7,36 @@ import (
 
 func ConvertUDtoToModel(u *user_dto.User) *model.User {
 	return &model.User{
-		Id:         u.Id,
-		Name:       u.Name,
-		Email:      u.Email,
-		Password:   u.Password,
-		CreateTime: u.CreateTime,
-		ModifyTime: u.ModifyTime,
-		AvatarUrl:  u.AvatarUrl,
-		Phone:      u.Phone,
+		Id:                u.Id,
+		Name:              u.Name,
+		Email:             u.Email,
+		Password:          u.Password,
+		CreateTime:        u.CreateTime,
+		ModifyTime:        u.ModifyTime,
+		AvatarUrl:         u.AvatarUrl,
+		Phone:             u.Phone,
+		DeletedTime:       u.DeletedTime,
+		DeactivatedTeamId: u.DeactivatedTeamId,
+		DeactivatedRole:   u.DeactivatedRole,
+		DisabledTime:      u.DisabledTime,
+		PlatformAdmin:     u.PlatformAdmin,
 	}
 }
 
 func ConvertUModelToDto(u *model.User) *user_dto.User {
 	return &user_dto.User{
-		Id:         u.Id,
-		Name:       u.Name,
-		Email:      u.Email,
-		Password:   u.Password,
-		CreateTime: u.CreateTime,
-		ModifyTime: u.ModifyTime,
-		Phone:      u.Phone,
-		AvatarUrl:  u.AvatarUrl,
+		Id:                u.Id,
+		Name:              u.Name,
+		Email:             u.Email,
+		Password:          u.Password,
+		CreateTime:        u.CreateTime,
+		ModifyTime:        u.ModifyTime,
+		Phone:             u.Phone,
+		AvatarUrl:         u.AvatarUrl,
+		DeletedTime:       u.DeletedTime,
+		DeactivatedTeamId: u.DeactivatedTeamId,
+		DeactivatedRole:   u.DeactivatedRole,
+		DisabledTime:      u.DisabledTime,
+		PlatformAdmin:     u.PlatformAdmin,
 	}
 }
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/audit_dto"
+	"gorm.io/gorm"
+)
+
+type AuditRepo interface {
+	Add(ctx *gin.Context, db *gorm.DB, dto *audit_dto.AuditLog) error
+	List(ctx *gin.Context, db *gorm.DB, req *audit_dto.AuditListReq) (*audit_dto.AuditListResp, error)
+}
@@ -0,0 +1,83 @@
+package audit_repo
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shop_management/dto/audit_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/audit_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type auditRepoImpl struct {
+}
+
+func NewAuditRepoImpl() repository.AuditRepo {
+	return &auditRepoImpl{}
+}
+
+// Add 显式写入一条记录, 用于不伴随数据变更的操作. 团队id以传入的为准, 不受团队隔离影响
+func (a *auditRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *audit_dto.AuditLog) error {
+	m := audit_assembly.ConvertAuditDtoToModel(dto)
+	m.ID = uuid.NewString()
+	m.CreateTime = time.Now()
+	err := util.WithoutTeamScope(db).Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("auditRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	dto.CreateTime = m.CreateTime
+	return nil
+}
+
+func (a *auditRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *audit_dto.AuditListReq) (*audit_dto.AuditListResp, error) {
+	query := db.Model(&model.AuditLog{})
+	if req.ActorID != "" {
+		query = query.Where("actor_id = ?", req.ActorID)
+	}
+	if req.Action != "" {
+		query = query.Where("action = ?", req.Action)
+	}
+	if req.EntityType != "" {
+		query = query.Where("entity_type = ?", req.EntityType)
+	}
+	if req.EntityID != "" {
+		query = query.Where("entity_id = ?", req.EntityID)
+	}
+	if req.RequestID != "" {
+		query = query.Where("request_id = ?", req.RequestID)
+	}
+	if !req.StartTime.IsZero() {
+		query = query.Where("create_time >= ?", req.StartTime)
+	}
+	if !req.EndTime.IsZero() {
+		query = query.Where("create_time < ?", req.EndTime)
+	}
+	if err := query.Count(&req.Pager.TotalRows).Error; err != nil {
+		vars.Log.Errorf("auditRepoImpl.List count error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	offset := (req.Pager.Page - 1) * req.Pager.PageSize
+
+	mList := make([]*model.AuditLog, 0)
+	err := query.Offset(int(offset)).Limit(int(req.Pager.PageSize)).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("auditRepoImpl.List Find error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*audit_dto.AuditLog, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, audit_assembly.ConvertAuditModelToDto(m))
+	}
+	return &audit_dto.AuditListResp{
+		Pager: req.Pager,
+		List:  list,
+	}, nil
+}
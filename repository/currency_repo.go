@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/currency_dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+type CurrencyRepo interface {
+	SaveRate(ctx *gin.Context, db *gorm.DB, dto *currency_dto.ExchangeRate) error
+	ListRates(ctx *gin.Context, db *gorm.DB, req *currency_dto.RateListReq) ([]*currency_dto.ExchangeRate, error)
+	GetRate(ctx *gin.Context, db *gorm.DB, userId string, from string, to string, at time.Time) (*currency_dto.ExchangeRate, error)
+	GetSetting(ctx *gin.Context, db *gorm.DB, userId string) (*currency_dto.CurrencySetting, error)
+	SaveSetting(ctx *gin.Context, db *gorm.DB, dto *currency_dto.CurrencySetting) error
+}
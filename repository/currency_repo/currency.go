@@ -0,0 +1,127 @@
+package currency_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/currency_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/currency_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type currencyRepoImpl struct {
+}
+
+func NewCurrencyRepoImpl() repository.CurrencyRepo {
+	return &currencyRepoImpl{}
+}
+
+// SaveRate 同一币种对同一天只保留一条汇率, 重复录入或导入时覆盖. 按唯一索引 uk_user_pair_date 在一条语句中插入或更新,
+// 并发录入同一天的汇率不会因唯一索引冲突而失败
+func (c *currencyRepoImpl) SaveRate(ctx *gin.Context, db *gorm.DB, dto *currency_dto.ExchangeRate) error {
+	m := currency_assembly.ConvertERDtoToModel(dto)
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "from_currency"}, {Name: "to_currency"}, {Name: "effective_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "modify_time"}),
+	}).Create(m)
+	if result.Error != nil {
+		vars.Log.Errorf("currencyRepoImpl.SaveRate error:%v,data: %v", result.Error, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	// MySQL 插入新行时影响行数为1, 覆盖已有记录时新生成的 id 未被使用, 需按唯一索引取回原记录的 id
+	if result.RowsAffected == 1 {
+		dto.ID = m.ID
+		return nil
+	}
+	var id string
+	err := db.Model(&model.ExchangeRate{}).
+		Where("user_id = ? and from_currency = ? and to_currency = ? and effective_date = ?", dto.UserID, dto.FromCurrency, dto.ToCurrency, dto.EffectiveDate).
+		Pluck("id", &id).Error
+	if err != nil {
+		vars.Log.Errorf("currencyRepoImpl.SaveRate get id error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = id
+	return nil
+}
+
+func (c *currencyRepoImpl) ListRates(ctx *gin.Context, db *gorm.DB, req *currency_dto.RateListReq) ([]*currency_dto.ExchangeRate, error) {
+	query := db.Where("user_id = ?", req.UserID)
+	if req.FromCurrency != "" {
+		query = query.Where("from_currency = ?", req.FromCurrency)
+	}
+	if req.ToCurrency != "" {
+		query = query.Where("to_currency = ?", req.ToCurrency)
+	}
+	mList := make([]*model.ExchangeRate, 0)
+	err := query.Order("effective_date desc, from_currency asc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("currencyRepoImpl.ListRates error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*currency_dto.ExchangeRate, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, currency_assembly.ConvertERModelToDto(m))
+	}
+	return list, nil
+}
+
+// GetRate 取生效日期不晚于 at 的最近一条汇率
+func (c *currencyRepoImpl) GetRate(ctx *gin.Context, db *gorm.DB, userId string, from string, to string, at time.Time) (*currency_dto.ExchangeRate, error) {
+	m := &model.ExchangeRate{}
+	err := db.Where("user_id = ? and from_currency = ? and to_currency = ? and effective_date <= ?", userId, from, to, at).
+		Order("effective_date desc").First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("currencyRepoImpl.GetRate error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return currency_assembly.ConvertERModelToDto(m), nil
+}
+
+func (c *currencyRepoImpl) GetSetting(ctx *gin.Context, db *gorm.DB, userId string) (*currency_dto.CurrencySetting, error) {
+	m := &model.CurrencySetting{}
+	err := db.Where("user_id = ?", userId).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &currency_dto.CurrencySetting{UserID: userId, BaseCurrency: currency_dto.DefaultBaseCurrency}, nil
+		}
+		vars.Log.Errorf("currencyRepoImpl.GetSetting error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return &currency_dto.CurrencySetting{
+		UserID:       m.UserID,
+		BaseCurrency: m.BaseCurrency,
+	}, nil
+}
+
+func (c *currencyRepoImpl) SaveSetting(ctx *gin.Context, db *gorm.DB, dto *currency_dto.CurrencySetting) error {
+	exists := int64(0)
+	err := db.Model(&model.CurrencySetting{}).Where("user_id = ?", dto.UserID).Count(&exists).Error
+	if err != nil {
+		vars.Log.Errorf("currencyRepoImpl.SaveSetting Count error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	if exists > 0 {
+		err = db.Model(&model.CurrencySetting{}).Where("user_id = ?", dto.UserID).Update("base_currency", dto.BaseCurrency).Error
+	} else {
+		err = db.Create(&model.CurrencySetting{
+			UserID:       dto.UserID,
+			BaseCurrency: dto.BaseCurrency,
+		}).Error
+	}
+	if err != nil {
+		vars.Log.Errorf("currencyRepoImpl.SaveSetting error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/fulfillment_dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+type FulfillmentRepo interface {
+	AddPickTasks(ctx *gin.Context, db *gorm.DB, tasks []*fulfillment_dto.PickTask) error
+	GetPickTaskById(ctx *gin.Context, db *gorm.DB, id string) (*fulfillment_dto.PickTask, error)
+	ListPickTask(ctx *gin.Context, db *gorm.DB, req *fulfillment_dto.PickTaskListReq) ([]*fulfillment_dto.PickTask, error)
+	ClaimPickTask(ctx *gin.Context, db *gorm.DB, id string, assigneeId string, claimTime time.Time) (bool, error)
+	FinishPickTask(ctx *gin.Context, db *gorm.DB, id string, pickedTime time.Time) (bool, error)
+	AddPackRecord(ctx *gin.Context, db *gorm.DB, dto *fulfillment_dto.PackRecord) error
+	GetPackRecordByOrderId(ctx *gin.Context, db *gorm.DB, orderId string) (*fulfillment_dto.PackRecord, error)
+	AddShipment(ctx *gin.Context, db *gorm.DB, dto *fulfillment_dto.Shipment) error
+	GetShipmentByOrderId(ctx *gin.Context, db *gorm.DB, orderId string) (*fulfillment_dto.Shipment, error)
+}
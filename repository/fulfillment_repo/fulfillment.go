@@ -0,0 +1,189 @@
+package fulfillment_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/fulfillment_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/fulfillment_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type fulfillmentRepoImpl struct {
+}
+
+func NewFulfillmentRepoImpl() repository.FulfillmentRepo {
+	return &fulfillmentRepoImpl{}
+}
+
+func (f *fulfillmentRepoImpl) AddPickTasks(ctx *gin.Context, db *gorm.DB, tasks []*fulfillment_dto.PickTask) error {
+	for _, task := range tasks {
+		m := fulfillment_assembly.ConvertPTDtoToModel(task)
+		err := db.Create(m).Error
+		if err != nil {
+			vars.Log.Errorf("fulfillmentRepoImpl.AddPickTasks error:%v,data: %v", err, util.MarshalToStringNoErr(task))
+			return sm_error.NewHttpError(error_code.DBError)
+		}
+		task.ID = m.ID
+		if len(task.Items) == 0 {
+			continue
+		}
+		items := make([]*model.PickTaskItem, 0, len(task.Items))
+		for _, item := range task.Items {
+			item.TaskID = m.ID
+			items = append(items, fulfillment_assembly.ConvertPTIDtoToModel(item))
+		}
+		err = db.Create(&items).Error
+		if err != nil {
+			vars.Log.Errorf("fulfillmentRepoImpl.AddPickTasks items error:%v,data: %v", err, util.MarshalToStringNoErr(task))
+			return sm_error.NewHttpError(error_code.DBError)
+		}
+	}
+	return nil
+}
+
+func (f *fulfillmentRepoImpl) GetPickTaskById(ctx *gin.Context, db *gorm.DB, id string) (*fulfillment_dto.PickTask, error) {
+	m := &model.PickTask{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("fulfillmentRepoImpl.GetPickTaskById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	tasks, err := f.fillItems(db, []*model.PickTask{m})
+	if err != nil {
+		return nil, err
+	}
+	return tasks[0], nil
+}
+
+func (f *fulfillmentRepoImpl) ListPickTask(ctx *gin.Context, db *gorm.DB, req *fulfillment_dto.PickTaskListReq) ([]*fulfillment_dto.PickTask, error) {
+	query := db.Model(&model.PickTask{})
+	if req.OrderID != "" {
+		query = query.Where("order_id = ?", req.OrderID)
+	}
+	if req.AssigneeID != "" {
+		query = query.Where("assignee_id = ?", req.AssigneeID)
+	}
+	if req.Status != 0 {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Warehouses != nil {
+		query = query.Where("warehouse in ?", req.Warehouses)
+	}
+	mList := make([]*model.PickTask, 0)
+	err := query.Order("warehouse asc, storage_pos asc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("fulfillmentRepoImpl.ListPickTask error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return f.fillItems(db, mList)
+}
+
+func (f *fulfillmentRepoImpl) fillItems(db *gorm.DB, mList []*model.PickTask) ([]*fulfillment_dto.PickTask, error) {
+	list := make([]*fulfillment_dto.PickTask, 0, len(mList))
+	if len(mList) == 0 {
+		return list, nil
+	}
+	taskMap := make(map[string]*fulfillment_dto.PickTask)
+	taskIds := make([]string, 0, len(mList))
+	for _, m := range mList {
+		task := fulfillment_assembly.ConvertPTModelToDto(m)
+		taskMap[task.ID] = task
+		taskIds = append(taskIds, task.ID)
+		list = append(list, task)
+	}
+	items := make([]*model.PickTaskItem, 0)
+	err := db.Where("task_id in ?", taskIds).Find(&items).Error
+	if err != nil {
+		vars.Log.Errorf("fulfillmentRepoImpl.fillItems error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	for _, item := range items {
+		if task, ok := taskMap[item.TaskID]; ok {
+			task.Items = append(task.Items, fulfillment_assembly.ConvertPTIModelToDto(item))
+		}
+	}
+	return list, nil
+}
+
+func (f *fulfillmentRepoImpl) ClaimPickTask(ctx *gin.Context, db *gorm.DB, id string, assigneeId string, claimTime time.Time) (bool, error) {
+	result := db.Model(&model.PickTask{}).Where("id = ? and status = ?", id, fulfillment_dto.PickTaskStatusPending).Updates(map[string]interface{}{
+		"status":      fulfillment_dto.PickTaskStatusClaimed,
+		"assignee_id": assigneeId,
+		"claim_time":  claimTime,
+	})
+	if result.Error != nil {
+		vars.Log.Errorf("fulfillmentRepoImpl.ClaimPickTask error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (f *fulfillmentRepoImpl) FinishPickTask(ctx *gin.Context, db *gorm.DB, id string, pickedTime time.Time) (bool, error) {
+	result := db.Model(&model.PickTask{}).Where("id = ? and status = ?", id, fulfillment_dto.PickTaskStatusClaimed).Updates(map[string]interface{}{
+		"status":      fulfillment_dto.PickTaskStatusPicked,
+		"picked_time": pickedTime,
+	})
+	if result.Error != nil {
+		vars.Log.Errorf("fulfillmentRepoImpl.FinishPickTask error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (f *fulfillmentRepoImpl) AddPackRecord(ctx *gin.Context, db *gorm.DB, dto *fulfillment_dto.PackRecord) error {
+	m := fulfillment_assembly.ConvertPRDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("fulfillmentRepoImpl.AddPackRecord error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (f *fulfillmentRepoImpl) GetPackRecordByOrderId(ctx *gin.Context, db *gorm.DB, orderId string) (*fulfillment_dto.PackRecord, error) {
+	m := &model.PackRecord{}
+	err := db.Where("order_id = ?", orderId).Order("pack_time desc").First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("fulfillmentRepoImpl.GetPackRecordByOrderId error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return fulfillment_assembly.ConvertPRModelToDto(m), nil
+}
+
+func (f *fulfillmentRepoImpl) AddShipment(ctx *gin.Context, db *gorm.DB, dto *fulfillment_dto.Shipment) error {
+	m := fulfillment_assembly.ConvertSDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("fulfillmentRepoImpl.AddShipment error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (f *fulfillmentRepoImpl) GetShipmentByOrderId(ctx *gin.Context, db *gorm.DB, orderId string) (*fulfillment_dto.Shipment, error) {
+	m := &model.Shipment{}
+	err := db.Where("order_id = ?", orderId).Order("ship_time desc").First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("fulfillmentRepoImpl.GetShipmentByOrderId error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return fulfillment_assembly.ConvertSModelToDto(m), nil
+}
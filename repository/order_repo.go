@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/order_dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+type OrderRepo interface {
+	Add(ctx *gin.Context, db *gorm.DB, dto *order_dto.SalesOrder) error
+	GetById(ctx *gin.Context, db *gorm.DB, id string) (*order_dto.SalesOrder, error)
+	List(ctx *gin.Context, db *gorm.DB, req *order_dto.OrderListReq) ([]*order_dto.SalesOrder, error)
+	UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, fromStatus int, toStatus int) (bool, error)
+	Confirm(ctx *gin.Context, db *gorm.DB, id string, confirmTime time.Time) (bool, error)
+	ListByCreateTime(ctx *gin.Context, db *gorm.DB, start time.Time, end time.Time, statuses []int) ([]*order_dto.SalesOrder, error)
+}
@@ -0,0 +1,133 @@
+package order_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/order_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type orderRepoImpl struct {
+}
+
+func NewOrderRepoImpl() repository.OrderRepo {
+	return &orderRepoImpl{}
+}
+
+func (o *orderRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *order_dto.SalesOrder) error {
+	m := order_assembly.ConvertSODtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("orderRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	if len(dto.Items) == 0 {
+		return nil
+	}
+	items := make([]*model.SalesOrderItem, 0, len(dto.Items))
+	for _, item := range dto.Items {
+		item.OrderID = m.ID
+		items = append(items, order_assembly.ConvertSOIDtoToModel(item))
+	}
+	err = db.Create(&items).Error
+	if err != nil {
+		vars.Log.Errorf("orderRepoImpl.Add items error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	for i, item := range items {
+		dto.Items[i].ID = item.ID
+	}
+	return nil
+}
+
+func (o *orderRepoImpl) GetById(ctx *gin.Context, db *gorm.DB, id string) (*order_dto.SalesOrder, error) {
+	m := &model.SalesOrder{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("orderRepoImpl.GetById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	items := make([]*model.SalesOrderItem, 0)
+	err = db.Where("order_id = ?", id).Order("create_time asc").Find(&items).Error
+	if err != nil {
+		vars.Log.Errorf("orderRepoImpl.GetById items error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	order := order_assembly.ConvertSOModelToDto(m)
+	for _, item := range items {
+		order.Items = append(order.Items, order_assembly.ConvertSOIModelToDto(item))
+	}
+	return order, nil
+}
+
+func (o *orderRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *order_dto.OrderListReq) ([]*order_dto.SalesOrder, error) {
+	db = db.Model(&model.SalesOrder{})
+	if req.Status != 0 {
+		db = db.Where("status = ?", req.Status)
+	}
+	if err := db.Count(&req.Pager.TotalRows).Error; err != nil {
+		vars.Log.Errorf("orderRepoImpl.List count error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	offset := (req.Pager.Page - 1) * req.Pager.PageSize
+
+	mList := make([]*model.SalesOrder, 0)
+	err := db.Offset(int(offset)).Limit(int(req.Pager.PageSize)).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("orderRepoImpl.List Find error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*order_dto.SalesOrder, 0)
+	for _, m := range mList {
+		list = append(list, order_assembly.ConvertSOModelToDto(m))
+	}
+	return list, nil
+}
+
+func (o *orderRepoImpl) UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, fromStatus int, toStatus int) (bool, error) {
+	result := db.Model(&model.SalesOrder{}).Where("id = ? and status = ?", id, fromStatus).Update("status", toStatus)
+	if result.Error != nil {
+		vars.Log.Errorf("orderRepoImpl.UpdateStatus error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (o *orderRepoImpl) Confirm(ctx *gin.Context, db *gorm.DB, id string, confirmTime time.Time) (bool, error) {
+	result := db.Model(&model.SalesOrder{}).Where("id = ? and status = ?", id, order_dto.OrderStatusDraft).Updates(map[string]interface{}{
+		"status":       order_dto.OrderStatusConfirmed,
+		"confirm_time": confirmTime,
+	})
+	if result.Error != nil {
+		vars.Log.Errorf("orderRepoImpl.Confirm error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (o *orderRepoImpl) ListByCreateTime(ctx *gin.Context, db *gorm.DB, start time.Time, end time.Time, statuses []int) ([]*order_dto.SalesOrder, error) {
+	mList := make([]*model.SalesOrder, 0)
+	err := db.Where("create_time >= ? and create_time < ? and status in ?", start, end, statuses).
+		Order("create_time asc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("orderRepoImpl.ListByCreateTime error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*order_dto.SalesOrder, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, order_assembly.ConvertSOModelToDto(m))
+	}
+	return list, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/pos_dto"
+	"gorm.io/gorm"
+)
+
+type PosRepo interface {
+	AddShift(ctx *gin.Context, db *gorm.DB, dto *pos_dto.PosShift) error
+	GetOpenShift(ctx *gin.Context, db *gorm.DB, userId string) (*pos_dto.PosShift, error)
+	CloseShift(ctx *gin.Context, db *gorm.DB, dto *pos_dto.PosShift) (bool, error)
+	AddPayments(ctx *gin.Context, db *gorm.DB, payments []*pos_dto.PosPayment) error
+	ListPaymentByShiftId(ctx *gin.Context, db *gorm.DB, shiftId string) ([]*pos_dto.PosPayment, error)
+}
@@ -0,0 +1,123 @@
+package pos_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/pos_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+)
+
+type posRepoImpl struct {
+}
+
+func NewPosRepoImpl() repository.PosRepo {
+	return &posRepoImpl{}
+}
+
+func (p *posRepoImpl) AddShift(ctx *gin.Context, db *gorm.DB, dto *pos_dto.PosShift) error {
+	m := &model.PosShift{
+		UserID:      dto.UserID,
+		Status:      dto.Status,
+		Currency:    dto.Currency,
+		OpeningCash: dto.OpeningCash,
+		OpenTime:    dto.OpenTime,
+	}
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("posRepoImpl.AddShift error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (p *posRepoImpl) GetOpenShift(ctx *gin.Context, db *gorm.DB, userId string) (*pos_dto.PosShift, error) {
+	m := &model.PosShift{}
+	err := db.Where("user_id = ? and status = ?", userId, pos_dto.ShiftStatusOpen).Order("open_time desc").First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("posRepoImpl.GetOpenShift error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return &pos_dto.PosShift{
+		ID:             m.ID,
+		UserID:         m.UserID,
+		Status:         m.Status,
+		Currency:       m.Currency,
+		OpeningCash:    m.OpeningCash,
+		ClosingCash:    m.ClosingCash,
+		ExpectedCash:   m.ExpectedCash,
+		CashDifference: m.CashDifference,
+		OpenTime:       m.OpenTime,
+		CloseTime:      m.CloseTime,
+	}, nil
+}
+
+func (p *posRepoImpl) CloseShift(ctx *gin.Context, db *gorm.DB, dto *pos_dto.PosShift) (bool, error) {
+	result := db.Model(&model.PosShift{}).Where("id = ? and status = ?", dto.ID, pos_dto.ShiftStatusOpen).Updates(map[string]interface{}{
+		"status":          pos_dto.ShiftStatusClosed,
+		"closing_cash":    dto.ClosingCash,
+		"expected_cash":   dto.ExpectedCash,
+		"cash_difference": dto.CashDifference,
+		"close_time":      dto.CloseTime,
+	})
+	if result.Error != nil {
+		vars.Log.Errorf("posRepoImpl.CloseShift error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (p *posRepoImpl) AddPayments(ctx *gin.Context, db *gorm.DB, payments []*pos_dto.PosPayment) error {
+	if len(payments) == 0 {
+		return nil
+	}
+	mList := make([]*model.PosPayment, 0, len(payments))
+	for _, payment := range payments {
+		mList = append(mList, &model.PosPayment{
+			ShiftID:      payment.ShiftID,
+			OrderID:      payment.OrderID,
+			TenderType:   payment.TenderType,
+			Amount:       payment.Amount,
+			ChangeAmount: payment.ChangeAmount,
+		})
+	}
+	err := db.Create(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("posRepoImpl.AddPayments error:%v,data: %v", err, util.MarshalToStringNoErr(payments))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	for i, m := range mList {
+		payments[i].ID = m.ID
+	}
+	return nil
+}
+
+func (p *posRepoImpl) ListPaymentByShiftId(ctx *gin.Context, db *gorm.DB, shiftId string) ([]*pos_dto.PosPayment, error) {
+	mList := make([]*model.PosPayment, 0)
+	err := db.Where("shift_id = ?", shiftId).Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("posRepoImpl.ListPaymentByShiftId error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*pos_dto.PosPayment, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, &pos_dto.PosPayment{
+			ID:           m.ID,
+			ShiftID:      m.ShiftID,
+			OrderID:      m.OrderID,
+			TenderType:   m.TenderType,
+			Amount:       m.Amount,
+			ChangeAmount: m.ChangeAmount,
+		})
+	}
+	return list, nil
+}
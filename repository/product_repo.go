@@ -8,4 +8,11 @@ import (
 
 type ProductRepo interface {
 	AddProduct(ctx *gin.Context, db *gorm.DB, dto *product_dto.Product) error
+	GetByIds(ctx *gin.Context, db *gorm.DB, ids []string) ([]*product_dto.Product, error)
+	GetByCodes(ctx *gin.Context, db *gorm.DB, codes []string) ([]*product_dto.Product, error)
+	DecrStock(ctx *gin.Context, db *gorm.DB, id string, nums int) (bool, error)
+	HoldStock(ctx *gin.Context, db *gorm.DB, id string, nums int) (bool, error)
+	ReleaseStock(ctx *gin.Context, db *gorm.DB, id string, nums int) error
+	ListInStock(ctx *gin.Context, db *gorm.DB, warehouses []string) ([]*product_dto.Product, error)
+	List(ctx *gin.Context, db *gorm.DB, req *product_dto.ProductListReq) ([]*product_dto.Product, error)
 }
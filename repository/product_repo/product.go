@@ -5,6 +5,11 @@ import (
 	"github.com/shop_management/dto/product_dto"
 	"github.com/shop_management/model"
 	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/product_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
 	"gorm.io/gorm"
 )
 
@@ -20,12 +25,17 @@ func (p *productRepoImpl) AddProduct(ctx *gin.Context, db *gorm.DB, dto *product
 		ID:               dto.ID,
 		ImageURL:         dto.ImageURL,
 		StorageCode:      dto.StorageCode,
+		Barcode:          dto.Barcode,
 		StoragePos:       dto.StoragePos,
+		Warehouse:        dto.Warehouse,
 		Name:             dto.Name,
 		Color:            dto.Color,
+		Category:         dto.Category,
 		BasePrice:        dto.BasePrice,
+		SaleCurrency:     dto.SaleCurrency,
 		CostPrice:        dto.CostPrice,
 		PurchasePrice:    dto.PurchasePrice,
+		PurchaseCurrency: dto.PurchaseCurrency,
 		Factory:          dto.Factory,
 		Stock:            dto.Stock,
 		InProductionNums: dto.InProductionNums,
@@ -40,3 +50,116 @@ func (p *productRepoImpl) AddProduct(ctx *gin.Context, db *gorm.DB, dto *product
 	}
 	return nil
 }
+
+func (p *productRepoImpl) GetByIds(ctx *gin.Context, db *gorm.DB, ids []string) ([]*product_dto.Product, error) {
+	list := make([]*product_dto.Product, 0)
+	if len(ids) == 0 {
+		return list, nil
+	}
+	mList := make([]*model.Product, 0)
+	err := db.Where("id in ?", ids).Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("productRepoImpl.GetByIds error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	for _, m := range mList {
+		list = append(list, product_assembly.ConvertPModelToDto(m))
+	}
+	return list, nil
+}
+
+// GetByCodes 按库位码或条码查询商品
+func (p *productRepoImpl) GetByCodes(ctx *gin.Context, db *gorm.DB, codes []string) ([]*product_dto.Product, error) {
+	list := make([]*product_dto.Product, 0)
+	if len(codes) == 0 {
+		return list, nil
+	}
+	mList := make([]*model.Product, 0)
+	err := db.Where("storage_code in ? or barcode in ?", codes, codes).Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("productRepoImpl.GetByCodes error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	for _, m := range mList {
+		list = append(list, product_assembly.ConvertPModelToDto(m))
+	}
+	return list, nil
+}
+
+// DecrStock 库存不足时返回 false
+func (p *productRepoImpl) DecrStock(ctx *gin.Context, db *gorm.DB, id string, nums int) (bool, error) {
+	result := db.Model(&model.Product{}).Where("id = ? and stock >= ?", id, nums).Update("stock", gorm.Expr("stock - ?", nums))
+	if result.Error != nil {
+		vars.Log.Errorf("productRepoImpl.DecrStock error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// HoldStock 预占库存, 可用库存(stock - held_nums)不足时返回 false
+func (p *productRepoImpl) HoldStock(ctx *gin.Context, db *gorm.DB, id string, nums int) (bool, error) {
+	result := db.Model(&model.Product{}).Where("id = ? and stock - held_nums >= ?", id, nums).Update("held_nums", gorm.Expr("held_nums + ?", nums))
+	if result.Error != nil {
+		vars.Log.Errorf("productRepoImpl.HoldStock error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (p *productRepoImpl) ReleaseStock(ctx *gin.Context, db *gorm.DB, id string, nums int) error {
+	err := db.Model(&model.Product{}).Where("id = ?", id).Update("held_nums", gorm.Expr("greatest(held_nums - ?, 0)", nums)).Error
+	if err != nil {
+		vars.Log.Errorf("productRepoImpl.ReleaseStock error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+// ListInStock warehouses 为 nil 时不限制仓库
+func (p *productRepoImpl) ListInStock(ctx *gin.Context, db *gorm.DB, warehouses []string) ([]*product_dto.Product, error) {
+	db = db.Where("stock > 0")
+	if warehouses != nil {
+		db = db.Where("warehouse in ?", warehouses)
+	}
+	mList := make([]*model.Product, 0)
+	err := db.Order("create_time asc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("productRepoImpl.ListInStock error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*product_dto.Product, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, product_assembly.ConvertPModelToDto(m))
+	}
+	return list, nil
+}
+
+func (p *productRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *product_dto.ProductListReq) ([]*product_dto.Product, error) {
+	db = db.Model(&model.Product{})
+	if req.Category != "" {
+		db = db.Where("category = ?", req.Category)
+	}
+	if req.Warehouse != "" {
+		db = db.Where("warehouse = ?", req.Warehouse)
+	}
+	if req.Warehouses != nil {
+		db = db.Where("warehouse in ?", req.Warehouses)
+	}
+	if err := db.Count(&req.Pager.TotalRows).Error; err != nil {
+		vars.Log.Errorf("productRepoImpl.List count error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	offset := (req.Pager.Page - 1) * req.Pager.PageSize
+
+	mList := make([]*model.Product, 0)
+	err := db.Offset(int(offset)).Limit(int(req.Pager.PageSize)).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("productRepoImpl.List Find error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*product_dto.Product, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, product_assembly.ConvertPModelToDto(m))
+	}
+	return list, nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/promotion_dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+type PromotionRepo interface {
+	Add(ctx *gin.Context, db *gorm.DB, dto *promotion_dto.Promotion) error
+	GetById(ctx *gin.Context, db *gorm.DB, id string) (*promotion_dto.Promotion, error)
+	List(ctx *gin.Context, db *gorm.DB, userId string) ([]*promotion_dto.Promotion, error)
+	ListActive(ctx *gin.Context, db *gorm.DB, userId string, now time.Time) ([]*promotion_dto.Promotion, error)
+	UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, status int) error
+	IncrUsage(ctx *gin.Context, db *gorm.DB, id string) (bool, error)
+	GetRedemption(ctx *gin.Context, db *gorm.DB, promotionId string, customerId string) (*promotion_dto.CouponRedemption, error)
+	AddRedemption(ctx *gin.Context, db *gorm.DB, dto *promotion_dto.CouponRedemption) error
+}
@@ -0,0 +1,121 @@
+package promotion_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/promotion_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type promotionRepoImpl struct {
+}
+
+func NewPromotionRepoImpl() repository.PromotionRepo {
+	return &promotionRepoImpl{}
+}
+
+func (p *promotionRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *promotion_dto.Promotion) error {
+	m := promotion_assembly.ConvertPDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("promotionRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (p *promotionRepoImpl) GetById(ctx *gin.Context, db *gorm.DB, id string) (*promotion_dto.Promotion, error) {
+	m := &model.Promotion{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("promotionRepoImpl.GetById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return promotion_assembly.ConvertPModelToDto(m), nil
+}
+
+func (p *promotionRepoImpl) List(ctx *gin.Context, db *gorm.DB, userId string) ([]*promotion_dto.Promotion, error) {
+	mList := make([]*model.Promotion, 0)
+	err := db.Where("user_id = ?", userId).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("promotionRepoImpl.List error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*promotion_dto.Promotion, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, promotion_assembly.ConvertPModelToDto(m))
+	}
+	return list, nil
+}
+
+func (p *promotionRepoImpl) ListActive(ctx *gin.Context, db *gorm.DB, userId string, now time.Time) ([]*promotion_dto.Promotion, error) {
+	mList := make([]*model.Promotion, 0)
+	err := db.Where("user_id = ? and status = ? and start_time <= ? and end_time >= ?", userId, promotion_dto.PromotionStatusEnabled, now, now).
+		Where("usage_limit = 0 or used_count < usage_limit").
+		Order("priority desc, create_time asc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("promotionRepoImpl.ListActive error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*promotion_dto.Promotion, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, promotion_assembly.ConvertPModelToDto(m))
+	}
+	return list, nil
+}
+
+func (p *promotionRepoImpl) UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, status int) error {
+	err := db.Model(&model.Promotion{}).Where("id = ?", id).Update("status", status).Error
+	if err != nil {
+		vars.Log.Errorf("promotionRepoImpl.UpdateStatus error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+// IncrUsage 使用次数达到上限时返回 false
+func (p *promotionRepoImpl) IncrUsage(ctx *gin.Context, db *gorm.DB, id string) (bool, error) {
+	result := db.Model(&model.Promotion{}).Where("id = ? and (usage_limit = 0 or used_count < usage_limit)", id).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		vars.Log.Errorf("promotionRepoImpl.IncrUsage error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (p *promotionRepoImpl) GetRedemption(ctx *gin.Context, db *gorm.DB, promotionId string, customerId string) (*promotion_dto.CouponRedemption, error) {
+	m := &model.CouponRedemption{}
+	err := db.Where("promotion_id = ? and customer_id = ?", promotionId, customerId).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("promotionRepoImpl.GetRedemption error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return promotion_assembly.ConvertCRModelToDto(m), nil
+}
+
+func (p *promotionRepoImpl) AddRedemption(ctx *gin.Context, db *gorm.DB, dto *promotion_dto.CouponRedemption) error {
+	m := promotion_assembly.ConvertCRDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("promotionRepoImpl.AddRedemption error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
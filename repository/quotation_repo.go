@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/quotation_dto"
+	"gorm.io/gorm"
+	"time"
+)
+
+type QuotationRepo interface {
+	Add(ctx *gin.Context, db *gorm.DB, dto *quotation_dto.Quotation) error
+	GetById(ctx *gin.Context, db *gorm.DB, id string) (*quotation_dto.Quotation, error)
+	List(ctx *gin.Context, db *gorm.DB, req *quotation_dto.QuotationListReq) ([]*quotation_dto.Quotation, error)
+	ListVersions(ctx *gin.Context, db *gorm.DB, quotationNo string) ([]*quotation_dto.Quotation, error)
+	ListExpired(ctx *gin.Context, db *gorm.DB, now time.Time) ([]*quotation_dto.Quotation, error)
+	UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, fromStatus int, toStatus int, orderId string) (bool, error)
+}
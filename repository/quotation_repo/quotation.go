@@ -0,0 +1,154 @@
+package quotation_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/quotation_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/quotation_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type quotationRepoImpl struct {
+}
+
+func NewQuotationRepoImpl() repository.QuotationRepo {
+	return &quotationRepoImpl{}
+}
+
+func (q *quotationRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *quotation_dto.Quotation) error {
+	m := quotation_assembly.ConvertQuotationDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("quotationRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	if len(dto.Items) == 0 {
+		return nil
+	}
+	items := make([]*model.QuotationItem, 0, len(dto.Items))
+	for _, item := range dto.Items {
+		item.QuotationID = m.ID
+		items = append(items, quotation_assembly.ConvertQuotationItemDtoToModel(item))
+	}
+	err = db.Create(&items).Error
+	if err != nil {
+		vars.Log.Errorf("quotationRepoImpl.Add items error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	for i, item := range items {
+		dto.Items[i].ID = item.ID
+	}
+	return nil
+}
+
+func (q *quotationRepoImpl) GetById(ctx *gin.Context, db *gorm.DB, id string) (*quotation_dto.Quotation, error) {
+	m := &model.Quotation{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("quotationRepoImpl.GetById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list, err := q.withItems(db, []*model.Quotation{m})
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+func (q *quotationRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *quotation_dto.QuotationListReq) ([]*quotation_dto.Quotation, error) {
+	query := db.Model(&model.Quotation{})
+	if req.QuotationNo != "" {
+		query = query.Where("quotation_no = ?", req.QuotationNo)
+	}
+	if req.Status != 0 {
+		query = query.Where("status = ?", req.Status)
+	}
+	if err := query.Count(&req.Pager.TotalRows).Error; err != nil {
+		vars.Log.Errorf("quotationRepoImpl.List count error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	offset := (req.Pager.Page - 1) * req.Pager.PageSize
+
+	mList := make([]*model.Quotation, 0)
+	err := query.Offset(int(offset)).Limit(int(req.Pager.PageSize)).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("quotationRepoImpl.List Find error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*quotation_dto.Quotation, 0)
+	for _, m := range mList {
+		list = append(list, quotation_assembly.ConvertQuotationModelToDto(m))
+	}
+	return list, nil
+}
+
+func (q *quotationRepoImpl) ListVersions(ctx *gin.Context, db *gorm.DB, quotationNo string) ([]*quotation_dto.Quotation, error) {
+	mList := make([]*model.Quotation, 0)
+	err := db.Where("quotation_no = ?", quotationNo).Order("version asc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("quotationRepoImpl.ListVersions error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return q.withItems(db, mList)
+}
+
+func (q *quotationRepoImpl) ListExpired(ctx *gin.Context, db *gorm.DB, now time.Time) ([]*quotation_dto.Quotation, error) {
+	mList := make([]*model.Quotation, 0)
+	err := db.Where("status = ? and valid_until < ?", quotation_dto.QuotationStatusActive, now).Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("quotationRepoImpl.ListExpired error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return q.withItems(db, mList)
+}
+
+func (q *quotationRepoImpl) UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, fromStatus int, toStatus int, orderId string) (bool, error) {
+	updates := map[string]interface{}{"status": toStatus}
+	if orderId != "" {
+		updates["order_id"] = orderId
+	}
+	result := db.Model(&model.Quotation{}).Where("id = ? and status = ?", id, fromStatus).Updates(updates)
+	if result.Error != nil {
+		vars.Log.Errorf("quotationRepoImpl.UpdateStatus error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (q *quotationRepoImpl) withItems(db *gorm.DB, mList []*model.Quotation) ([]*quotation_dto.Quotation, error) {
+	list := make([]*quotation_dto.Quotation, 0, len(mList))
+	if len(mList) == 0 {
+		return list, nil
+	}
+	ids := make([]string, 0, len(mList))
+	quotationMap := make(map[string]*quotation_dto.Quotation)
+	for _, m := range mList {
+		quotation := quotation_assembly.ConvertQuotationModelToDto(m)
+		ids = append(ids, m.ID)
+		quotationMap[m.ID] = quotation
+		list = append(list, quotation)
+	}
+	items := make([]*model.QuotationItem, 0)
+	err := db.Where("quotation_id in ?", ids).Order("create_time asc").Find(&items).Error
+	if err != nil {
+		vars.Log.Errorf("quotationRepoImpl.withItems error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	for _, item := range items {
+		if quotation, ok := quotationMap[item.QuotationID]; ok {
+			quotation.Items = append(quotation.Items, quotation_assembly.ConvertQuotationItemModelToDto(item))
+		}
+	}
+	return list, nil
+}
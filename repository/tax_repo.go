@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/tax_dto"
+	"gorm.io/gorm"
+)
+
+type TaxRepo interface {
+	SaveRule(ctx *gin.Context, db *gorm.DB, dto *tax_dto.TaxRule) error
+	GetRuleById(ctx *gin.Context, db *gorm.DB, id string) (*tax_dto.TaxRule, error)
+	ListRules(ctx *gin.Context, db *gorm.DB, userId string) ([]*tax_dto.TaxRule, error)
+	DeleteRule(ctx *gin.Context, db *gorm.DB, id string) error
+	GetSetting(ctx *gin.Context, db *gorm.DB, userId string) (*tax_dto.TaxSetting, error)
+	SaveSetting(ctx *gin.Context, db *gorm.DB, dto *tax_dto.TaxSetting) error
+	AddExemption(ctx *gin.Context, db *gorm.DB, dto *tax_dto.TaxExemption) error
+	GetExemption(ctx *gin.Context, db *gorm.DB, userId string, customerId string) (*tax_dto.TaxExemption, error)
+	ListExemptions(ctx *gin.Context, db *gorm.DB, userId string) ([]*tax_dto.TaxExemption, error)
+	DeleteExemption(ctx *gin.Context, db *gorm.DB, userId string, customerId string) error
+}
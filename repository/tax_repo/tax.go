@@ -0,0 +1,181 @@
+package tax_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/tax_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+)
+
+type taxRepoImpl struct {
+}
+
+func NewTaxRepoImpl() repository.TaxRepo {
+	return &taxRepoImpl{}
+}
+
+// SaveRule 同一用户同一类目只保留一条税率, 已存在时更新税率
+func (t *taxRepoImpl) SaveRule(ctx *gin.Context, db *gorm.DB, dto *tax_dto.TaxRule) error {
+	exists := &model.TaxRule{}
+	err := db.Where("user_id = ? and category = ?", dto.UserID, dto.Category).First(exists).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		vars.Log.Errorf("taxRepoImpl.SaveRule First error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	if err == nil {
+		err = db.Model(exists).Updates(map[string]interface{}{
+			"name": dto.Name,
+			"rate": dto.Rate,
+		}).Error
+		if err != nil {
+			vars.Log.Errorf("taxRepoImpl.SaveRule Updates error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+			return sm_error.NewHttpError(error_code.DBError)
+		}
+		dto.ID = exists.ID
+		return nil
+	}
+	m := tax_assembly.ConvertTRDtoToModel(dto)
+	err = db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("taxRepoImpl.SaveRule Create error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (t *taxRepoImpl) GetRuleById(ctx *gin.Context, db *gorm.DB, id string) (*tax_dto.TaxRule, error) {
+	m := &model.TaxRule{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("taxRepoImpl.GetRuleById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return tax_assembly.ConvertTRModelToDto(m), nil
+}
+
+func (t *taxRepoImpl) ListRules(ctx *gin.Context, db *gorm.DB, userId string) ([]*tax_dto.TaxRule, error) {
+	mList := make([]*model.TaxRule, 0)
+	err := db.Where("user_id = ?", userId).Order("category asc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("taxRepoImpl.ListRules error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*tax_dto.TaxRule, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, tax_assembly.ConvertTRModelToDto(m))
+	}
+	return list, nil
+}
+
+func (t *taxRepoImpl) DeleteRule(ctx *gin.Context, db *gorm.DB, id string) error {
+	err := db.Where("id = ?", id).Delete(&model.TaxRule{}).Error
+	if err != nil {
+		vars.Log.Errorf("taxRepoImpl.DeleteRule error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+// GetSetting 未配置时默认商品售价不含税
+func (t *taxRepoImpl) GetSetting(ctx *gin.Context, db *gorm.DB, userId string) (*tax_dto.TaxSetting, error) {
+	m := &model.TaxSetting{}
+	err := db.Where("user_id = ?", userId).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &tax_dto.TaxSetting{UserID: userId}, nil
+		}
+		vars.Log.Errorf("taxRepoImpl.GetSetting error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return &tax_dto.TaxSetting{
+		UserID:           m.UserID,
+		PriceIncludesTax: m.PriceIncludesTax,
+	}, nil
+}
+
+func (t *taxRepoImpl) SaveSetting(ctx *gin.Context, db *gorm.DB, dto *tax_dto.TaxSetting) error {
+	result := db.Model(&model.TaxSetting{}).Where("user_id = ?", dto.UserID).Update("price_includes_tax", dto.PriceIncludesTax)
+	if result.Error != nil {
+		vars.Log.Errorf("taxRepoImpl.SaveSetting Update error:%v,data: %v", result.Error, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	exists := int64(0)
+	err := db.Model(&model.TaxSetting{}).Where("user_id = ?", dto.UserID).Count(&exists).Error
+	if err != nil {
+		vars.Log.Errorf("taxRepoImpl.SaveSetting Count error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	if exists > 0 {
+		return nil
+	}
+	err = db.Create(&model.TaxSetting{
+		UserID:           dto.UserID,
+		PriceIncludesTax: dto.PriceIncludesTax,
+	}).Error
+	if err != nil {
+		vars.Log.Errorf("taxRepoImpl.SaveSetting Create error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (t *taxRepoImpl) AddExemption(ctx *gin.Context, db *gorm.DB, dto *tax_dto.TaxExemption) error {
+	m := tax_assembly.ConvertTEDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("taxRepoImpl.AddExemption error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (t *taxRepoImpl) GetExemption(ctx *gin.Context, db *gorm.DB, userId string, customerId string) (*tax_dto.TaxExemption, error) {
+	m := &model.TaxExemption{}
+	err := db.Where("user_id = ? and customer_id = ?", userId, customerId).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("taxRepoImpl.GetExemption error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return tax_assembly.ConvertTEModelToDto(m), nil
+}
+
+func (t *taxRepoImpl) ListExemptions(ctx *gin.Context, db *gorm.DB, userId string) ([]*tax_dto.TaxExemption, error) {
+	mList := make([]*model.TaxExemption, 0)
+	err := db.Where("user_id = ?", userId).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("taxRepoImpl.ListExemptions error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*tax_dto.TaxExemption, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, tax_assembly.ConvertTEModelToDto(m))
+	}
+	return list, nil
+}
+
+func (t *taxRepoImpl) DeleteExemption(ctx *gin.Context, db *gorm.DB, userId string, customerId string) error {
+	err := db.Where("user_id = ? and customer_id = ?", userId, customerId).Delete(&model.TaxExemption{}).Error
+	if err != nil {
+		vars.Log.Errorf("taxRepoImpl.DeleteExemption error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
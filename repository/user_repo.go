@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shop_management/dto/user_dto"
 	"gorm.io/gorm"
+	"time"
 )
 
 type UserRepo interface {
@@ -13,16 +14,63 @@ type UserRepo interface {
 	GetByPhone(ctx *gin.Context, db *gorm.DB, phone string) (*user_dto.User, error)
 	ModifyPassword(ctx *gin.Context, db *gorm.DB, id string, password string) error
 	SaveProfile(ctx *gin.Context, db *gorm.DB, req *user_dto.UserProfile) error
+	UpdateAvatar(ctx *gin.Context, db *gorm.DB, id string, avatarUrl string) error
 	Delete(ctx *gin.Context, db *gorm.DB, userId string) error
 	SaveSecret(ctx *gin.Context, db *gorm.DB, userId, key, secret string) error
 	List(ctx *gin.Context, db *gorm.DB, req *user_dto.UserListReq) ([]*user_dto.User, error)
 	Save(ctx *gin.Context, db *gorm.DB, req *user_dto.User) error
 	GetAll(ctx *gin.Context, db *gorm.DB) ([]*user_dto.User, error)
 	GetBySecret(ctx *gin.Context, db *gorm.DB, secret string) (*user_dto.User, error)
+	Deactivate(ctx *gin.Context, db *gorm.DB, id string, teamId string, role string) (bool, error)
+	Reactivate(ctx *gin.Context, db *gorm.DB, id string) (bool, error)
+	ListDeactivated(ctx *gin.Context, db *gorm.DB, teamId string) ([]*user_dto.User, error)
+	ListDeactivatedBefore(ctx *gin.Context, db *gorm.DB, before time.Time) ([]*user_dto.User, error)
+	SetDisabled(ctx *gin.Context, db *gorm.DB, id string, disabled bool) (bool, error)
 }
 
 type UserTeamRepo interface {
 	List(ctx *gin.Context, db *gorm.DB, req *user_dto.SubUserListReq) (*user_dto.SubUserListResp, error)
-	AddSubUser(ctx *gin.Context, db *gorm.DB, subUserId string, userId string) error
+	AddSubUser(ctx *gin.Context, db *gorm.DB, subUserId string, userId string, role string) error
 	DelSubUser(ctx *gin.Context, db *gorm.DB, id string) error
+	GetById(ctx *gin.Context, db *gorm.DB, id string) (*user_dto.SubUser, error)
+	GetBySubUserId(ctx *gin.Context, db *gorm.DB, subUserId string) (*user_dto.SubUser, error)
+	UpdateRole(ctx *gin.Context, db *gorm.DB, id string, role string) error
+	IsMember(ctx *gin.Context, db *gorm.DB, userId string, subUserId string) (bool, error)
+	CountMembers(ctx *gin.Context, db *gorm.DB, userId string) (int64, error)
+	DelBySubUserId(ctx *gin.Context, db *gorm.DB, subUserId string) error
+}
+
+type UserInvitationRepo interface {
+	Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.Invitation) error
+	GetById(ctx *gin.Context, db *gorm.DB, id string) (*user_dto.Invitation, error)
+	List(ctx *gin.Context, db *gorm.DB, req *user_dto.InvitationListReq) (*user_dto.InvitationListResp, error)
+	UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, from int, to int, inviteeId string) (bool, error)
+}
+
+type LoginLockoutRepo interface {
+	Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.LoginLockout) error
+	List(ctx *gin.Context, db *gorm.DB, req *user_dto.LoginLockoutListReq) (*user_dto.LoginLockoutListResp, error)
+}
+
+type UserTwoFactorRepo interface {
+	GetByUserId(ctx *gin.Context, db *gorm.DB, userId string) (*user_dto.TwoFactor, error)
+	Save(ctx *gin.Context, db *gorm.DB, dto *user_dto.TwoFactor) error
+	UpdateStatus(ctx *gin.Context, db *gorm.DB, userId string, status int) error
+	Delete(ctx *gin.Context, db *gorm.DB, userId string) error
+	ReplaceRecoveryCodes(ctx *gin.Context, db *gorm.DB, userId string, codeHashes []string) error
+	UseRecoveryCode(ctx *gin.Context, db *gorm.DB, userId string, codeHash string) (bool, error)
+}
+
+type UserIdentityRepo interface {
+	GetBySubject(ctx *gin.Context, db *gorm.DB, issuer string, subject string) (*user_dto.UserIdentity, error)
+	Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.UserIdentity) error
+	DeleteByUser(ctx *gin.Context, db *gorm.DB, userId string) error
+}
+
+type UserGrantRepo interface {
+	ListByUser(ctx *gin.Context, db *gorm.DB, userId string) ([]*user_dto.UserGrant, error)
+	GetById(ctx *gin.Context, db *gorm.DB, id string) (*user_dto.UserGrant, error)
+	Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.UserGrant) error
+	Delete(ctx *gin.Context, db *gorm.DB, id string) error
+	DeleteByUser(ctx *gin.Context, db *gorm.DB, userId string) error
 }
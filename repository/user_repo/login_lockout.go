@@ -0,0 +1,59 @@
+package user_repo
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/user_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+)
+
+type loginLockoutRepoImpl struct {
+}
+
+func NewLoginLockoutRepoImpl() repository.LoginLockoutRepo {
+	return &loginLockoutRepoImpl{}
+}
+
+func (l *loginLockoutRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.LoginLockout) error {
+	m := user_assembly.ConvertLoginLockoutDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("loginLockoutRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (l *loginLockoutRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *user_dto.LoginLockoutListReq) (*user_dto.LoginLockoutListResp, error) {
+	query := db.Model(&model.LoginLockout{})
+	if req.UserID != "" {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	if err := query.Count(&req.Pager.TotalRows).Error; err != nil {
+		vars.Log.Errorf("loginLockoutRepoImpl.List count error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	offset := (req.Pager.Page - 1) * req.Pager.PageSize
+
+	mList := make([]*model.LoginLockout, 0)
+	err := query.Offset(int(offset)).Limit(int(req.Pager.PageSize)).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("loginLockoutRepoImpl.List Find error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*user_dto.LoginLockout, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, user_assembly.ConvertLoginLockoutModelToDto(m))
+	}
+	return &user_dto.LoginLockoutListResp{
+		Pager: req.Pager,
+		List:  list,
+	}, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/shop_management/util"
 	"github.com/shop_management/vars"
 	"gorm.io/gorm"
+	"time"
 )
 
 type userRepoImpl struct {
@@ -22,11 +23,13 @@ func NewUserRepoImpl() repository.UserRepo {
 }
 
 func (u *userRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.User) error {
-	err := db.Create(user_assembly.ConvertUDtoToModel(dto)).Error
+	m := user_assembly.ConvertUDtoToModel(dto)
+	err := db.Create(m).Error
 	if err != nil {
 		vars.Log.Errorf("userRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
 		return sm_error.NewHttpError(error_code.DBError)
 	}
+	dto.Id = m.Id
 	return nil
 }
 
@@ -91,15 +94,24 @@ func (u *userRepoImpl) ModifyPassword(ctx *gin.Context, db *gorm.DB, id string,
 	return nil
 }
 
+// SaveProfile 只修改名称和邮箱, 手机号和头像有各自的修改入口
 func (u *userRepoImpl) SaveProfile(ctx *gin.Context, db *gorm.DB, req *user_dto.UserProfile) error {
 	err := db.Model(&model.User{}).Where("id=?", req.UserId).Updates(map[string]interface {
 	}{
-		"phone":      req.Phone,
-		"avatar_url": req.AvatarUrl,
-		"name":       req.Name,
-		"email":      req.Email,
+		"name":  req.Name,
+		"email": req.Email,
 	}).Error
 	if err != nil {
+		vars.Log.Errorf("userRepoImpl.SaveProfile error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (u *userRepoImpl) UpdateAvatar(ctx *gin.Context, db *gorm.DB, id string, avatarUrl string) error {
+	err := db.Model(&model.User{}).Where("id=?", id).Update("avatar_url", avatarUrl).Error
+	if err != nil {
+		vars.Log.Errorf("userRepoImpl.UpdateAvatar error:%v", err)
 		return sm_error.NewHttpError(error_code.DBError)
 	}
 	return nil
@@ -116,6 +128,7 @@ func (u *userRepoImpl) Save(ctx *gin.Context, db *gorm.DB, req *user_dto.User) e
 func (u *userRepoImpl) Delete(ctx *gin.Context, db *gorm.DB, userId string) error {
 	err := db.Where("id = ?", userId).Delete(&model.User{}).Error
 	if err != nil {
+		vars.Log.Errorf("userRepoImpl.Delete error:%v", err)
 		return sm_error.NewHttpError(error_code.DBError)
 	}
 	return nil
@@ -125,7 +138,7 @@ func (u *userRepoImpl) SaveSecret(ctx *gin.Context, db *gorm.DB, userId, key, se
 	err := db.Model(&model.User{}).Where("id=?", userId).Updates(map[string]interface{}{
 		"api_key":    key,
 		"api_secret": secret,
-	})
+	}).Error
 	if err != nil {
 		vars.Log.Errorf("userRepoImpl.SaveSecret error:%v", err)
 		return sm_error.NewHttpError(error_code.DBError)
@@ -137,6 +150,12 @@ func (u *userRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *user_dto.UserLis
 	if req.Name != "" {
 		db = db.Where("name like ?", "%"+req.Name+"%")
 	}
+	if len(req.UserIds) > 0 {
+		db = db.Where("id in ?", req.UserIds)
+	}
+	if req.Keyword != "" {
+		db = db.Where("name like ? or phone like ?", "%"+req.Keyword+"%", "%"+req.Keyword+"%")
+	}
 	if err := db.Model(&model.User{}).Count(&req.Pager.TotalRows).Error; err != nil {
 		vars.Log.Errorf("userRepoImpl.List count error:%v,data: %v", err, util.MarshalToStringNoErr(req))
 		return nil, sm_error.NewHttpError(error_code.DBError)
@@ -170,3 +189,71 @@ func (u *userRepoImpl) GetAll(ctx *gin.Context, db *gorm.DB) ([]*user_dto.User,
 	}
 	return list, nil
 }
+
+// Deactivate 停用账号, 记录停用前所在团队和角色以便恢复. 已停用的账号返回 false
+func (u *userRepoImpl) Deactivate(ctx *gin.Context, db *gorm.DB, id string, teamId string, role string) (bool, error) {
+	result := db.Model(&model.User{}).Where("id=? and deleted_time is null", id).Updates(map[string]interface{}{
+		"deleted_time":        time.Now(),
+		"deactivated_team_id": teamId,
+		"deactivated_role":    role,
+	})
+	if result.Error != nil {
+		vars.Log.Errorf("userRepoImpl.Deactivate error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Reactivate 恢复已停用的账号, 未停用的账号返回 false
+func (u *userRepoImpl) Reactivate(ctx *gin.Context, db *gorm.DB, id string) (bool, error) {
+	result := db.Model(&model.User{}).Where("id=? and deleted_time is not null", id).Updates(map[string]interface{}{
+		"deleted_time":        nil,
+		"deactivated_team_id": "",
+		"deactivated_role":    "",
+	})
+	if result.Error != nil {
+		vars.Log.Errorf("userRepoImpl.Reactivate error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListDeactivated 停用前属于该团队的账号
+func (u *userRepoImpl) ListDeactivated(ctx *gin.Context, db *gorm.DB, teamId string) ([]*user_dto.User, error) {
+	return u.listDeactivated(db.Where("deactivated_team_id = ?", teamId))
+}
+
+// ListDeactivatedBefore 在 before 之前停用的账号, 用于清除超过保留期的数据
+func (u *userRepoImpl) ListDeactivatedBefore(ctx *gin.Context, db *gorm.DB, before time.Time) ([]*user_dto.User, error) {
+	return u.listDeactivated(db.Where("deleted_time < ?", before))
+}
+
+func (u *userRepoImpl) listDeactivated(db *gorm.DB) ([]*user_dto.User, error) {
+	mList := make([]*model.User, 0)
+	err := db.Where("deleted_time is not null").Order("deleted_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("userRepoImpl.listDeactivated Find error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*user_dto.User, 0, len(mList))
+	for _, user := range mList {
+		list = append(list, user_assembly.ConvertUModelToDto(user))
+	}
+	return list, nil
+}
+
+// SetDisabled 禁用或解除禁用账号, 状态未发生变化时返回 false
+func (u *userRepoImpl) SetDisabled(ctx *gin.Context, db *gorm.DB, id string, disabled bool) (bool, error) {
+	query := db.Model(&model.User{}).Where("id=?", id)
+	var result *gorm.DB
+	if disabled {
+		result = query.Where("disabled_time is null").Update("disabled_time", time.Now())
+	} else {
+		result = query.Where("disabled_time is not null").Update("disabled_time", nil)
+	}
+	if result.Error != nil {
+		vars.Log.Errorf("userRepoImpl.SetDisabled error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
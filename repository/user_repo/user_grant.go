@@ -0,0 +1,92 @@
+package user_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+)
+
+type userGrantRepoImpl struct {
+}
+
+func NewUserGrantRepoImpl() repository.UserGrantRepo {
+	return &userGrantRepoImpl{}
+}
+
+func (u *userGrantRepoImpl) ListByUser(ctx *gin.Context, db *gorm.DB, userId string) ([]*user_dto.UserGrant, error) {
+	mList := make([]*model.UserGrant, 0)
+	err := db.Where("user_id = ?", userId).Order("resource_type asc, resource_id asc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("userGrantRepoImpl.ListByUser error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*user_dto.UserGrant, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, convertGrantModelToDto(m))
+	}
+	return list, nil
+}
+
+func (u *userGrantRepoImpl) GetById(ctx *gin.Context, db *gorm.DB, id string) (*user_dto.UserGrant, error) {
+	m := &model.UserGrant{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("userGrantRepoImpl.GetById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return convertGrantModelToDto(m), nil
+}
+
+func (u *userGrantRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.UserGrant) error {
+	m := &model.UserGrant{
+		UserID:       dto.UserID,
+		ResourceType: dto.ResourceType,
+		ResourceID:   dto.ResourceID,
+		Action:       dto.Action,
+	}
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("userGrantRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (u *userGrantRepoImpl) Delete(ctx *gin.Context, db *gorm.DB, id string) error {
+	err := db.Where("id = ?", id).Delete(&model.UserGrant{}).Error
+	if err != nil {
+		vars.Log.Errorf("userGrantRepoImpl.Delete error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (u *userGrantRepoImpl) DeleteByUser(ctx *gin.Context, db *gorm.DB, userId string) error {
+	err := db.Where("user_id = ?", userId).Delete(&model.UserGrant{}).Error
+	if err != nil {
+		vars.Log.Errorf("userGrantRepoImpl.DeleteByUser error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func convertGrantModelToDto(m *model.UserGrant) *user_dto.UserGrant {
+	return &user_dto.UserGrant{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		ResourceType: m.ResourceType,
+		ResourceID:   m.ResourceID,
+		Action:       m.Action,
+	}
+}
@@ -0,0 +1,67 @@
+package user_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+)
+
+type userIdentityRepoImpl struct {
+}
+
+func NewUserIdentityRepoImpl() repository.UserIdentityRepo {
+	return &userIdentityRepoImpl{}
+}
+
+func (u *userIdentityRepoImpl) GetBySubject(ctx *gin.Context, db *gorm.DB, issuer string, subject string) (*user_dto.UserIdentity, error) {
+	m := &model.UserIdentity{}
+	err := db.Where("issuer = ? and subject = ?", issuer, subject).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("userIdentityRepoImpl.GetBySubject error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return &user_dto.UserIdentity{
+		ID:      m.ID,
+		UserID:  m.UserID,
+		Issuer:  m.Issuer,
+		Subject: m.Subject,
+		Email:   m.Email,
+		Phone:   m.Phone,
+	}, nil
+}
+
+func (u *userIdentityRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.UserIdentity) error {
+	m := &model.UserIdentity{
+		UserID:  dto.UserID,
+		Issuer:  dto.Issuer,
+		Subject: dto.Subject,
+		Email:   dto.Email,
+		Phone:   dto.Phone,
+	}
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("userIdentityRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (u *userIdentityRepoImpl) DeleteByUser(ctx *gin.Context, db *gorm.DB, userId string) error {
+	err := db.Where("user_id = ?", userId).Delete(&model.UserIdentity{}).Error
+	if err != nil {
+		vars.Log.Errorf("userIdentityRepoImpl.DeleteByUser error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package user_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/assembly/user_assembly"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type userInvitationRepoImpl struct {
+}
+
+func NewUserInvitationRepoImpl() repository.UserInvitationRepo {
+	return &userInvitationRepoImpl{}
+}
+
+func (u *userInvitationRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *user_dto.Invitation) error {
+	m := user_assembly.ConvertInvitationDtoToModel(dto)
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("userInvitationRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	dto.CreateTime = m.CreateTime
+	return nil
+}
+
+func (u *userInvitationRepoImpl) GetById(ctx *gin.Context, db *gorm.DB, id string) (*user_dto.Invitation, error) {
+	m := &model.UserInvitation{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("userInvitationRepoImpl.GetById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return user_assembly.ConvertInvitationModelToDto(m), nil
+}
+
+// List 过期状态按有效期判断, 库中过期的邀请仍为待处理状态
+func (u *userInvitationRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *user_dto.InvitationListReq) (*user_dto.InvitationListResp, error) {
+	query := db.Model(&model.UserInvitation{}).Where("user_id = ?", req.UserID)
+	now := time.Now()
+	switch req.Status {
+	case 0:
+	case user_dto.InvitationStatusPending:
+		query = query.Where("status = ? and expire_time >= ?", user_dto.InvitationStatusPending, now)
+	case user_dto.InvitationStatusExpired:
+		query = query.Where("status = ? and expire_time < ?", user_dto.InvitationStatusPending, now)
+	default:
+		query = query.Where("status = ?", req.Status)
+	}
+	if err := query.Count(&req.Pager.TotalRows).Error; err != nil {
+		vars.Log.Errorf("userInvitationRepoImpl.List count error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	offset := (req.Pager.Page - 1) * req.Pager.PageSize
+
+	mList := make([]*model.UserInvitation, 0)
+	err := query.Offset(int(offset)).Limit(int(req.Pager.PageSize)).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("userInvitationRepoImpl.List Find error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*user_dto.Invitation, 0, len(mList))
+	for _, m := range mList {
+		invitation := user_assembly.ConvertInvitationModelToDto(m)
+		if invitation.IsExpired(now) {
+			invitation.Status = user_dto.InvitationStatusExpired
+		}
+		list = append(list, invitation)
+	}
+	return &user_dto.InvitationListResp{
+		Pager: req.Pager,
+		List:  list,
+	}, nil
+}
+
+func (u *userInvitationRepoImpl) UpdateStatus(ctx *gin.Context, db *gorm.DB, id string, from int, to int, inviteeId string) (bool, error) {
+	result := db.Model(&model.UserInvitation{}).Where("id = ? and status = ?", id, from).Updates(map[string]interface{}{
+		"status":     to,
+		"invitee_id": inviteeId,
+	})
+	if result.Error != nil {
+		vars.Log.Errorf("userInvitationRepoImpl.UpdateStatus error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
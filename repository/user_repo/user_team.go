@@ -1,12 +1,14 @@
 package user_repo
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/shop_management/dto/user_dto"
 	"github.com/shop_management/model"
 	"github.com/shop_management/repository"
 	"github.com/shop_management/sm_error"
 	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/vars"
 	"gorm.io/gorm"
 )
 
@@ -36,6 +38,7 @@ func (u *userTeamRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *user_dto.Sub
 			Id:        user.ID,
 			SubUserId: user.SubUserID,
 			UserId:    user.UserID,
+			Role:      user.Role,
 		})
 	}
 	resp := &user_dto.SubUserListResp{
@@ -47,10 +50,11 @@ func (u *userTeamRepoImpl) List(ctx *gin.Context, db *gorm.DB, req *user_dto.Sub
 	return resp, nil
 }
 
-func (u *userTeamRepoImpl) AddSubUser(ctx *gin.Context, db *gorm.DB, subUserId string, userId string) error {
+func (u *userTeamRepoImpl) AddSubUser(ctx *gin.Context, db *gorm.DB, subUserId string, userId string, role string) error {
 	err := db.Create(&model.UserTeam{
 		UserID:    userId,
 		SubUserID: subUserId,
+		Role:      role,
 	}).Error
 	if err != nil {
 		return sm_error.NewHttpError(error_code.DBError)
@@ -65,3 +69,69 @@ func (u *userTeamRepoImpl) DelSubUser(ctx *gin.Context, db *gorm.DB, id string)
 	}
 	return nil
 }
+
+func (u *userTeamRepoImpl) IsMember(ctx *gin.Context, db *gorm.DB, userId string, subUserId string) (bool, error) {
+	var count int64
+	err := db.Model(&model.UserTeam{}).Where("user_id=? and sub_user_id=?", userId, subUserId).Count(&count).Error
+	if err != nil {
+		vars.Log.Errorf("userTeamRepoImpl.IsMember error:%v", err)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return count > 0, nil
+}
+
+func (u *userTeamRepoImpl) CountMembers(ctx *gin.Context, db *gorm.DB, userId string) (int64, error) {
+	var count int64
+	err := db.Model(&model.UserTeam{}).Where("user_id=?", userId).Count(&count).Error
+	if err != nil {
+		vars.Log.Errorf("userTeamRepoImpl.CountMembers error:%v", err)
+		return 0, sm_error.NewHttpError(error_code.DBError)
+	}
+	return count, nil
+}
+
+// DelBySubUserId 将用户从所在团队中移除
+func (u *userTeamRepoImpl) DelBySubUserId(ctx *gin.Context, db *gorm.DB, subUserId string) error {
+	err := db.Where("sub_user_id=?", subUserId).Delete(&model.UserTeam{}).Error
+	if err != nil {
+		vars.Log.Errorf("userTeamRepoImpl.DelBySubUserId error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (u *userTeamRepoImpl) GetById(ctx *gin.Context, db *gorm.DB, id string) (*user_dto.SubUser, error) {
+	return u.getOne(db.Where("id=?", id))
+}
+
+// GetBySubUserId 子账号所在的团队, 不是任何团队的子账号时返回nil
+func (u *userTeamRepoImpl) GetBySubUserId(ctx *gin.Context, db *gorm.DB, subUserId string) (*user_dto.SubUser, error) {
+	return u.getOne(db.Where("sub_user_id=?", subUserId))
+}
+
+func (u *userTeamRepoImpl) UpdateRole(ctx *gin.Context, db *gorm.DB, id string, role string) error {
+	err := db.Model(&model.UserTeam{}).Where("id=?", id).Update("role", role).Error
+	if err != nil {
+		vars.Log.Errorf("userTeamRepoImpl.UpdateRole error:%v,id: %v", err, id)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (u *userTeamRepoImpl) getOne(db *gorm.DB) (*user_dto.SubUser, error) {
+	m := &model.UserTeam{}
+	err := db.First(m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		vars.Log.Errorf("userTeamRepoImpl.getOne error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return &user_dto.SubUser{
+		Id:        m.ID,
+		UserId:    m.UserID,
+		SubUserId: m.SubUserID,
+		Role:      m.Role,
+	}, nil
+}
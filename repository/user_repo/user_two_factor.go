@@ -0,0 +1,134 @@
+package user_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type userTwoFactorRepoImpl struct {
+}
+
+func NewUserTwoFactorRepoImpl() repository.UserTwoFactorRepo {
+	return &userTwoFactorRepoImpl{}
+}
+
+// GetByUserId 返回解密后的密钥, 未开启时返回 nil
+func (u *userTwoFactorRepoImpl) GetByUserId(ctx *gin.Context, db *gorm.DB, userId string) (*user_dto.TwoFactor, error) {
+	m := &model.UserTwoFactor{}
+	err := db.Where("user_id = ?", userId).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("userTwoFactorRepoImpl.GetByUserId error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	secret, err := util.DecryptSecret(m.Secret)
+	if err != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.GetByUserId decrypt error:%v, user_id:%s", err, userId)
+		return nil, sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	return &user_dto.TwoFactor{
+		ID:     m.ID,
+		UserID: m.UserID,
+		Secret: secret,
+		Status: m.Status,
+	}, nil
+}
+
+// Save 每个用户只有一条记录, 重新开启时覆盖旧的密钥
+func (u *userTwoFactorRepoImpl) Save(ctx *gin.Context, db *gorm.DB, dto *user_dto.TwoFactor) error {
+	secret, err := util.EncryptSecret(dto.Secret)
+	if err != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.Save encrypt error:%v, user_id:%s", err, dto.UserID)
+		return sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	result := db.Model(&model.UserTwoFactor{}).Where("user_id = ?", dto.UserID).Updates(map[string]interface{}{
+		"secret": secret,
+		"status": dto.Status,
+	})
+	if result.Error != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.Save update error:%v, user_id:%s", result.Error, dto.UserID)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	m := &model.UserTwoFactor{
+		UserID: dto.UserID,
+		Secret: secret,
+		Status: dto.Status,
+	}
+	err = db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.Save create error:%v, user_id:%s", err, dto.UserID)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	return nil
+}
+
+func (u *userTwoFactorRepoImpl) UpdateStatus(ctx *gin.Context, db *gorm.DB, userId string, status int) error {
+	err := db.Model(&model.UserTwoFactor{}).Where("user_id = ?", userId).Update("status", status).Error
+	if err != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.UpdateStatus error:%v, user_id:%s", err, userId)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+// Delete 关闭两步验证, 同时删除恢复码
+func (u *userTwoFactorRepoImpl) Delete(ctx *gin.Context, db *gorm.DB, userId string) error {
+	err := db.Where("user_id = ?", userId).Delete(&model.UserTwoFactor{}).Error
+	if err == nil {
+		err = db.Where("user_id = ?", userId).Delete(&model.UserRecoveryCode{}).Error
+	}
+	if err != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.Delete error:%v, user_id:%s", err, userId)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes 生成新的恢复码后旧的恢复码全部作废
+func (u *userTwoFactorRepoImpl) ReplaceRecoveryCodes(ctx *gin.Context, db *gorm.DB, userId string, codeHashes []string) error {
+	err := db.Where("user_id = ?", userId).Delete(&model.UserRecoveryCode{}).Error
+	if err != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.ReplaceRecoveryCodes delete error:%v, user_id:%s", err, userId)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	mList := make([]*model.UserRecoveryCode, 0, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		mList = append(mList, &model.UserRecoveryCode{
+			UserID:   userId,
+			CodeHash: codeHash,
+		})
+	}
+	err = db.Create(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.ReplaceRecoveryCodes create error:%v, user_id:%s", err, userId)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+// UseRecoveryCode 以条件更新标记恢复码已使用, 并发使用同一恢复码时只有一次成功
+func (u *userTwoFactorRepoImpl) UseRecoveryCode(ctx *gin.Context, db *gorm.DB, userId string, codeHash string) (bool, error) {
+	result := db.Model(&model.UserRecoveryCode{}).
+		Where("user_id = ? and code_hash = ? and used_time is null", userId, codeHash).
+		Update("used_time", time.Now())
+	if result.Error != nil {
+		vars.Log.Errorf("userTwoFactorRepoImpl.UseRecoveryCode error:%v, user_id:%s", result.Error, userId)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
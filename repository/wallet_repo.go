@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/wallet_dto"
+	"github.com/shop_management/util"
+	"gorm.io/gorm"
+	"time"
+)
+
+type WalletRepo interface {
+	GetByOwner(ctx *gin.Context, db *gorm.DB, ownerType string, ownerId string) (*wallet_dto.Wallet, error)
+	GetById(ctx *gin.Context, db *gorm.DB, id string) (*wallet_dto.Wallet, error)
+	Add(ctx *gin.Context, db *gorm.DB, dto *wallet_dto.Wallet) error
+	ChangeBalance(ctx *gin.Context, db *gorm.DB, id string, delta util.Money) (bool, error)
+	SetBalance(ctx *gin.Context, db *gorm.DB, id string, balance util.Money) error
+	AddEntry(ctx *gin.Context, db *gorm.DB, dto *wallet_dto.WalletEntry) error
+	GetEntryById(ctx *gin.Context, db *gorm.DB, id string) (*wallet_dto.WalletEntry, error)
+	ListEntry(ctx *gin.Context, db *gorm.DB, walletId string, req *wallet_dto.WalletEntryListReq) (*wallet_dto.WalletEntryListResp, error)
+	ListEntrySince(ctx *gin.Context, db *gorm.DB, walletId string, types []string, startTime time.Time) ([]*wallet_dto.WalletEntry, error)
+	SumAmount(ctx *gin.Context, db *gorm.DB, walletId string, types []string, startTime time.Time) (util.Money, error)
+	SumRefund(ctx *gin.Context, db *gorm.DB, refEntryId string) (util.Money, error)
+}
@@ -0,0 +1,220 @@
+package wallet_repo
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/dto/wallet_dto"
+	"github.com/shop_management/model"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type walletRepoImpl struct {
+}
+
+func NewWalletRepoImpl() repository.WalletRepo {
+	return &walletRepoImpl{}
+}
+
+func (w *walletRepoImpl) GetByOwner(ctx *gin.Context, db *gorm.DB, ownerType string, ownerId string) (*wallet_dto.Wallet, error) {
+	m := &model.Wallet{}
+	err := db.Where("owner_type = ? and owner_id = ?", ownerType, ownerId).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("walletRepoImpl.GetByOwner error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return convertWalletModelToDto(m), nil
+}
+
+func (w *walletRepoImpl) GetById(ctx *gin.Context, db *gorm.DB, id string) (*wallet_dto.Wallet, error) {
+	m := &model.Wallet{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("walletRepoImpl.GetById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return convertWalletModelToDto(m), nil
+}
+
+// Add 并发创建同一对象的钱包时只保留一个, 调用方需重新按对象查询
+func (w *walletRepoImpl) Add(ctx *gin.Context, db *gorm.DB, dto *wallet_dto.Wallet) error {
+	m := &model.Wallet{
+		OwnerType: dto.OwnerType,
+		OwnerID:   dto.OwnerID,
+		Currency:  dto.Currency,
+	}
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("walletRepoImpl.Add error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+// ChangeBalance 在同一条语句中校验并修改余额, 余额不足时返回 false. 修改后该行在事务结束前保持锁定
+func (w *walletRepoImpl) ChangeBalance(ctx *gin.Context, db *gorm.DB, id string, delta util.Money) (bool, error) {
+	result := db.Model(&model.Wallet{}).Where("id = ? and balance + ? >= 0", id, delta).
+		Update("balance", gorm.Expr("balance + ?", delta))
+	if result.Error != nil {
+		vars.Log.Errorf("walletRepoImpl.ChangeBalance error:%v", result.Error)
+		return false, sm_error.NewHttpError(error_code.DBError)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (w *walletRepoImpl) SetBalance(ctx *gin.Context, db *gorm.DB, id string, balance util.Money) error {
+	err := db.Model(&model.Wallet{}).Where("id = ?", id).Update("balance", balance).Error
+	if err != nil {
+		vars.Log.Errorf("walletRepoImpl.SetBalance error:%v", err)
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	return nil
+}
+
+func (w *walletRepoImpl) AddEntry(ctx *gin.Context, db *gorm.DB, dto *wallet_dto.WalletEntry) error {
+	m := &model.WalletEntry{
+		WalletID:     dto.WalletID,
+		Type:         dto.Type,
+		Amount:       dto.Amount,
+		BalanceAfter: dto.BalanceAfter,
+		RefEntryID:   dto.RefEntryID,
+		Reference:    dto.Reference,
+		Remark:       dto.Remark,
+		OperatorID:   dto.OperatorID,
+	}
+	err := db.Create(m).Error
+	if err != nil {
+		vars.Log.Errorf("walletRepoImpl.AddEntry error:%v,data: %v", err, util.MarshalToStringNoErr(dto))
+		return sm_error.NewHttpError(error_code.DBError)
+	}
+	dto.ID = m.ID
+	dto.CreateTime = m.CreateTime
+	return nil
+}
+
+func (w *walletRepoImpl) GetEntryById(ctx *gin.Context, db *gorm.DB, id string) (*wallet_dto.WalletEntry, error) {
+	m := &model.WalletEntry{}
+	err := db.Where("id = ?", id).First(m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		vars.Log.Errorf("walletRepoImpl.GetEntryById error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	return convertEntryModelToDto(m), nil
+}
+
+func (w *walletRepoImpl) ListEntry(ctx *gin.Context, db *gorm.DB, walletId string, req *wallet_dto.WalletEntryListReq) (*wallet_dto.WalletEntryListResp, error) {
+	query := db.Model(&model.WalletEntry{}).Where("wallet_id = ?", walletId)
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if err := query.Count(&req.Pager.TotalRows).Error; err != nil {
+		vars.Log.Errorf("walletRepoImpl.ListEntry count error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	offset := (req.Pager.Page - 1) * req.Pager.PageSize
+
+	mList := make([]*model.WalletEntry, 0)
+	err := query.Offset(int(offset)).Limit(int(req.Pager.PageSize)).Order("create_time desc").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("walletRepoImpl.ListEntry Find error:%v,data: %v", err, util.MarshalToStringNoErr(req))
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*wallet_dto.WalletEntry, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, convertEntryModelToDto(m))
+	}
+	return &wallet_dto.WalletEntryListResp{
+		Pager: &common_dto.Pager{
+			Page:      req.Pager.Page,
+			PageSize:  req.Pager.PageSize,
+			TotalRows: req.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
+}
+
+func (w *walletRepoImpl) ListEntrySince(ctx *gin.Context, db *gorm.DB, walletId string, types []string, startTime time.Time) ([]*wallet_dto.WalletEntry, error) {
+	mList := make([]*model.WalletEntry, 0)
+	err := db.Where("wallet_id = ? and type in ? and create_time >= ?", walletId, types, startTime).
+		Order("create_time").Find(&mList).Error
+	if err != nil {
+		vars.Log.Errorf("walletRepoImpl.ListEntrySince error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.DBError)
+	}
+	list := make([]*wallet_dto.WalletEntry, 0, len(mList))
+	for _, m := range mList {
+		list = append(list, convertEntryModelToDto(m))
+	}
+	return list, nil
+}
+
+// SumAmount 流水金额之和, types 为空表示全部类型, startTime 为零值表示不限制
+func (w *walletRepoImpl) SumAmount(ctx *gin.Context, db *gorm.DB, walletId string, types []string, startTime time.Time) (util.Money, error) {
+	query := db.Model(&model.WalletEntry{}).Where("wallet_id = ?", walletId)
+	if len(types) > 0 {
+		query = query.Where("type in ?", types)
+	}
+	if !startTime.IsZero() {
+		query = query.Where("create_time >= ?", startTime)
+	}
+	var sum util.Money
+	err := query.Select("coalesce(sum(amount), 0)").Row().Scan(&sum)
+	if err != nil {
+		vars.Log.Errorf("walletRepoImpl.SumAmount error:%v", err)
+		return 0, sm_error.NewHttpError(error_code.DBError)
+	}
+	return sum, nil
+}
+
+func (w *walletRepoImpl) SumRefund(ctx *gin.Context, db *gorm.DB, refEntryId string) (util.Money, error) {
+	var sum util.Money
+	err := db.Model(&model.WalletEntry{}).Where("ref_entry_id = ? and type = ?", refEntryId, wallet_dto.EntryTypeRefund).
+		Select("coalesce(sum(amount), 0)").Row().Scan(&sum)
+	if err != nil {
+		vars.Log.Errorf("walletRepoImpl.SumRefund error:%v", err)
+		return 0, sm_error.NewHttpError(error_code.DBError)
+	}
+	return sum, nil
+}
+
+func convertWalletModelToDto(m *model.Wallet) *wallet_dto.Wallet {
+	return &wallet_dto.Wallet{
+		ID:         m.ID,
+		OwnerType:  m.OwnerType,
+		OwnerID:    m.OwnerID,
+		Currency:   m.Currency,
+		Balance:    m.Balance,
+		CreateTime: m.CreateTime,
+	}
+}
+
+func convertEntryModelToDto(m *model.WalletEntry) *wallet_dto.WalletEntry {
+	return &wallet_dto.WalletEntry{
+		ID:           m.ID,
+		WalletID:     m.WalletID,
+		Type:         m.Type,
+		Amount:       m.Amount,
+		BalanceAfter: m.BalanceAfter,
+		RefEntryID:   m.RefEntryID,
+		Reference:    m.Reference,
+		Remark:       m.Remark,
+		OperatorID:   m.OperatorID,
+		CreateTime:   m.CreateTime,
+	}
+}
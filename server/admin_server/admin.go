@@ -0,0 +1,166 @@
+package admin_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/admin_dto"
+	"github.com/shop_management/dto/audit_dto"
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/po/admin_po"
+	"github.com/shop_management/po/audit_po"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/server/assembly/admin_assembly"
+	"github.com/shop_management/server/assembly/audit_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/admin_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+)
+
+type AdminServer struct {
+	adminService service.AdminService
+}
+
+func NewAdminServer() *AdminServer {
+	return &AdminServer{
+		adminService: admin_service.NewAdminServiceImpl(),
+	}
+}
+
+func (a *AdminServer) UserList(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &admin_po.UserListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	r, err := a.adminService.UserList(ctx, &admin_dto.UserListReq{
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		Keyword: req.Keyword,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*admin_po.User, 0, len(r.List))
+	for _, user := range r.List {
+		list = append(list, admin_assembly.ConvertUserDtoToPo(user))
+	}
+	return &admin_po.UserListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
+}
+
+func (a *AdminServer) UserDetail(ctx *gin.Context) (interface{}, error) {
+	req := &admin_po.UserReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	detail, err := a.adminService.UserDetail(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return admin_assembly.ConvertUserDetailDtoToPo(detail), nil
+}
+
+func (a *AdminServer) UserActivity(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &admin_po.UserReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	r, err := a.adminService.UserActivity(ctx, &admin_dto.UserActivityReq{
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		UserId: req.UserId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertAuditListResp(r), nil
+}
+
+func (a *AdminServer) DisableUser(ctx *gin.Context) (interface{}, error) {
+	req := &admin_po.DisableUserReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = a.adminService.DisableUser(ctx, &admin_dto.DisableUserReq{UserId: req.UserId, Reason: req.Reason})
+	return &common_po.CommonResp{}, err
+}
+
+func (a *AdminServer) EnableUser(ctx *gin.Context) (interface{}, error) {
+	req := &admin_po.UserReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = a.adminService.EnableUser(ctx, &admin_dto.EnableUserReq{UserId: req.UserId})
+	return &common_po.CommonResp{}, err
+}
+
+func (a *AdminServer) Impersonate(ctx *gin.Context) (interface{}, error) {
+	req := &admin_po.UserReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = a.adminService.Impersonate(ctx, &admin_dto.ImpersonateReq{UserId: req.UserId})
+	return &common_po.CommonResp{}, err
+}
+
+func (a *AdminServer) AuditList(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &audit_po.AuditListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	dtoReq, err := audit_assembly.ConvertALRPoToDto(req, pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	r, err := a.adminService.AuditList(ctx, dtoReq)
+	if err != nil {
+		return nil, err
+	}
+	return convertAuditListResp(r), nil
+}
+
+func convertAuditListResp(r *audit_dto.AuditListResp) *audit_po.AuditListResp {
+	list := make([]*audit_po.AuditLog, 0, len(r.List))
+	for _, log := range r.List {
+		list = append(list, audit_assembly.ConvertAuditDtoToPo(log))
+	}
+	return &audit_po.AuditListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}
+}
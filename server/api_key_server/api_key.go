@@ -0,0 +1,69 @@
+package api_key_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/po/api_key_po"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/server/assembly/api_key_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/api_key_service"
+	"github.com/shop_management/sm_error"
+)
+
+type ApiKeyServer struct {
+	apiKeyService service.ApiKeyService
+}
+
+func NewApiKeyServer() *ApiKeyServer {
+	return &ApiKeyServer{
+		apiKeyService: api_key_service.NewApiKeyServiceImpl(),
+	}
+}
+
+func (a *ApiKeyServer) Create(ctx *gin.Context) (interface{}, error) {
+	req := &api_key_po.CreateApiKeyReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	apiKey, err := a.apiKeyService.Create(ctx, api_key_assembly.ConvertCAKRPoToDto(req))
+	if err != nil {
+		return nil, err
+	}
+	return api_key_assembly.ConvertApiKeyDtoToPo(apiKey), nil
+}
+
+func (a *ApiKeyServer) List(ctx *gin.Context) (interface{}, error) {
+	list, err := a.apiKeyService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &api_key_po.ApiKeyListResp{List: make([]*api_key_po.ApiKey, 0, len(list))}
+	for _, apiKey := range list {
+		resp.List = append(resp.List, api_key_assembly.ConvertApiKeyDtoToPo(apiKey))
+	}
+	return resp, nil
+}
+
+func (a *ApiKeyServer) Rotate(ctx *gin.Context) (interface{}, error) {
+	req := &api_key_po.ApiKeyIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	apiKey, err := a.apiKeyService.Rotate(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return api_key_assembly.ConvertApiKeyDtoToPo(apiKey), nil
+}
+
+func (a *ApiKeyServer) Revoke(ctx *gin.Context) (interface{}, error) {
+	req := &api_key_po.ApiKeyIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = a.apiKeyService.Revoke(ctx, req.ID)
+	return &common_po.CommonResp{}, err
+}
@@ -0,0 +1,50 @@
+package admin_assembly
+
+import (
+	"github.com/shop_management/dto/admin_dto"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/po/admin_po"
+	"github.com/shop_management/po/user_po"
+	"github.com/shop_management/server/assembly/user_assembly"
+	"github.com/shop_management/util"
+)
+
+func ConvertUserDtoToPo(u *user_dto.User) *admin_po.User {
+	r := &admin_po.User{
+		Id:            u.Id,
+		Name:          u.Name,
+		Phone:         u.Phone,
+		Email:         u.Email,
+		AvatarUrl:     u.AvatarUrl,
+		PlatformAdmin: u.PlatformAdmin,
+		Disabled:      u.IsDisabled(),
+		Deactivated:   u.IsDeactivated(),
+		CreateTime:    util.FormatTime(u.CreateTime),
+	}
+	if u.IsDisabled() {
+		r.DisabledTime = util.FormatTime(*u.DisabledTime)
+	}
+	if u.IsDeactivated() {
+		r.DeactivatedTime = util.FormatTime(*u.DeletedTime)
+	}
+	return r
+}
+
+func ConvertUserDetailDtoToPo(d *admin_dto.UserDetail) *admin_po.UserDetailResp {
+	r := &admin_po.UserDetailResp{
+		User:     ConvertUserDtoToPo(d.User),
+		Sessions: make([]*user_po.Session, 0, len(d.Sessions)),
+	}
+	if d.Team != nil {
+		r.Team = &admin_po.UserTeam{
+			OwnerId:     d.Team.OwnerId,
+			OwnerName:   d.Team.OwnerName,
+			Role:        d.Team.Role,
+			MemberCount: d.Team.MemberCount,
+		}
+	}
+	for _, session := range d.Sessions {
+		r.Sessions = append(r.Sessions, user_assembly.ConvertSessionDtoToPo(session))
+	}
+	return r
+}
@@ -0,0 +1,31 @@
+package api_key_assembly
+
+import (
+	"github.com/shop_management/dto/api_key_dto"
+	"github.com/shop_management/po/api_key_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertCAKRPoToDto(req *api_key_po.CreateApiKeyReq) *api_key_dto.CreateApiKeyReq {
+	return &api_key_dto.CreateApiKeyReq{
+		Name:   req.Name,
+		Scopes: req.Scopes,
+	}
+}
+
+func ConvertApiKeyDtoToPo(a *api_key_dto.ApiKey) *api_key_po.ApiKey {
+	lastUsedTime := ""
+	if a.LastUsedTime != nil {
+		lastUsedTime = util.FormatTime(*a.LastUsedTime)
+	}
+	return &api_key_po.ApiKey{
+		ID:           a.ID,
+		Name:         a.Name,
+		AccessKey:    a.AccessKey,
+		Secret:       a.Secret,
+		Scopes:       a.Scopes,
+		Status:       a.Status,
+		LastUsedTime: lastUsedTime,
+		CreateTime:   util.FormatTime(a.CreateTime),
+	}
+}
@@ -0,0 +1,57 @@
+package audit_assembly
+
+import (
+	"github.com/shop_management/dto/audit_dto"
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/po/audit_po"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/util"
+	"time"
+)
+
+// ConvertALRPoToDto 结束日期包含当天
+func ConvertALRPoToDto(req *audit_po.AuditListReq, pager *common_po.Pager) (*audit_dto.AuditListReq, error) {
+	r := &audit_dto.AuditListReq{
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		ActorID:    req.ActorId,
+		Action:     req.Action,
+		EntityType: req.EntityType,
+		EntityID:   req.EntityId,
+		RequestID:  req.RequestId,
+	}
+	if req.StartDate != "" {
+		startTime, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		r.StartTime = startTime
+	}
+	if req.EndDate != "" {
+		endTime, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		r.EndTime = endTime.AddDate(0, 0, 1)
+	}
+	return r, nil
+}
+
+func ConvertAuditDtoToPo(a *audit_dto.AuditLog) *audit_po.AuditLog {
+	return &audit_po.AuditLog{
+		Id:             a.ID,
+		ActorId:        a.ActorID,
+		ActorName:      a.ActorName,
+		ImpersonatorId: a.ImpersonatorID,
+		Action:         a.Action,
+		EntityType:     a.EntityType,
+		EntityId:       a.EntityID,
+		Before:         a.BeforeData,
+		After:          a.AfterData,
+		Ip:             a.Ip,
+		RequestId:      a.RequestID,
+		CreateTime:     util.FormatTime(a.CreateTime),
+	}
+}
@@ -0,0 +1,31 @@
+package currency_assembly
+
+import (
+	"github.com/shop_management/dto/currency_dto"
+	"github.com/shop_management/po/currency_po"
+	"time"
+)
+
+func ConvertSRRPoToDto(req *currency_po.SaveRateReq) (*currency_dto.ExchangeRate, error) {
+	effectiveDate, err := time.ParseInLocation("2006-01-02", req.EffectiveDate, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &currency_dto.ExchangeRate{
+		FromCurrency:  req.FromCurrency,
+		ToCurrency:    req.ToCurrency,
+		Rate:          req.Rate,
+		EffectiveDate: effectiveDate,
+	}, nil
+}
+
+func ConvertERDtoToPo(e *currency_dto.ExchangeRate) *currency_po.ExchangeRate {
+	return &currency_po.ExchangeRate{
+		ID:            e.ID,
+		FromCurrency:  e.FromCurrency,
+		ToCurrency:    e.ToCurrency,
+		Rate:          e.Rate,
+		EffectiveDate: e.EffectiveDate.Format("2006-01-02"),
+		Source:        e.Source,
+	}
+}
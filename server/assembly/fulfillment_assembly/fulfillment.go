@@ -0,0 +1,75 @@
+package fulfillment_assembly
+
+import (
+	"github.com/shop_management/dto/fulfillment_dto"
+	"github.com/shop_management/po/fulfillment_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertPTDtoToPo(t *fulfillment_dto.PickTask) *fulfillment_po.PickTask {
+	items := make([]*fulfillment_po.PickTaskItem, 0, len(t.Items))
+	for _, item := range t.Items {
+		items = append(items, &fulfillment_po.PickTaskItem{
+			ID:          item.ID,
+			OrderItemID: item.OrderItemID,
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			StorageCode: item.StorageCode,
+			Quantity:    item.Quantity,
+		})
+	}
+	return &fulfillment_po.PickTask{
+		ID:         t.ID,
+		OrderID:    t.OrderID,
+		Warehouse:  t.Warehouse,
+		StoragePos: t.StoragePos,
+		Status:     t.Status,
+		AssigneeID: t.AssigneeID,
+		ClaimTime:  util.FormatTimePtr(t.ClaimTime),
+		PickedTime: util.FormatTimePtr(t.PickedTime),
+		Items:      items,
+	}
+}
+
+func ConvertPTListDtoToPo(tasks []*fulfillment_dto.PickTask) *fulfillment_po.PickTaskListResp {
+	list := make([]*fulfillment_po.PickTask, 0, len(tasks))
+	for _, task := range tasks {
+		list = append(list, ConvertPTDtoToPo(task))
+	}
+	return &fulfillment_po.PickTaskListResp{List: list}
+}
+
+func ConvertPRDtoToPo(r *fulfillment_dto.PackRecord) *fulfillment_po.PackResp {
+	return &fulfillment_po.PackResp{
+		ID:       r.ID,
+		OrderID:  r.OrderID,
+		PackerID: r.PackerID,
+		PackTime: util.FormatTime(r.PackTime),
+	}
+}
+
+func ConvertSDtoToPo(s *fulfillment_dto.Shipment) *fulfillment_po.ShipResp {
+	return &fulfillment_po.ShipResp{
+		ID:         s.ID,
+		OrderID:    s.OrderID,
+		Carrier:    s.Carrier,
+		TrackingNo: s.TrackingNo,
+		ShipperID:  s.ShipperID,
+		ShipTime:   util.FormatTime(s.ShipTime),
+	}
+}
+
+func ConvertFTDtoToPo(t *fulfillment_dto.FulfillmentTimeline) *fulfillment_po.TimelineResp {
+	return &fulfillment_po.TimelineResp{
+		OrderID:        t.OrderID,
+		ConfirmTime:    util.FormatTimePtr(t.ConfirmTime),
+		FirstClaimTime: util.FormatTimePtr(t.FirstClaimTime),
+		LastPickedTime: util.FormatTimePtr(t.LastPickedTime),
+		PackTime:       util.FormatTimePtr(t.PackTime),
+		ShipTime:       util.FormatTimePtr(t.ShipTime),
+		PickSeconds:    t.PickSeconds,
+		PackSeconds:    t.PackSeconds,
+		ShipSeconds:    t.ShipSeconds,
+		TotalSeconds:   t.TotalSeconds,
+	}
+}
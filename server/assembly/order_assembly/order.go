@@ -0,0 +1,77 @@
+package order_assembly
+
+import (
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/po/order_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertAORPoToDto(req *order_po.AddOrderReq) *order_dto.AddOrderReq {
+	items := make([]*order_dto.AddOrderItemReq, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, &order_dto.AddOrderItemReq{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+	return &order_dto.AddOrderReq{
+		CustomerID:   req.CustomerID,
+		CustomerName: req.CustomerName,
+		Remark:       req.Remark,
+		CouponCodes:  req.CouponCodes,
+		Items:        items,
+	}
+}
+
+func ConvertSODtoToPo(o *order_dto.SalesOrder) *order_po.SalesOrder {
+	items := make([]*order_po.SalesOrderItem, 0, len(o.Items))
+	for _, item := range o.Items {
+		items = append(items, &order_po.SalesOrderItem{
+			ID:             item.ID,
+			ProductID:      item.ProductID,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.UnitPrice,
+			DiscountAmount: item.DiscountAmount,
+			Amount:         item.Amount,
+			TaxRate:        item.TaxRate,
+			NetAmount:      item.NetAmount,
+			TaxAmount:      item.TaxAmount,
+		})
+	}
+	return &order_po.SalesOrder{
+		ID:             o.ID,
+		OrderNo:        o.OrderNo,
+		UserID:         o.UserID,
+		CustomerID:     o.CustomerID,
+		CustomerName:   o.CustomerName,
+		Remark:         o.Remark,
+		Status:         o.Status,
+		Currency:       o.Currency,
+		TotalAmount:    o.TotalAmount,
+		DiscountAmount: o.DiscountAmount,
+		NetAmount:      o.NetAmount,
+		TaxAmount:      o.TaxAmount,
+		ConfirmTime:    util.FormatTimePtr(o.ConfirmTime),
+		CreateTime:     util.FormatTime(o.CreateTime),
+		Items:          items,
+	}
+}
+
+func ConvertIDtoToPo(i *order_dto.Invoice) *order_po.Invoice {
+	breakdown := make([]*order_po.TaxBreakdown, 0, len(i.TaxBreakdown))
+	for _, b := range i.TaxBreakdown {
+		breakdown = append(breakdown, &order_po.TaxBreakdown{
+			TaxRate:   b.TaxRate,
+			NetAmount: b.NetAmount,
+			TaxAmount: b.TaxAmount,
+		})
+	}
+	return &order_po.Invoice{
+		InvoiceNo:    i.InvoiceNo,
+		Order:        ConvertSODtoToPo(i.Order),
+		NetAmount:    i.NetAmount,
+		TaxAmount:    i.TaxAmount,
+		TotalAmount:  i.TotalAmount,
+		TaxBreakdown: breakdown,
+	}
+}
@@ -0,0 +1,36 @@
+package pos_assembly
+
+import (
+	"github.com/jinzhu/copier"
+	"github.com/shop_management/dto/pos_dto"
+	"github.com/shop_management/po/pos_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertPSDtoToPo(s *pos_dto.PosShift) *pos_po.PosShift {
+	return &pos_po.PosShift{
+		ID:             s.ID,
+		UserID:         s.UserID,
+		Status:         s.Status,
+		Currency:       s.Currency,
+		OpeningCash:    s.OpeningCash,
+		ClosingCash:    s.ClosingCash,
+		ExpectedCash:   s.ExpectedCash,
+		CashDifference: s.CashDifference,
+		OpenTime:       util.FormatTime(s.OpenTime),
+		CloseTime:      util.FormatTimePtr(s.CloseTime),
+	}
+}
+
+func ConvertCRPoToDto(req *pos_po.CheckoutReq) *pos_dto.CheckoutReq {
+	convertRes := &pos_dto.CheckoutReq{}
+	_ = copier.Copy(convertRes, req)
+	return convertRes
+}
+
+func ConvertRDtoToPo(r *pos_dto.Receipt) *pos_po.Receipt {
+	convertRes := &pos_po.Receipt{}
+	_ = copier.Copy(convertRes, r)
+	convertRes.CheckoutTime = util.FormatTime(r.CheckoutTime)
+	return convertRes
+}
@@ -0,0 +1,31 @@
+package product_assembly
+
+import (
+	"github.com/shop_management/dto/product_dto"
+	"github.com/shop_management/po/product_po"
+)
+
+// ConvertPDtoToPo 成本价和采购价为 0 时不返回, 没有查看成本权限的用户在 service 中已被清零
+func ConvertPDtoToPo(p *product_dto.Product) *product_po.Product {
+	return &product_po.Product{
+		ID:               p.ID,
+		ImageURL:         p.ImageURL,
+		StorageCode:      p.StorageCode,
+		Barcode:          p.Barcode,
+		StoragePos:       p.StoragePos,
+		Warehouse:        p.Warehouse,
+		Name:             p.Name,
+		Color:            p.Color,
+		Category:         p.Category,
+		BasePrice:        p.BasePrice,
+		SaleCurrency:     p.SaleCurrency,
+		CostPrice:        p.CostPrice,
+		PurchasePrice:    p.PurchasePrice,
+		PurchaseCurrency: p.PurchaseCurrency,
+		Factory:          p.Factory,
+		Stock:            p.Stock,
+		InProductionNums: p.InProductionNums,
+		InOrderNums:      p.InOrderNums,
+		HeldNums:         p.HeldNums,
+	}
+}
@@ -0,0 +1,66 @@
+package promotion_assembly
+
+import (
+	"github.com/jinzhu/copier"
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/po/promotion_po"
+	"github.com/shop_management/util"
+	"time"
+)
+
+func ConvertAPRPoToDto(req *promotion_po.AddPromotionReq) (*promotion_dto.Promotion, error) {
+	startTime, err := time.ParseInLocation("2006-01-02 15:04:05", req.StartTime, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := time.ParseInLocation("2006-01-02 15:04:05", req.EndTime, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	rule := &promotion_dto.PromotionRule{}
+	_ = copier.Copy(rule, req.Rule)
+	return &promotion_dto.Promotion{
+		Name:       req.Name,
+		Type:       req.Type,
+		Category:   req.Category,
+		Rule:       rule,
+		CouponCode: req.CouponCode,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		UsageLimit: req.UsageLimit,
+		Stackable:  req.Stackable,
+		Priority:   req.Priority,
+	}, nil
+}
+
+func ConvertPDtoToPo(p *promotion_dto.Promotion) *promotion_po.Promotion {
+	rule := &promotion_po.PromotionRule{}
+	_ = copier.Copy(rule, p.Rule)
+	return &promotion_po.Promotion{
+		ID:         p.ID,
+		Name:       p.Name,
+		Type:       p.Type,
+		Category:   p.Category,
+		Rule:       rule,
+		CouponCode: p.CouponCode,
+		StartTime:  util.FormatTime(p.StartTime),
+		EndTime:    util.FormatTime(p.EndTime),
+		UsageLimit: p.UsageLimit,
+		UsedCount:  p.UsedCount,
+		Stackable:  p.Stackable,
+		Priority:   p.Priority,
+		Status:     p.Status,
+	}
+}
+
+func ConvertERPoToDto(req *promotion_po.EvaluateReq) *promotion_dto.EvaluateReq {
+	convertRes := &promotion_dto.EvaluateReq{}
+	_ = copier.Copy(convertRes, req)
+	return convertRes
+}
+
+func ConvertERDtoToPo(resp *promotion_dto.EvaluateResp) *promotion_po.EvaluateResp {
+	convertRes := &promotion_po.EvaluateResp{}
+	_ = copier.Copy(convertRes, resp)
+	return convertRes
+}
@@ -0,0 +1,66 @@
+package quotation_assembly
+
+import (
+	"github.com/shop_management/dto/quotation_dto"
+	"github.com/shop_management/po/quotation_po"
+	"github.com/shop_management/util"
+	"time"
+)
+
+func ConvertAQRPoToDto(req *quotation_po.AddQuotationReq) (*quotation_dto.SaveQuotationReq, error) {
+	validUntil, err := time.ParseInLocation("2006-01-02 15:04:05", req.ValidUntil, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*quotation_dto.SaveQuotationItemReq, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, &quotation_dto.SaveQuotationItemReq{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+		})
+	}
+	return &quotation_dto.SaveQuotationReq{
+		CustomerID:   req.CustomerID,
+		CustomerName: req.CustomerName,
+		Remark:       req.Remark,
+		HoldStock:    req.HoldStock,
+		ValidUntil:   validUntil,
+		Items:        items,
+	}, nil
+}
+
+func ConvertQDtoToPo(q *quotation_dto.Quotation) *quotation_po.Quotation {
+	items := make([]*quotation_po.QuotationItem, 0, len(q.Items))
+	for _, item := range q.Items {
+		items = append(items, &quotation_po.QuotationItem{
+			ID:        item.ID,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Amount:    item.Amount,
+			TaxRate:   item.TaxRate,
+			NetAmount: item.NetAmount,
+			TaxAmount: item.TaxAmount,
+		})
+	}
+	return &quotation_po.Quotation{
+		ID:           q.ID,
+		QuotationNo:  q.QuotationNo,
+		Version:      q.Version,
+		UserID:       q.UserID,
+		CustomerID:   q.CustomerID,
+		CustomerName: q.CustomerName,
+		Remark:       q.Remark,
+		Status:       q.Status,
+		Currency:     q.Currency,
+		HoldStock:    q.HoldStock,
+		TotalAmount:  q.TotalAmount,
+		NetAmount:    q.NetAmount,
+		TaxAmount:    q.TaxAmount,
+		ValidUntil:   util.FormatTime(q.ValidUntil),
+		OrderID:      q.OrderID,
+		CreateTime:   util.FormatTime(q.CreateTime),
+		Items:        items,
+	}
+}
@@ -0,0 +1,36 @@
+package report_assembly
+
+import (
+	"github.com/jinzhu/copier"
+	"github.com/shop_management/dto/report_dto"
+	"github.com/shop_management/po/report_po"
+	"time"
+)
+
+// ConvertSRRPoToDto 结束日期包含当天
+func ConvertSRRPoToDto(req *report_po.SalesReportReq) (*report_dto.SalesReportReq, error) {
+	startTime, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	return &report_dto.SalesReportReq{
+		StartTime: startTime,
+		EndTime:   endTime.AddDate(0, 0, 1),
+	}, nil
+}
+
+func ConvertSRDtoToPo(r *report_dto.SalesReport) *report_po.SalesReport {
+	convertRes := &report_po.SalesReport{}
+	_ = copier.Copy(convertRes, r)
+	return convertRes
+}
+
+func ConvertIVDtoToPo(v *report_dto.InventoryValuation) *report_po.InventoryValuation {
+	convertRes := &report_po.InventoryValuation{}
+	_ = copier.Copy(convertRes, v)
+	return convertRes
+}
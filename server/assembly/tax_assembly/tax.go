@@ -0,0 +1,26 @@
+package tax_assembly
+
+import (
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/po/tax_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertTRDtoToPo(t *tax_dto.TaxRule) *tax_po.TaxRule {
+	return &tax_po.TaxRule{
+		ID:         t.ID,
+		Name:       t.Name,
+		Category:   t.Category,
+		Rate:       t.Rate,
+		ModifyTime: util.FormatTime(t.ModifyTime),
+	}
+}
+
+func ConvertTEDtoToPo(t *tax_dto.TaxExemption) *tax_po.TaxExemption {
+	return &tax_po.TaxExemption{
+		ID:         t.ID,
+		CustomerID: t.CustomerID,
+		Reason:     t.Reason,
+		CreateTime: util.FormatTime(t.CreateTime),
+	}
+}
@@ -0,0 +1,16 @@
+package user_assembly
+
+import (
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/po/user_po"
+)
+
+func ConvertGrantDtoToPo(g *user_dto.UserGrant) *user_po.UserGrant {
+	return &user_po.UserGrant{
+		Id:           g.ID,
+		SubUserId:    g.UserID,
+		ResourceType: g.ResourceType,
+		ResourceId:   g.ResourceID,
+		Action:       g.Action,
+	}
+}
@@ -0,0 +1,30 @@
+package user_assembly
+
+import (
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/po/user_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertIRPoToDto(req *user_po.InviteReq) *user_dto.InviteReq {
+	return &user_dto.InviteReq{
+		Phone: req.Phone,
+		Email: req.Email,
+		Role:  req.Role,
+	}
+}
+
+func ConvertInvitationDtoToPo(i *user_dto.Invitation) *user_po.Invitation {
+	return &user_po.Invitation{
+		ID:          i.ID,
+		InviterName: i.InviterName,
+		Phone:       i.Phone,
+		Email:       i.Email,
+		Role:        i.Role,
+		Status:      i.Status,
+		InviteeID:   i.InviteeID,
+		Token:       i.Token,
+		ExpireTime:  util.FormatTime(i.ExpireTime),
+		CreateTime:  util.FormatTime(i.CreateTime),
+	}
+}
@@ -0,0 +1,19 @@
+package user_assembly
+
+import (
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/po/user_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertLoginLockoutDtoToPo(l *user_dto.LoginLockout) *user_po.LoginLockout {
+	return &user_po.LoginLockout{
+		Id:          l.ID,
+		UserId:      l.UserID,
+		Phone:       l.Phone,
+		Ip:          l.Ip,
+		UserAgent:   l.UserAgent,
+		LockedUntil: util.FormatTime(l.LockedUntil),
+		CreateTime:  util.FormatTime(l.CreateTime),
+	}
+}
@@ -0,0 +1,20 @@
+package user_assembly
+
+import (
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/po/user_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertSessionDtoToPo(s *user_dto.Session) *user_po.Session {
+	return &user_po.Session{
+		ID:           s.ID,
+		UserAgent:    s.UserAgent,
+		Ip:           s.Ip,
+		Current:      s.Current,
+		Impersonated: s.ImpersonatorID != "",
+		CreateTime:   util.FormatTime(s.CreateTime),
+		LastSeenTime: util.FormatTime(s.LastSeenTime),
+		ExpireTime:   util.FormatTime(s.ExpireTime),
+	}
+}
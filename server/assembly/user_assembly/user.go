@@ -24,6 +24,29 @@ func ConvertUPDtoToPo(req *user_dto.UserProfile) *user_po.UserProfile {
 		Name:      req.Name,
 		Email:     req.Email,
 		Phone:     req.Phone,
+		UserId:    req.UserId,
+		Role:      req.Role,
 		IsAdmin:   req.IsAdmin,
 	}
 }
+
+func ConvertMPPoToDto(req *user_po.ModifyPasswordReq, userId string) *user_dto.ModifyUserPasswordReq {
+	return &user_dto.ModifyUserPasswordReq{
+		UserId:          userId,
+		OldPassword:     req.OldPassword,
+		NewPassword:     req.NewPassword,
+		ConfirmPassword: req.ConfirmPassword,
+	}
+}
+
+func ConvertSRCPoToDto(req *user_po.SendResetCodeReq) *user_dto.SendResetCodeReq {
+	convertRes := &user_dto.SendResetCodeReq{}
+	_ = copier.Copy(convertRes, req)
+	return convertRes
+}
+
+func ConvertRPPoToDto(req *user_po.ResetPasswordReq) *user_dto.ResetPasswordReq {
+	convertRes := &user_dto.ResetPasswordReq{}
+	_ = copier.Copy(convertRes, req)
+	return convertRes
+}
@@ -0,0 +1,38 @@
+package wallet_assembly
+
+import (
+	"github.com/shop_management/dto/wallet_dto"
+	"github.com/shop_management/po/wallet_po"
+	"github.com/shop_management/util"
+)
+
+func ConvertOwnerPoToDto(o *wallet_po.WalletOwner) wallet_dto.WalletOwner {
+	return wallet_dto.WalletOwner{
+		OwnerType: o.OwnerType,
+		OwnerID:   o.OwnerId,
+	}
+}
+
+func ConvertWalletDtoToPo(w *wallet_dto.Wallet) *wallet_po.Wallet {
+	return &wallet_po.Wallet{
+		Id:        w.ID,
+		OwnerType: w.OwnerType,
+		OwnerId:   w.OwnerID,
+		Currency:  w.Currency,
+		Balance:   w.Balance,
+	}
+}
+
+func ConvertEntryDtoToPo(e *wallet_dto.WalletEntry) *wallet_po.WalletEntry {
+	return &wallet_po.WalletEntry{
+		Id:           e.ID,
+		Type:         e.Type,
+		Amount:       e.Amount,
+		BalanceAfter: e.BalanceAfter,
+		RefEntryId:   e.RefEntryID,
+		Reference:    e.Reference,
+		Remark:       e.Remark,
+		OperatorId:   e.OperatorID,
+		CreateTime:   util.FormatTime(e.CreateTime),
+	}
+}
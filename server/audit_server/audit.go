@@ -0,0 +1,55 @@
+package audit_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/po/audit_po"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/server/assembly/audit_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/audit_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+)
+
+type AuditServer struct {
+	auditService service.AuditService
+}
+
+func NewAuditServer() *AuditServer {
+	return &AuditServer{
+		auditService: audit_service.NewAuditServiceImpl(),
+	}
+}
+
+func (a *AuditServer) List(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &audit_po.AuditListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	dtoReq, err := audit_assembly.ConvertALRPoToDto(req, pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	r, err := a.auditService.List(ctx, dtoReq)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*audit_po.AuditLog, 0, len(r.List))
+	for _, log := range r.List {
+		list = append(list, audit_assembly.ConvertAuditDtoToPo(log))
+	}
+	return &audit_po.AuditListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
+}
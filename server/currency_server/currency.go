@@ -0,0 +1,94 @@
+package currency_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/currency_dto"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/currency_po"
+	"github.com/shop_management/server/assembly/currency_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/currency_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+)
+
+type CurrencyServer struct {
+	currencyService service.CurrencyService
+}
+
+func NewCurrencyServer() *CurrencyServer {
+	return &CurrencyServer{
+		currencyService: currency_service.NewCurrencyServiceImpl(),
+	}
+}
+
+func (c *CurrencyServer) BaseCurrency(ctx *gin.Context) (interface{}, error) {
+	currency, err := c.currencyService.GetBaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &currency_po.BaseCurrency{BaseCurrency: currency}, nil
+}
+
+func (c *CurrencyServer) SaveBaseCurrency(ctx *gin.Context) (interface{}, error) {
+	req := &currency_po.BaseCurrency{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = c.currencyService.SaveBaseCurrency(ctx, req.BaseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (c *CurrencyServer) SaveRate(ctx *gin.Context) (interface{}, error) {
+	req := &currency_po.SaveRateReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	dto, err := currency_assembly.ConvertSRRPoToDto(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	rate, err := c.currencyService.SaveRate(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+	return currency_assembly.ConvertERDtoToPo(rate), nil
+}
+
+func (c *CurrencyServer) ImportRates(ctx *gin.Context) (interface{}, error) {
+	req := &currency_po.ImportRatesReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	imported, err := c.currencyService.ImportRates(ctx, req.Url)
+	if err != nil {
+		return nil, err
+	}
+	return &currency_po.ImportRatesResp{Imported: imported}, nil
+}
+
+func (c *CurrencyServer) RateList(ctx *gin.Context) (interface{}, error) {
+	req := &currency_po.RateListReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	rates, err := c.currencyService.ListRates(ctx, &currency_dto.RateListReq{
+		FromCurrency: req.FromCurrency,
+		ToCurrency:   req.ToCurrency,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*currency_po.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		list = append(list, currency_assembly.ConvertERDtoToPo(rate))
+	}
+	return &currency_po.RateListResp{List: list}, nil
+}
@@ -0,0 +1,124 @@
+package fulfillment_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/fulfillment_dto"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/fulfillment_po"
+	"github.com/shop_management/po/order_po"
+	"github.com/shop_management/server/assembly/fulfillment_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/fulfillment_service"
+	"github.com/shop_management/sm_error"
+)
+
+type FulfillmentServer struct {
+	fulfillmentService service.FulfillmentService
+}
+
+func NewFulfillmentServer() *FulfillmentServer {
+	return &FulfillmentServer{
+		fulfillmentService: fulfillment_service.NewFulfillmentServiceImpl(),
+	}
+}
+
+func (f *FulfillmentServer) GeneratePickList(ctx *gin.Context) (interface{}, error) {
+	req := &order_po.OrderIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	tasks, err := f.fulfillmentService.GeneratePickList(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	return fulfillment_assembly.ConvertPTListDtoToPo(tasks), nil
+}
+
+func (f *FulfillmentServer) PickTaskList(ctx *gin.Context) (interface{}, error) {
+	req := &fulfillment_po.PickTaskListReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	tasks, err := f.fulfillmentService.PickTaskList(ctx, &fulfillment_dto.PickTaskListReq{
+		OrderID: req.OrderID,
+		Status:  req.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fulfillment_assembly.ConvertPTListDtoToPo(tasks), nil
+}
+
+func (f *FulfillmentServer) ClaimPickTask(ctx *gin.Context) (interface{}, error) {
+	req := &fulfillment_po.PickTaskIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = f.fulfillmentService.ClaimPickTask(ctx, req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (f *FulfillmentServer) FinishPickTask(ctx *gin.Context) (interface{}, error) {
+	req := &fulfillment_po.PickTaskIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = f.fulfillmentService.FinishPickTask(ctx, req.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (f *FulfillmentServer) Pack(ctx *gin.Context) (interface{}, error) {
+	req := &fulfillment_po.PackReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	record, err := f.fulfillmentService.Pack(ctx, &fulfillment_dto.PackReq{
+		OrderID:      req.OrderID,
+		StorageCodes: req.StorageCodes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fulfillment_assembly.ConvertPRDtoToPo(record), nil
+}
+
+func (f *FulfillmentServer) Ship(ctx *gin.Context) (interface{}, error) {
+	req := &fulfillment_po.ShipReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	shipment, err := f.fulfillmentService.Ship(ctx, &fulfillment_dto.ShipReq{
+		OrderID:    req.OrderID,
+		Carrier:    req.Carrier,
+		TrackingNo: req.TrackingNo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fulfillment_assembly.ConvertSDtoToPo(shipment), nil
+}
+
+func (f *FulfillmentServer) Timeline(ctx *gin.Context) (interface{}, error) {
+	req := &order_po.OrderIdReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	timeline, err := f.fulfillmentService.Timeline(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	return fulfillment_assembly.ConvertFTDtoToPo(timeline), nil
+}
@@ -0,0 +1,111 @@
+package order_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/order_po"
+	"github.com/shop_management/server/assembly/order_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/order_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+)
+
+type OrderServer struct {
+	orderService service.OrderService
+}
+
+func NewOrderServer() *OrderServer {
+	return &OrderServer{
+		orderService: order_service.NewOrderServiceImpl(),
+	}
+}
+
+func (o *OrderServer) Add(ctx *gin.Context) (interface{}, error) {
+	req := &order_po.AddOrderReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	order, err := o.orderService.Add(ctx, order_assembly.ConvertAORPoToDto(req))
+	if err != nil {
+		return nil, err
+	}
+	return order_assembly.ConvertSODtoToPo(order), nil
+}
+
+func (o *OrderServer) Confirm(ctx *gin.Context) (interface{}, error) {
+	req := &order_po.OrderIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = o.orderService.Confirm(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (o *OrderServer) Detail(ctx *gin.Context) (interface{}, error) {
+	req := &order_po.OrderIdReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	order, err := o.orderService.Detail(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	return order_assembly.ConvertSODtoToPo(order), nil
+}
+
+func (o *OrderServer) Invoice(ctx *gin.Context) (interface{}, error) {
+	req := &order_po.OrderIdReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	invoice, err := o.orderService.Invoice(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	return order_assembly.ConvertIDtoToPo(invoice), nil
+}
+
+func (o *OrderServer) List(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &order_po.OrderListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	r, err := o.orderService.List(ctx, &order_dto.OrderListReq{
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		Status: req.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*order_po.SalesOrder, 0, len(r.List))
+	for _, order := range r.List {
+		list = append(list, order_assembly.ConvertSODtoToPo(order))
+	}
+	return &order_po.OrderListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
+}
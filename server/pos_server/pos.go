@@ -0,0 +1,67 @@
+package pos_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/po/pos_po"
+	"github.com/shop_management/server/assembly/pos_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/pos_service"
+	"github.com/shop_management/sm_error"
+)
+
+type PosServer struct {
+	posService service.PosService
+}
+
+func NewPosServer() *PosServer {
+	return &PosServer{
+		posService: pos_service.NewPosServiceImpl(),
+	}
+}
+
+func (p *PosServer) OpenShift(ctx *gin.Context) (interface{}, error) {
+	req := &pos_po.OpenShiftReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	shift, err := p.posService.OpenShift(ctx, req.OpeningCash)
+	if err != nil {
+		return nil, err
+	}
+	return pos_assembly.ConvertPSDtoToPo(shift), nil
+}
+
+func (p *PosServer) CurrentShift(ctx *gin.Context) (interface{}, error) {
+	shift, err := p.posService.CurrentShift(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pos_assembly.ConvertPSDtoToPo(shift), nil
+}
+
+func (p *PosServer) CloseShift(ctx *gin.Context) (interface{}, error) {
+	req := &pos_po.CloseShiftReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	shift, err := p.posService.CloseShift(ctx, req.ClosingCash)
+	if err != nil {
+		return nil, err
+	}
+	return pos_assembly.ConvertPSDtoToPo(shift), nil
+}
+
+func (p *PosServer) Checkout(ctx *gin.Context) (interface{}, error) {
+	req := &pos_po.CheckoutReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	receipt, err := p.posService.Checkout(ctx, pos_assembly.ConvertCRPoToDto(req))
+	if err != nil {
+		return nil, err
+	}
+	return pos_assembly.ConvertRDtoToPo(receipt), nil
+}
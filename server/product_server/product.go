@@ -2,12 +2,15 @@ package product_server
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/common_dto"
 	"github.com/shop_management/dto/product_dto"
 	"github.com/shop_management/po/common_po"
 	"github.com/shop_management/po/product_po"
+	"github.com/shop_management/server/assembly/product_assembly"
 	"github.com/shop_management/service"
 	"github.com/shop_management/service/product_service"
 	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
 )
 
 type ProductServer struct {
@@ -30,12 +33,17 @@ func (p ProductServer) Add(ctx *gin.Context) (interface{}, error) {
 		ID:               dto.ID,
 		ImageURL:         dto.ImageURL,
 		StorageCode:      dto.StorageCode,
+		Barcode:          dto.Barcode,
 		StoragePos:       dto.StoragePos,
+		Warehouse:        dto.Warehouse,
 		Name:             dto.Name,
 		Color:            dto.Color,
+		Category:         dto.Category,
 		BasePrice:        dto.BasePrice,
+		SaleCurrency:     dto.SaleCurrency,
 		CostPrice:        dto.CostPrice,
 		PurchasePrice:    dto.PurchasePrice,
+		PurchaseCurrency: dto.PurchaseCurrency,
 		Factory:          dto.Factory,
 		Stock:            dto.Stock,
 		InProductionNums: dto.InProductionNums,
@@ -48,5 +56,37 @@ func (p ProductServer) Add(ctx *gin.Context) (interface{}, error) {
 }
 
 func (p ProductServer) List(ctx *gin.Context) (interface{}, error) {
-	return nil, nil
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &product_po.ProductListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	r, err := p.productService.List(ctx, &product_dto.ProductListReq{
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		Category:  req.Category,
+		Warehouse: req.Warehouse,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*product_po.Product, 0, len(r.Data))
+	for _, product := range r.Data {
+		list = append(list, product_assembly.ConvertPDtoToPo(product))
+	}
+	return &product_po.ProductListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
 }
@@ -0,0 +1,76 @@
+package promotion_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/promotion_po"
+	"github.com/shop_management/server/assembly/promotion_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/promotion_service"
+	"github.com/shop_management/sm_error"
+)
+
+type PromotionServer struct {
+	promotionService service.PromotionService
+}
+
+func NewPromotionServer() *PromotionServer {
+	return &PromotionServer{
+		promotionService: promotion_service.NewPromotionServiceImpl(),
+	}
+}
+
+func (p *PromotionServer) Add(ctx *gin.Context) (interface{}, error) {
+	req := &promotion_po.AddPromotionReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	dto, err := promotion_assembly.ConvertAPRPoToDto(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	promotion, err := p.promotionService.Add(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+	return promotion_assembly.ConvertPDtoToPo(promotion), nil
+}
+
+func (p *PromotionServer) List(ctx *gin.Context) (interface{}, error) {
+	promotions, err := p.promotionService.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*promotion_po.Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		list = append(list, promotion_assembly.ConvertPDtoToPo(promotion))
+	}
+	return &promotion_po.PromotionListResp{List: list}, nil
+}
+
+func (p *PromotionServer) Disable(ctx *gin.Context) (interface{}, error) {
+	req := &promotion_po.PromotionIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = p.promotionService.Disable(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (p *PromotionServer) Evaluate(ctx *gin.Context) (interface{}, error) {
+	req := &promotion_po.EvaluateReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	resp, err := p.promotionService.Evaluate(ctx, promotion_assembly.ConvertERPoToDto(req))
+	if err != nil {
+		return nil, err
+	}
+	return promotion_assembly.ConvertERDtoToPo(resp), nil
+}
@@ -0,0 +1,152 @@
+package quotation_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/dto/quotation_dto"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/quotation_po"
+	"github.com/shop_management/server/assembly/order_assembly"
+	"github.com/shop_management/server/assembly/quotation_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/quotation_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+)
+
+type QuotationServer struct {
+	quotationService service.QuotationService
+}
+
+func NewQuotationServer() *QuotationServer {
+	return &QuotationServer{
+		quotationService: quotation_service.NewQuotationServiceImpl(),
+	}
+}
+
+func (q *QuotationServer) Add(ctx *gin.Context) (interface{}, error) {
+	req := &quotation_po.AddQuotationReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	dto, err := quotation_assembly.ConvertAQRPoToDto(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	quotation, err := q.quotationService.Add(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+	return quotation_assembly.ConvertQDtoToPo(quotation), nil
+}
+
+func (q *QuotationServer) Revise(ctx *gin.Context) (interface{}, error) {
+	req := &quotation_po.ReviseQuotationReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	dto, err := quotation_assembly.ConvertAQRPoToDto(&req.AddQuotationReq)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	dto.QuotationID = req.QuotationID
+	quotation, err := q.quotationService.Revise(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+	return quotation_assembly.ConvertQDtoToPo(quotation), nil
+}
+
+func (q *QuotationServer) Detail(ctx *gin.Context) (interface{}, error) {
+	req := &quotation_po.QuotationIdReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	quotation, err := q.quotationService.Detail(ctx, req.QuotationID)
+	if err != nil {
+		return nil, err
+	}
+	return quotation_assembly.ConvertQDtoToPo(quotation), nil
+}
+
+func (q *QuotationServer) List(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &quotation_po.QuotationListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	r, err := q.quotationService.List(ctx, &quotation_dto.QuotationListReq{
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		QuotationNo: req.QuotationNo,
+		Status:      req.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*quotation_po.Quotation, 0, len(r.List))
+	for _, quotation := range r.List {
+		list = append(list, quotation_assembly.ConvertQDtoToPo(quotation))
+	}
+	return &quotation_po.QuotationListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
+}
+
+func (q *QuotationServer) Versions(ctx *gin.Context) (interface{}, error) {
+	req := &quotation_po.QuotationIdReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	versions, err := q.quotationService.Versions(ctx, req.QuotationID)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*quotation_po.Quotation, 0, len(versions))
+	for _, quotation := range versions {
+		list = append(list, quotation_assembly.ConvertQDtoToPo(quotation))
+	}
+	return &quotation_po.QuotationVersionsResp{List: list}, nil
+}
+
+func (q *QuotationServer) Convert(ctx *gin.Context) (interface{}, error) {
+	req := &quotation_po.QuotationIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	order, err := q.quotationService.Convert(ctx, req.QuotationID)
+	if err != nil {
+		return nil, err
+	}
+	return order_assembly.ConvertSODtoToPo(order), nil
+}
+
+func (q *QuotationServer) Cancel(ctx *gin.Context) (interface{}, error) {
+	req := &quotation_po.QuotationIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = q.quotationService.Cancel(ctx, req.QuotationID)
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
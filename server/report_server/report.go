@@ -0,0 +1,45 @@
+package report_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/po/report_po"
+	"github.com/shop_management/server/assembly/report_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/report_service"
+	"github.com/shop_management/sm_error"
+)
+
+type ReportServer struct {
+	reportService service.ReportService
+}
+
+func NewReportServer() *ReportServer {
+	return &ReportServer{
+		reportService: report_service.NewReportServiceImpl(),
+	}
+}
+
+func (r *ReportServer) Sales(ctx *gin.Context) (interface{}, error) {
+	req := &report_po.SalesReportReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	dto, err := report_assembly.ConvertSRRPoToDto(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	report, err := r.reportService.Sales(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+	return report_assembly.ConvertSRDtoToPo(report), nil
+}
+
+func (r *ReportServer) InventoryValuation(ctx *gin.Context) (interface{}, error) {
+	valuation, err := r.reportService.InventoryValuation(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return report_assembly.ConvertIVDtoToPo(valuation), nil
+}
@@ -0,0 +1,126 @@
+package tax_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/tax_po"
+	"github.com/shop_management/server/assembly/tax_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/tax_service"
+	"github.com/shop_management/sm_error"
+)
+
+type TaxServer struct {
+	taxService service.TaxService
+}
+
+func NewTaxServer() *TaxServer {
+	return &TaxServer{
+		taxService: tax_service.NewTaxServiceImpl(),
+	}
+}
+
+func (t *TaxServer) SaveRule(ctx *gin.Context) (interface{}, error) {
+	req := &tax_po.SaveTaxRuleReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	rule, err := t.taxService.SaveRule(ctx, &tax_dto.TaxRule{
+		Name:     req.Name,
+		Category: req.Category,
+		Rate:     req.Rate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tax_assembly.ConvertTRDtoToPo(rule), nil
+}
+
+func (t *TaxServer) RuleList(ctx *gin.Context) (interface{}, error) {
+	rules, err := t.taxService.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*tax_po.TaxRule, 0, len(rules))
+	for _, rule := range rules {
+		list = append(list, tax_assembly.ConvertTRDtoToPo(rule))
+	}
+	return &tax_po.TaxRuleListResp{List: list}, nil
+}
+
+func (t *TaxServer) DeleteRule(ctx *gin.Context) (interface{}, error) {
+	req := &tax_po.TaxRuleIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = t.taxService.DeleteRule(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (t *TaxServer) Setting(ctx *gin.Context) (interface{}, error) {
+	setting, err := t.taxService.GetSetting(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tax_po.TaxSetting{PriceIncludesTax: setting.PriceIncludesTax}, nil
+}
+
+func (t *TaxServer) SaveSetting(ctx *gin.Context) (interface{}, error) {
+	req := &tax_po.TaxSetting{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = t.taxService.SaveSetting(ctx, &tax_dto.TaxSetting{PriceIncludesTax: req.PriceIncludesTax})
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (t *TaxServer) AddExemption(ctx *gin.Context) (interface{}, error) {
+	req := &tax_po.AddTaxExemptionReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	exemption, err := t.taxService.AddExemption(ctx, &tax_dto.TaxExemption{
+		CustomerID: req.CustomerID,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tax_assembly.ConvertTEDtoToPo(exemption), nil
+}
+
+func (t *TaxServer) ExemptionList(ctx *gin.Context) (interface{}, error) {
+	exemptions, err := t.taxService.ListExemptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*tax_po.TaxExemption, 0, len(exemptions))
+	for _, exemption := range exemptions {
+		list = append(list, tax_assembly.ConvertTEDtoToPo(exemption))
+	}
+	return &tax_po.TaxExemptionListResp{List: list}, nil
+}
+
+func (t *TaxServer) DeleteExemption(ctx *gin.Context) (interface{}, error) {
+	req := &tax_po.CustomerIdReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = t.taxService.DeleteExemption(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
@@ -2,6 +2,7 @@ package user_server
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
 	"github.com/shop_management/po/common_po"
 	"github.com/shop_management/po/user_po"
 	"github.com/shop_management/server/assembly/user_assembly"
@@ -9,6 +10,7 @@ import (
 	"github.com/shop_management/service/user_service"
 	"github.com/shop_management/sm_error"
 	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
 )
 
 type UserServer struct {
@@ -28,10 +30,70 @@ func (u *UserServer) Login(ctx *gin.Context) (interface{}, error) {
 		return nil, sm_error.NewHttpError(error_code.ReqParamError)
 	}
 
-	id, err := u.userService.Login(ctx, user_assembly.ConvertULPoToDto(loginReq))
+	result, err := u.userService.Login(ctx, user_assembly.ConvertULPoToDto(loginReq))
+	if err != nil {
+		return nil, err
+	}
+	return &user_po.UserLoginResponse{
+		UserId:            result.UserId,
+		TwoFactorRequired: result.TwoFactorRequired,
+		ChallengeToken:    result.ChallengeToken,
+	}, nil
+}
+
+func (u *UserServer) LoginVerify(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.LoginVerifyReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	id, err := u.userService.LoginVerify(ctx, &user_dto.LoginVerifyReq{ChallengeToken: req.ChallengeToken, Code: req.Code})
 	return &user_po.UserLoginResponse{UserId: id}, err
 }
 
+func (u *UserServer) OidcAuthURL(ctx *gin.Context) (interface{}, error) {
+	authUrl, err := u.userService.OidcAuthURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &user_po.OidcAuthURLResp{AuthUrl: authUrl}, nil
+}
+
+func (u *UserServer) OidcLogin(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.OidcLoginReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	result, err := u.userService.OidcLogin(ctx, &user_dto.OidcLoginReq{Code: req.Code, State: req.State})
+	if err != nil {
+		return nil, err
+	}
+	return &user_po.UserLoginResponse{
+		UserId:            result.UserId,
+		TwoFactorRequired: result.TwoFactorRequired,
+		ChallengeToken:    result.ChallengeToken,
+	}, nil
+}
+
+func (u *UserServer) OidcLinkURL(ctx *gin.Context) (interface{}, error) {
+	authUrl, err := u.userService.OidcLinkURL(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &user_po.OidcAuthURLResp{AuthUrl: authUrl}, nil
+}
+
+func (u *UserServer) OidcLink(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.OidcLoginReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userService.OidcLink(ctx, &user_dto.OidcLoginReq{Code: req.Code, State: req.State})
+	return &common_po.CommonResp{}, err
+}
+
 func (u *UserServer) Register(ctx *gin.Context) (interface{}, error) {
 	registerReq := &user_po.RegisterUserReq{}
 	err := ctx.ShouldBindJSON(registerReq)
@@ -46,3 +108,82 @@ func (u *UserServer) GetUserProfile(ctx *gin.Context) (interface{}, error) {
 	profile, err := u.userService.GetUserProfile(ctx, ctx.Query("user_id"))
 	return user_assembly.ConvertUPDtoToPo(profile), err
 }
+
+func (u *UserServer) SaveUserProfile(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.SaveUserProfileReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userService.SaveUserProfile(ctx, &user_dto.SaveUserProfileReq{Name: req.Name, Email: req.Email})
+	return &common_po.CommonResp{}, err
+}
+
+func (u *UserServer) UploadAvatar(ctx *gin.Context) (interface{}, error) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.FileUploadError)
+	}
+	url, err := u.userService.UploadAvatar(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	return &user_po.UploadAvatarResp{AvatarUrl: url}, nil
+}
+
+func (u *UserServer) ModifyPassword(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.ModifyPasswordReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userService.ModifyPwd(ctx, user_assembly.ConvertMPPoToDto(req, util.GetUserIdByCookie(ctx)))
+	return &common_po.CommonResp{}, err
+}
+
+func (u *UserServer) SendResetCode(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.SendResetCodeReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userService.SendResetCode(ctx, user_assembly.ConvertSRCPoToDto(req))
+	return &common_po.CommonResp{}, err
+}
+
+func (u *UserServer) ResetPassword(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.ResetPasswordReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userService.ResetPassword(ctx, user_assembly.ConvertRPPoToDto(req))
+	return &common_po.CommonResp{}, err
+}
+
+func (u *UserServer) SessionList(ctx *gin.Context) (interface{}, error) {
+	list, err := u.userService.ListSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &user_po.SessionListResp{List: make([]*user_po.Session, 0, len(list))}
+	for _, session := range list {
+		resp.List = append(resp.List, user_assembly.ConvertSessionDtoToPo(session))
+	}
+	return resp, nil
+}
+
+func (u *UserServer) RevokeSession(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.RevokeSessionReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userService.RevokeSession(ctx, &user_dto.RevokeSessionReq{ID: req.ID})
+	return &common_po.CommonResp{}, err
+}
+
+func (u *UserServer) RevokeAllSession(ctx *gin.Context) (interface{}, error) {
+	err := u.userService.RevokeAllSession(ctx)
+	return &common_po.CommonResp{}, err
+}
@@ -0,0 +1,87 @@
+package user_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/user_po"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/user_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/util"
+)
+
+type UserDeactivationServer struct {
+	deactivationService service.UserDeactivationService
+}
+
+func NewUserDeactivationServer() *UserDeactivationServer {
+	return &UserDeactivationServer{
+		deactivationService: user_service.NewUserDeactivationServiceImpl(),
+	}
+}
+
+func (u *UserDeactivationServer) Deactivate(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.DeactivateReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.deactivationService.Deactivate(ctx, &user_dto.DeactivateReq{Password: req.Password})
+	if err != nil {
+		return nil, err
+	}
+	ctx.SetCookie("token", "", -1, "/", "", false, false)
+	ctx.SetCookie("user_id", "", -1, "/", "", false, false)
+	return &common_po.CommonResp{}, nil
+}
+
+func (u *UserDeactivationServer) DeactivateMember(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.DeactivateMemberReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.deactivationService.DeactivateMember(ctx, &user_dto.DeactivateMemberReq{Id: req.Id})
+	return &common_po.CommonResp{}, err
+}
+
+func (u *UserDeactivationServer) DeactivatedList(ctx *gin.Context) (interface{}, error) {
+	list, err := u.deactivationService.DeactivatedList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &user_po.DeactivatedUserListResp{List: make([]*user_po.DeactivatedUser, 0, len(list))}
+	for _, user := range list {
+		resp.List = append(resp.List, &user_po.DeactivatedUser{
+			UserId:          user.UserId,
+			Name:            user.Name,
+			Phone:           user.Phone,
+			Role:            user.Role,
+			DeactivatedTime: util.FormatTime(user.DeactivatedTime),
+			PurgeTime:       util.FormatTime(user.PurgeTime),
+		})
+	}
+	return resp, nil
+}
+
+func (u *UserDeactivationServer) Reactivate(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.ReactivateReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.deactivationService.Reactivate(ctx, &user_dto.ReactivateReq{UserId: req.UserId})
+	return &common_po.CommonResp{}, err
+}
+
+// ReactivateSelf 未登录调用, 恢复后需重新登录
+func (u *UserDeactivationServer) ReactivateSelf(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.UserLogin{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.deactivationService.ReactivateSelf(ctx, &user_dto.UserLogin{Phone: req.Phone, Password: req.Password})
+	return &common_po.CommonResp{}, err
+}
@@ -0,0 +1,55 @@
+package user_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/user_po"
+	"github.com/shop_management/server/assembly/user_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/user_service"
+	"github.com/shop_management/sm_error"
+)
+
+// UserInvitationServer 被邀请人查看, 接受和拒绝邀请
+type UserInvitationServer struct {
+	invitationService service.UserInvitationService
+}
+
+func NewUserInvitationServer() *UserInvitationServer {
+	return &UserInvitationServer{
+		invitationService: user_service.NewUserInvitationServiceImpl(),
+	}
+}
+
+func (u *UserInvitationServer) Detail(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.InvitationTokenReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	invitation, err := u.invitationService.Detail(ctx, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	return user_assembly.ConvertInvitationDtoToPo(invitation), nil
+}
+
+func (u *UserInvitationServer) Accept(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.InvitationTokenReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.invitationService.Accept(ctx, req.Token)
+	return &common_po.CommonResp{}, err
+}
+
+func (u *UserInvitationServer) Decline(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.InvitationTokenReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.invitationService.Decline(ctx, req.Token)
+	return &common_po.CommonResp{}, err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/shop_management/dto/user_dto"
 	"github.com/shop_management/po/common_po"
 	"github.com/shop_management/po/user_po"
+	"github.com/shop_management/server/assembly/user_assembly"
 	"github.com/shop_management/service"
 	"github.com/shop_management/service/user_service"
 	"github.com/shop_management/sm_error"
@@ -14,12 +15,14 @@ import (
 )
 
 type UserTeamServer struct {
-	userTeamService service.UserTeamService
+	userTeamService   service.UserTeamService
+	invitationService service.UserInvitationService
 }
 
 func NewUserTeamServer() *UserTeamServer {
 	return &UserTeamServer{
-		userTeamService: user_service.NewUserTeamServiceImpl(),
+		userTeamService:   user_service.NewUserTeamServiceImpl(),
+		invitationService: user_service.NewUserInvitationServiceImpl(),
 	}
 }
 
@@ -46,6 +49,7 @@ func (u *UserTeamServer) SubUserList(ctx *gin.Context) (interface{}, error) {
 			Id:        user.Id,
 			UserId:    user.UserId,
 			SubUserId: user.SubUserId,
+			Role:      user.Role,
 			Name:      user.Name,
 			Phone:     user.Phone,
 		})
@@ -67,7 +71,7 @@ func (u *UserTeamServer) AddSubUser(ctx *gin.Context) (interface{}, error) {
 	if err != nil {
 		return nil, sm_error.NewHttpError(error_code.ReqParamError)
 	}
-	err = u.userTeamService.AddSubUser(ctx, &user_dto.AddSubUserReq{Phone: req.Phone})
+	err = u.userTeamService.AddSubUser(ctx, &user_dto.AddSubUserReq{Phone: req.Phone, Role: req.Role})
 	if err != nil {
 		return nil, err
 	}
@@ -86,3 +90,160 @@ func (u *UserTeamServer) DelSubUser(ctx *gin.Context) (interface{}, error) {
 	}
 	return &common_po.CommonResp{}, nil
 }
+
+func (u *UserTeamServer) AssignRole(ctx *gin.Context) (interface{}, error) {
+	req := user_po.AssignRoleReq{}
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userTeamService.AssignRole(ctx, &user_dto.AssignRoleReq{Id: req.Id, Role: req.Role})
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (u *UserTeamServer) ForceLogout(ctx *gin.Context) (interface{}, error) {
+	req := user_po.ForceLogoutReq{}
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userTeamService.ForceLogout(ctx, &user_dto.ForceLogoutReq{Id: req.Id})
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (u *UserTeamServer) LockoutList(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &user_po.LoginLockoutListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	r, err := u.userTeamService.LockoutList(ctx, &user_dto.LoginLockoutListReq{
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		UserID: req.UserId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*user_po.LoginLockout, 0, len(r.List))
+	for _, lockout := range r.List {
+		list = append(list, user_assembly.ConvertLoginLockoutDtoToPo(lockout))
+	}
+	return &user_po.LoginLockoutListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
+}
+
+func (u *UserTeamServer) GrantList(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.GrantListReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	grants, err := u.userTeamService.GrantList(ctx, &user_dto.GrantListReq{Id: req.Id})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*user_po.UserGrant, 0, len(grants))
+	for _, grant := range grants {
+		list = append(list, user_assembly.ConvertGrantDtoToPo(grant))
+	}
+	return &user_po.GrantListResp{List: list}, nil
+}
+
+func (u *UserTeamServer) AddGrant(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.AddGrantReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	grant, err := u.userTeamService.AddGrant(ctx, &user_dto.AddGrantReq{
+		Id:           req.Id,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceId,
+		Action:       req.Action,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user_assembly.ConvertGrantDtoToPo(grant), nil
+}
+
+func (u *UserTeamServer) DelGrant(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.DelGrantReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.userTeamService.DelGrant(ctx, &user_dto.DelGrantReq{Id: req.Id})
+	if err != nil {
+		return nil, err
+	}
+	return &common_po.CommonResp{}, nil
+}
+
+func (u *UserTeamServer) Invite(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.InviteReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	invitation, err := u.invitationService.Invite(ctx, user_assembly.ConvertIRPoToDto(req))
+	if err != nil {
+		return nil, err
+	}
+	return user_assembly.ConvertInvitationDtoToPo(invitation), nil
+}
+
+func (u *UserTeamServer) InvitationList(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &user_po.InvitationListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	r, err := u.invitationService.List(ctx, &user_dto.InvitationListReq{
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		Status: req.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*user_po.Invitation, 0, len(r.List))
+	for _, invitation := range r.List {
+		list = append(list, user_assembly.ConvertInvitationDtoToPo(invitation))
+	}
+	return &user_po.InvitationListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
+}
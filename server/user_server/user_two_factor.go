@@ -0,0 +1,65 @@
+package user_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/user_po"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/user_service"
+	"github.com/shop_management/sm_error"
+)
+
+type UserTwoFactorServer struct {
+	twoFactorService service.UserTwoFactorService
+}
+
+func NewUserTwoFactorServer() *UserTwoFactorServer {
+	return &UserTwoFactorServer{
+		twoFactorService: user_service.NewUserTwoFactorServiceImpl(),
+	}
+}
+
+func (u *UserTwoFactorServer) Enroll(ctx *gin.Context) (interface{}, error) {
+	enrollment, err := u.twoFactorService.Enroll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &user_po.TwoFactorEnrollment{Secret: enrollment.Secret, URI: enrollment.URI}, nil
+}
+
+func (u *UserTwoFactorServer) Activate(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.TwoFactorCodeReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	codes, err := u.twoFactorService.Activate(ctx, &user_dto.TwoFactorCodeReq{Code: req.Code})
+	if err != nil {
+		return nil, err
+	}
+	return &user_po.RecoveryCodesResp{RecoveryCodes: codes}, nil
+}
+
+func (u *UserTwoFactorServer) Disable(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.TwoFactorCodeReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	err = u.twoFactorService.Disable(ctx, &user_dto.TwoFactorCodeReq{Code: req.Code})
+	return &common_po.CommonResp{}, err
+}
+
+func (u *UserTwoFactorServer) RegenerateRecoveryCodes(ctx *gin.Context) (interface{}, error) {
+	req := &user_po.TwoFactorCodeReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	codes, err := u.twoFactorService.RegenerateRecoveryCodes(ctx, &user_dto.TwoFactorCodeReq{Code: req.Code})
+	if err != nil {
+		return nil, err
+	}
+	return &user_po.RecoveryCodesResp{RecoveryCodes: codes}, nil
+}
@@ -0,0 +1,185 @@
+package wallet_server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/dto/wallet_dto"
+	"github.com/shop_management/po/common_po"
+	"github.com/shop_management/po/wallet_po"
+	"github.com/shop_management/server/assembly/wallet_assembly"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/wallet_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+)
+
+// defaultTrendDays 未指定天数时的消费趋势范围
+const defaultTrendDays = 30
+
+type WalletServer struct {
+	walletService service.WalletService
+}
+
+func NewWalletServer() *WalletServer {
+	return &WalletServer{
+		walletService: wallet_service.NewWalletServiceImpl(),
+	}
+}
+
+func (w *WalletServer) Detail(ctx *gin.Context) (interface{}, error) {
+	req := &wallet_po.WalletOwner{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	owner := wallet_assembly.ConvertOwnerPoToDto(req)
+	wallet, err := w.walletService.Detail(ctx, &owner)
+	if err != nil {
+		return nil, err
+	}
+	return wallet_assembly.ConvertWalletDtoToPo(wallet), nil
+}
+
+func (w *WalletServer) EntryList(ctx *gin.Context) (interface{}, error) {
+	pager := &common_po.Pager{}
+	err := ctx.ShouldBindQuery(pager)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ReqParamError)
+	}
+	req := &wallet_po.WalletEntryListReq{}
+	err = ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	r, err := w.walletService.EntryList(ctx, &wallet_dto.WalletEntryListReq{
+		WalletOwner: wallet_assembly.ConvertOwnerPoToDto(&req.WalletOwner),
+		Pager: &common_dto.Pager{
+			Page:     pager.Page,
+			PageSize: pager.PageSize,
+		},
+		Type: req.Type,
+	})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*wallet_po.WalletEntry, 0, len(r.List))
+	for _, entry := range r.List {
+		list = append(list, wallet_assembly.ConvertEntryDtoToPo(entry))
+	}
+	return &wallet_po.WalletEntryListResp{
+		Pager: &common_po.Pager{
+			Page:      r.Pager.Page,
+			PageSize:  r.Pager.PageSize,
+			TotalRows: r.Pager.TotalRows,
+		},
+		List: list,
+	}, nil
+}
+
+func (w *WalletServer) TopUp(ctx *gin.Context) (interface{}, error) {
+	req := &wallet_po.AddWalletEntryReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	entry, err := w.walletService.TopUp(ctx, convertAddEntryReq(req))
+	if err != nil {
+		return nil, err
+	}
+	return wallet_assembly.ConvertEntryDtoToPo(entry), nil
+}
+
+func (w *WalletServer) Deduct(ctx *gin.Context) (interface{}, error) {
+	req := &wallet_po.AddWalletEntryReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	entry, err := w.walletService.Deduct(ctx, convertAddEntryReq(req))
+	if err != nil {
+		return nil, err
+	}
+	return wallet_assembly.ConvertEntryDtoToPo(entry), nil
+}
+
+func (w *WalletServer) Refund(ctx *gin.Context) (interface{}, error) {
+	req := &wallet_po.RefundReq{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	entry, err := w.walletService.Refund(ctx, &wallet_dto.RefundReq{
+		EntryID: req.EntryId,
+		Amount:  req.Amount,
+		Remark:  req.Remark,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wallet_assembly.ConvertEntryDtoToPo(entry), nil
+}
+
+func (w *WalletServer) Reconcile(ctx *gin.Context) (interface{}, error) {
+	req := &wallet_po.WalletOwner{}
+	err := ctx.ShouldBindJSON(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	owner := wallet_assembly.ConvertOwnerPoToDto(req)
+	wallet, err := w.walletService.Reconcile(ctx, &owner)
+	if err != nil {
+		return nil, err
+	}
+	return wallet_assembly.ConvertWalletDtoToPo(wallet), nil
+}
+
+func (w *WalletServer) GetStat(ctx *gin.Context) (interface{}, error) {
+	req := &wallet_po.WalletOwner{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	owner := wallet_assembly.ConvertOwnerPoToDto(req)
+	stat, err := w.walletService.Stat(ctx, &owner)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet_po.GetStatResp{
+		TotalUsage:            stat.TotalUsage,
+		TotalUsageCurrency:    stat.Currency,
+		LastWeekUsage:         stat.LastWeekUsage,
+		LastWeekUsageCurrency: stat.Currency,
+	}, nil
+}
+
+func (w *WalletServer) GetTrend(ctx *gin.Context) (interface{}, error) {
+	req := &wallet_po.GetTrendReq{}
+	err := ctx.ShouldBindQuery(req)
+	if err != nil {
+		return nil, sm_error.NewParamHttpError(err)
+	}
+	if req.Days == 0 {
+		req.Days = defaultTrendDays
+	}
+	items, err := w.walletService.Trend(ctx, &wallet_dto.WalletTrendReq{
+		WalletOwner: wallet_assembly.ConvertOwnerPoToDto(&req.WalletOwner),
+		Days:        req.Days,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &wallet_po.GetTrendResp{Data: make([]*wallet_po.TrendItem, 0, len(items))}
+	for _, item := range items {
+		resp.Data = append(resp.Data, &wallet_po.TrendItem{Day: item.Day, TotalCount: item.Usage})
+	}
+	return resp, nil
+}
+
+func convertAddEntryReq(req *wallet_po.AddWalletEntryReq) *wallet_dto.AddWalletEntryReq {
+	return &wallet_dto.AddWalletEntryReq{
+		WalletOwner: wallet_assembly.ConvertOwnerPoToDto(&req.WalletOwner),
+		Amount:      req.Amount,
+		Reference:   req.Reference,
+		Remark:      req.Remark,
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/admin_dto"
+	"github.com/shop_management/dto/audit_dto"
+)
+
+type AdminService interface {
+	CheckPlatformAdmin(ctx *gin.Context) error
+	UserList(ctx *gin.Context, req *admin_dto.UserListReq) (*admin_dto.UserListResp, error)
+	UserDetail(ctx *gin.Context, userId string) (*admin_dto.UserDetail, error)
+	UserActivity(ctx *gin.Context, req *admin_dto.UserActivityReq) (*audit_dto.AuditListResp, error)
+	DisableUser(ctx *gin.Context, req *admin_dto.DisableUserReq) error
+	EnableUser(ctx *gin.Context, req *admin_dto.EnableUserReq) error
+	Impersonate(ctx *gin.Context, req *admin_dto.ImpersonateReq) error
+	AuditList(ctx *gin.Context, req *audit_dto.AuditListReq) (*audit_dto.AuditListResp, error)
+}
@@ -0,0 +1,325 @@
+package admin_service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/admin_dto"
+	"github.com/shop_management/dto/audit_dto"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/redis/user_redis"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/api_key_repo"
+	"github.com/shop_management/repository/audit_repo"
+	"github.com/shop_management/repository/user_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/audit_service"
+	"github.com/shop_management/service/auth_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+// impersonateExpireTime 模拟登录会话的有效期(秒), 远短于正常登录
+const impersonateExpireTime = 3600
+
+const auditEntityUser = "user"
+
+type adminServiceImpl struct {
+	userRepo     repository.UserRepo
+	userTeamRepo repository.UserTeamRepo
+	apiKeyRepo   repository.ApiKeyRepo
+	auditRepo    repository.AuditRepo
+	auditService service.AuditService
+	authService  service.AuthService
+}
+
+func NewAdminServiceImpl() service.AdminService {
+	return &adminServiceImpl{
+		userRepo:     user_repo.NewUserRepoImpl(),
+		userTeamRepo: user_repo.NewUserTeamRepoImpl(),
+		apiKeyRepo:   api_key_repo.NewApiKeyRepoImpl(),
+		auditRepo:    audit_repo.NewAuditRepoImpl(),
+		auditService: audit_service.NewAuditServiceImpl(),
+		authService:  auth_service.NewAuthServiceImpl(),
+	}
+}
+
+// CheckPlatformAdmin 当前用户是否为平台管理员, 模拟登录的会话不视为管理员
+func (a *adminServiceImpl) CheckPlatformAdmin(ctx *gin.Context) error {
+	if ctx.GetString(vars.ImpersonatorIdMetadataName) != "" {
+		return sm_error.NewHttpError(error_code.ImpersonationNotAllowed)
+	}
+	user, err := a.userRepo.GetById(ctx, util.GetDBFromContext(ctx), util.GetUserIdByCookie(ctx))
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.PlatformAdmin || user.IsDisabled() || user.IsDeactivated() {
+		return sm_error.NewHttpError(error_code.AdminNoPermission)
+	}
+	return nil
+}
+
+// UserList 按名称或手机号查询全部用户, 包括已停用和已禁用的账号
+func (a *adminServiceImpl) UserList(ctx *gin.Context, req *admin_dto.UserListReq) (*admin_dto.UserListResp, error) {
+	db := util.GetDBFromContext(ctx)
+	list, err := a.userRepo.List(ctx, db, &user_dto.UserListReq{
+		Pager:   req.Pager,
+		Keyword: req.Keyword,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = a.audit(ctx, db, util.AuditActionSearch, "", map[string]interface{}{
+		"keyword": req.Keyword,
+		"page":    req.Pager.Page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &admin_dto.UserListResp{
+		Pager: req.Pager,
+		List:  list,
+	}, nil
+}
+
+// UserDetail 用户资料, 所在团队和当前有效的会话
+func (a *adminServiceImpl) UserDetail(ctx *gin.Context, userId string) (*admin_dto.UserDetail, error) {
+	db := util.GetDBFromContext(ctx)
+	user, err := a.getUser(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	detail := &admin_dto.UserDetail{User: user}
+	// 停用的账号已移出团队, 团队信息记录在停用字段中
+	if !user.IsDeactivated() {
+		detail.Team, err = a.userTeam(ctx, db, user)
+		if err != nil {
+			return nil, err
+		}
+	}
+	detail.Sessions, err = user_redis.ListSession(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	err = a.audit(ctx, db, util.AuditActionView, user.Id, map[string]interface{}{"section": "detail"})
+	if err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
+
+func (a *adminServiceImpl) userTeam(ctx *gin.Context, db *gorm.DB, user *user_dto.User) (*admin_dto.UserTeam, error) {
+	teamRole, err := a.authService.GetTeamRole(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	team := &admin_dto.UserTeam{
+		OwnerId:   teamRole.OwnerId,
+		OwnerName: user.Name,
+		Role:      teamRole.Role,
+	}
+	if !teamRole.IsOwner() {
+		owner, err := a.userRepo.GetById(ctx, db, teamRole.OwnerId)
+		if err != nil {
+			return nil, err
+		}
+		if owner != nil {
+			team.OwnerName = owner.Name
+		}
+	}
+	team.MemberCount, err = a.userTeamRepo.CountMembers(ctx, db, teamRole.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// UserActivity 用户在所有团队中的操作记录
+func (a *adminServiceImpl) UserActivity(ctx *gin.Context, req *admin_dto.UserActivityReq) (*audit_dto.AuditListResp, error) {
+	db := util.GetDBFromContext(ctx)
+	user, err := a.getUser(ctx, db, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := a.auditService.List(ctx, &audit_dto.AuditListReq{
+		Pager:    req.Pager,
+		ActorID:  user.Id,
+		AllTeams: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = a.audit(ctx, db, util.AuditActionView, user.Id, map[string]interface{}{
+		"section": "activity",
+		"page":    req.Pager.Page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DisableUser 禁用账号: 注销所有会话并作废 API Key, 团队关系保留, 解除禁用后即可重新登录
+func (a *adminServiceImpl) DisableUser(ctx *gin.Context, req *admin_dto.DisableUserReq) error {
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	user, err := a.getTargetUser(ctx, tx, req.UserId)
+	if err != nil {
+		return err
+	}
+	ok, err := a.userRepo.SetDisabled(ctx, tx, user.Id, true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.UserDisabled)
+		return err
+	}
+	err = a.apiKeyRepo.RevokeByUser(ctx, tx, user.Id)
+	if err != nil {
+		return err
+	}
+	err = a.audit(ctx, tx, util.AuditActionDisable, user.Id, map[string]interface{}{"reason": req.Reason})
+	if err != nil {
+		return err
+	}
+	err = user_redis.ClearSession(ctx, user.Id)
+	return err
+}
+
+func (a *adminServiceImpl) EnableUser(ctx *gin.Context, req *admin_dto.EnableUserReq) error {
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	user, err := a.getUser(ctx, tx, req.UserId)
+	if err != nil {
+		return err
+	}
+	_, err = a.userRepo.SetDisabled(ctx, tx, user.Id, false)
+	if err != nil {
+		return err
+	}
+	err = a.audit(ctx, tx, util.AuditActionEnable, user.Id, nil)
+	return err
+}
+
+// Impersonate 以用户身份登录以便排查问题. 会话有效期较短, 写入 cookie 后当前浏览器的管理员会话被替换,
+// 会话中的操作在审计记录中同时记录管理员id, 且不能修改账号与密钥
+func (a *adminServiceImpl) Impersonate(ctx *gin.Context, req *admin_dto.ImpersonateReq) error {
+	db := util.GetDBFromContext(ctx)
+	user, err := a.getTargetUser(ctx, db, req.UserId)
+	if err != nil {
+		return err
+	}
+	if user.IsDeactivated() || user.IsDisabled() {
+		return sm_error.NewHttpError(error_code.AdminTargetNotAllowed)
+	}
+	err = a.audit(ctx, db, util.AuditActionImpersonate, user.Id, nil)
+	if err != nil {
+		return err
+	}
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = user_redis.SetSession(ctx, token, &user_dto.Session{
+		UserID:         user.Id,
+		UserAgent:      ctx.Request.UserAgent(),
+		Ip:             ctx.ClientIP(),
+		CreateTime:     now,
+		ExpireTime:     now.Add(impersonateExpireTime * time.Second),
+		ImpersonatorID: util.GetUserIdByCookie(ctx),
+	})
+	if err != nil {
+		return err
+	}
+	ctx.SetCookie("token", token, impersonateExpireTime, "/", "", false, false)
+	ctx.SetCookie("user_id", user.Id, impersonateExpireTime, "/", "", false, false)
+	return nil
+}
+
+// AuditList 查询全部团队的审计记录, 包括平台管理员的操作
+func (a *adminServiceImpl) AuditList(ctx *gin.Context, req *audit_dto.AuditListReq) (*audit_dto.AuditListResp, error) {
+	req.AllTeams = true
+	resp, err := a.auditService.List(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	err = a.audit(ctx, util.GetDBFromContext(ctx), util.AuditActionSearch, "", map[string]interface{}{
+		"entity_type": "audit_log",
+		"actor_id":    req.ActorID,
+		"entity_id":   req.EntityID,
+		"page":        req.Pager.Page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *adminServiceImpl) getUser(ctx *gin.Context, db *gorm.DB, userId string) (*user_dto.User, error) {
+	user, err := a.userRepo.GetById(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, sm_error.NewHttpError(error_code.UserNoExists)
+	}
+	return user, nil
+}
+
+// getTargetUser 禁用和模拟登录不能作用于平台管理员和自己
+func (a *adminServiceImpl) getTargetUser(ctx *gin.Context, db *gorm.DB, userId string) (*user_dto.User, error) {
+	user, err := a.getUser(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user.PlatformAdmin || user.Id == util.GetUserIdByCookie(ctx) {
+		return nil, sm_error.NewHttpError(error_code.AdminTargetNotAllowed)
+	}
+	return user, nil
+}
+
+// audit 平台管理员的操作不属于任何团队, 团队所有者查询审计记录时不可见
+func (a *adminServiceImpl) audit(ctx *gin.Context, db *gorm.DB, action string, userId string, data map[string]interface{}) error {
+	after := ""
+	if data != nil {
+		after = util.MarshalToStringNoErr(data)
+	}
+	return a.auditRepo.Add(ctx, db, &audit_dto.AuditLog{
+		ActorID:    util.GetUserIdByCookie(ctx),
+		Action:     action,
+		EntityType: auditEntityUser,
+		EntityID:   userId,
+		AfterData:  after,
+		Ip:         ctx.ClientIP(),
+		RequestID:  ctx.GetString(vars.RequestIdMetadataName),
+	})
+}
+
+func generateToken() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		vars.Log.Errorf("adminServiceImpl.generateToken error:%v", err)
+		return "", sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/api_key_dto"
+)
+
+type ApiKeyService interface {
+	Create(ctx *gin.Context, req *api_key_dto.CreateApiKeyReq) (*api_key_dto.ApiKey, error)
+	List(ctx *gin.Context) ([]*api_key_dto.ApiKey, error)
+	Rotate(ctx *gin.Context, id string) (*api_key_dto.ApiKey, error)
+	Revoke(ctx *gin.Context, id string) error
+	Authenticate(ctx *gin.Context, req *api_key_dto.SignedRequest) (*api_key_dto.ApiKey, error)
+}
@@ -0,0 +1,213 @@
+package api_key_service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/api_key_dto"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/redis/api_key_redis"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/api_key_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampWindow 请求时间戳与服务器时间允许的偏差, nonce 保留两倍时长
+const timestampWindow = 5 * time.Minute
+
+// scopeResources 可授权给 API Key 的资源, 团队管理和账号相关接口只允许登录后操作
+var scopeResources = []string{
+	user_dto.ResourceProduct,
+	user_dto.ResourceOrder,
+	user_dto.ResourceFulfillment,
+	user_dto.ResourcePromotion,
+	user_dto.ResourcePos,
+	user_dto.ResourceQuotation,
+	user_dto.ResourceTax,
+	user_dto.ResourceCurrency,
+	user_dto.ResourceReport,
+	user_dto.ResourceFile,
+}
+
+type apiKeyServiceImpl struct {
+	apiKeyRepo repository.ApiKeyRepo
+}
+
+func NewApiKeyServiceImpl() service.ApiKeyService {
+	return &apiKeyServiceImpl{
+		apiKeyRepo: api_key_repo.NewApiKeyRepoImpl(),
+	}
+}
+
+func (a *apiKeyServiceImpl) Create(ctx *gin.Context, req *api_key_dto.CreateApiKeyReq) (*api_key_dto.ApiKey, error) {
+	scopes, err := checkScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	accessKey, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	apiKey := &api_key_dto.ApiKey{
+		UserID:    util.GetUserIdByCookie(ctx),
+		Name:      strings.TrimSpace(req.Name),
+		AccessKey: "ak_" + accessKey,
+		Scopes:    scopes,
+		Status:    api_key_dto.ApiKeyStatusActive,
+	}
+	apiKey.Secret, apiKey.SecretHash, err = newSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = a.apiKeyRepo.Add(ctx, util.GetDBFromContext(ctx), apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+func (a *apiKeyServiceImpl) List(ctx *gin.Context) ([]*api_key_dto.ApiKey, error) {
+	return a.apiKeyRepo.ListByUser(ctx, util.GetDBFromContext(ctx), util.GetUserIdByCookie(ctx))
+}
+
+// Rotate 生成新密钥, 旧密钥立即失效, AccessKey 与权限范围不变
+func (a *apiKeyServiceImpl) Rotate(ctx *gin.Context, id string) (*api_key_dto.ApiKey, error) {
+	db := util.GetDBFromContext(ctx)
+	apiKey, err := a.getOwn(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	apiKey.Secret, apiKey.SecretHash, err = newSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = a.apiKeyRepo.UpdateSecret(ctx, db, apiKey.ID, apiKey.SecretHash)
+	if err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+func (a *apiKeyServiceImpl) Revoke(ctx *gin.Context, id string) error {
+	apiKey, err := a.getOwn(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.apiKeyRepo.UpdateStatus(ctx, util.GetDBFromContext(ctx), apiKey.ID, api_key_dto.ApiKeyStatusRevoked)
+}
+
+// Authenticate 请求携带的密钥与保存的摘要一致, 且时间戳与签名校验通过后才记录 nonce, 避免伪造请求占用合法的 nonce
+func (a *apiKeyServiceImpl) Authenticate(ctx *gin.Context, req *api_key_dto.SignedRequest) (*api_key_dto.ApiKey, error) {
+	db := util.GetDBFromContext(ctx)
+	apiKey, err := a.apiKeyRepo.GetByAccessKey(ctx, db, req.AccessKey)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil || apiKey.Status != api_key_dto.ApiKeyStatusActive {
+		return nil, sm_error.NewHttpError(error_code.ApiKeyNoExists)
+	}
+	timestamp, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ApiKeyRequestExpired)
+	}
+	now := time.Now()
+	if diff := now.Sub(time.Unix(timestamp, 0)); diff > timestampWindow || diff < -timestampWindow {
+		return nil, sm_error.NewHttpError(error_code.ApiKeyRequestExpired)
+	}
+	if req.Nonce == "" || len(req.Nonce) > 64 {
+		return nil, sm_error.NewHttpError(error_code.ApiKeySignatureInvalid)
+	}
+	if req.Secret == "" || subtle.ConstantTimeCompare([]byte(hashSecret(req.Secret)), []byte(apiKey.SecretHash)) != 1 {
+		return nil, sm_error.NewHttpError(error_code.ApiKeySignatureInvalid)
+	}
+	signature, err := hex.DecodeString(req.Signature)
+	if err != nil {
+		return nil, sm_error.NewHttpError(error_code.ApiKeySignatureInvalid)
+	}
+	mac := hmac.New(sha256.New, []byte(req.Secret))
+	mac.Write([]byte(req.StringToSign()))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, sm_error.NewHttpError(error_code.ApiKeySignatureInvalid)
+	}
+	err = api_key_redis.UseNonce(ctx, apiKey.AccessKey, req.Nonce, 2*timestampWindow)
+	if err != nil {
+		return nil, err
+	}
+	_ = a.apiKeyRepo.UpdateLastUsed(ctx, db, apiKey.ID, now)
+	return apiKey, nil
+}
+
+func (a *apiKeyServiceImpl) getOwn(ctx *gin.Context, id string) (*api_key_dto.ApiKey, error) {
+	apiKey, err := a.apiKeyRepo.GetById(ctx, util.GetDBFromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil || apiKey.UserID != util.GetUserIdByCookie(ctx) || apiKey.Status != api_key_dto.ApiKeyStatusActive {
+		return nil, sm_error.NewHttpError(error_code.ApiKeyNoExists)
+	}
+	return apiKey, nil
+}
+
+func checkScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, sm_error.NewHttpError(error_code.ApiKeyScopeInvalid)
+	}
+	result := make([]string, 0, len(scopes))
+	seen := make(map[string]bool)
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		resource, action, ok := strings.Cut(scope, ":")
+		if !ok || (action != user_dto.ActionRead && action != user_dto.ActionWrite) || !isScopeResource(resource) {
+			return nil, sm_error.NewHttpError(error_code.ApiKeyScopeInvalid, "权限范围不正确: "+scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
+
+func isScopeResource(resource string) bool {
+	for _, r := range scopeResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// newSecret 256位随机密钥, 只在创建和轮换时返回一次, 数据库只保存摘要.
+// 密钥本身足够随机, 摘要不需要加盐或慢哈希
+func newSecret() (secret string, hash string, err error) {
+	secret, err = randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+	secret = "sk_" + secret
+	return secret, hashSecret(secret), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		vars.Log.Errorf("apiKeyServiceImpl.randomHex error:%v", err)
+		return "", sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/audit_dto"
+)
+
+type AuditService interface {
+	List(ctx *gin.Context, req *audit_dto.AuditListReq) (*audit_dto.AuditListResp, error)
+}
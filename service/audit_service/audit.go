@@ -0,0 +1,65 @@
+package audit_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/audit_dto"
+	"github.com/shop_management/dto/common_dto"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/audit_repo"
+	"github.com/shop_management/repository/user_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/util"
+)
+
+type auditServiceImpl struct {
+	auditRepo repository.AuditRepo
+	userRepo  repository.UserRepo
+}
+
+func NewAuditServiceImpl() service.AuditService {
+	return &auditServiceImpl{
+		auditRepo: audit_repo.NewAuditRepoImpl(),
+		userRepo:  user_repo.NewUserRepoImpl(),
+	}
+}
+
+// List 按团队隔离, 只能查询本团队的变更记录, 并补充操作人名称. 平台管理员可查询全部团队
+func (a *auditServiceImpl) List(ctx *gin.Context, req *audit_dto.AuditListReq) (*audit_dto.AuditListResp, error) {
+	db := util.GetDBFromContext(ctx)
+	query := db
+	if req.AllTeams {
+		query = util.WithoutTeamScope(db)
+	}
+	resp, err := a.auditRepo.List(ctx, query, req)
+	if err != nil {
+		return nil, err
+	}
+	actorIds := make([]string, 0)
+	for _, log := range resp.List {
+		if log.ActorID != "" {
+			actorIds = append(actorIds, log.ActorID)
+		}
+	}
+	if len(actorIds) == 0 {
+		return resp, nil
+	}
+	users, err := a.userRepo.List(ctx, db, &user_dto.UserListReq{
+		Pager: &common_dto.Pager{
+			Page:     1,
+			PageSize: int64(len(actorIds)),
+		},
+		UserIds: actorIds,
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(users))
+	for _, user := range users {
+		names[user.Id] = user.Name
+	}
+	for _, log := range resp.List {
+		log.ActorName = names[log.ActorID]
+	}
+	return resp, nil
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+)
+
+type AuthService interface {
+	GetTeamRole(ctx *gin.Context, userId string) (*user_dto.TeamRole, error)
+	CheckPermission(ctx *gin.Context, resource string, action string) error
+	CanViewCost(ctx *gin.Context) (bool, error)
+	WarehouseScope(ctx *gin.Context, action string) (warehouses []string, restricted bool, err error)
+	CheckWarehouse(ctx *gin.Context, warehouse string, action string) error
+	ValidRole(role string) bool
+	ValidGrant(grant *user_dto.UserGrant) bool
+}
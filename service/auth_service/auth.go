@@ -0,0 +1,250 @@
+package auth_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/api_key_dto"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/user_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+)
+
+type authServiceImpl struct {
+	userTeamRepo  repository.UserTeamRepo
+	userGrantRepo repository.UserGrantRepo
+}
+
+func NewAuthServiceImpl() service.AuthService {
+	return &authServiceImpl{
+		userTeamRepo:  user_repo.NewUserTeamRepoImpl(),
+		userGrantRepo: user_repo.NewUserGrantRepoImpl(),
+	}
+}
+
+var (
+	readOnly  = []string{user_dto.ActionRead}
+	readWrite = []string{user_dto.ActionRead, user_dto.ActionWrite}
+	// readWriteCost 读写并可查看成本价
+	readWriteCost = []string{user_dto.ActionRead, user_dto.ActionWrite, user_dto.ActionViewCost}
+	// readWriteCredit 读写并可给钱包充值和退款
+	readWriteCredit = []string{user_dto.ActionRead, user_dto.ActionWrite, user_dto.ActionCredit}
+)
+
+// rolePermissions 各角色在每个资源上允许的操作, 团队所有者拥有全部权限不在此列出
+var rolePermissions = map[string]map[string][]string{
+	user_dto.RoleManager: {
+		user_dto.ResourceProduct:     readWriteCost,
+		user_dto.ResourceOrder:       readWrite,
+		user_dto.ResourceFulfillment: readWrite,
+		user_dto.ResourcePromotion:   readWrite,
+		user_dto.ResourcePos:         readWrite,
+		user_dto.ResourceQuotation:   readWrite,
+		user_dto.ResourceTax:         readWrite,
+		user_dto.ResourceCurrency:    readWrite,
+		user_dto.ResourceReport:      readOnly,
+		user_dto.ResourceFile:        readWrite,
+		user_dto.ResourceTeam:        readOnly,
+		user_dto.ResourceWallet:      readWriteCredit,
+	},
+	user_dto.RoleWarehouseClerk: {
+		user_dto.ResourceProduct:     readWrite,
+		user_dto.ResourceOrder:       readOnly,
+		user_dto.ResourceFulfillment: readWrite,
+		user_dto.ResourceTax:         readOnly,
+		user_dto.ResourceCurrency:    readOnly,
+		user_dto.ResourceReport:      readOnly,
+		user_dto.ResourceFile:        readWrite,
+	},
+	user_dto.RoleSales: {
+		user_dto.ResourceProduct:     readOnly,
+		user_dto.ResourceOrder:       readWrite,
+		user_dto.ResourceFulfillment: readOnly,
+		user_dto.ResourcePromotion:   readOnly,
+		user_dto.ResourcePos:         readWrite,
+		user_dto.ResourceQuotation:   readWrite,
+		user_dto.ResourceTax:         readOnly,
+		user_dto.ResourceCurrency:    readOnly,
+		user_dto.ResourceReport:      readOnly,
+		user_dto.ResourceFile:        readWrite,
+		user_dto.ResourceWallet:      readWrite,
+	},
+	user_dto.RoleReadOnly: {
+		user_dto.ResourceProduct:     readOnly,
+		user_dto.ResourceOrder:       readOnly,
+		user_dto.ResourceFulfillment: readOnly,
+		user_dto.ResourcePromotion:   readOnly,
+		user_dto.ResourcePos:         readOnly,
+		user_dto.ResourceQuotation:   readOnly,
+		user_dto.ResourceTax:         readOnly,
+		user_dto.ResourceCurrency:    readOnly,
+		user_dto.ResourceReport:      readOnly,
+		user_dto.ResourceWallet:      readOnly,
+	},
+}
+
+// GetTeamRole 不是任何团队子账号的用户视为自己团队的所有者,
+// 历史数据中未设置角色的子账号按只读处理
+func (a *authServiceImpl) GetTeamRole(ctx *gin.Context, userId string) (*user_dto.TeamRole, error) {
+	if value, ok := ctx.Get(vars.TeamRoleMetadataName); ok {
+		if teamRole, ok := value.(*user_dto.TeamRole); ok && teamRole.UserId == userId {
+			return teamRole, nil
+		}
+	}
+	member, err := a.userTeamRepo.GetBySubUserId(ctx, util.GetDBFromContext(ctx), userId)
+	if err != nil {
+		return nil, err
+	}
+	teamRole := &user_dto.TeamRole{UserId: userId, OwnerId: userId, Role: user_dto.RoleOwner}
+	if member != nil {
+		teamRole.OwnerId = member.UserId
+		teamRole.Role = member.Role
+		if !a.ValidRole(teamRole.Role) {
+			teamRole.Role = user_dto.RoleReadOnly
+		}
+	}
+	// 只缓存当前登录用户的身份, 查询其他用户时不能覆盖请求所属的团队
+	if userId == util.GetUserIdByCookie(ctx) {
+		ctx.Set(vars.TeamRoleMetadataName, teamRole)
+		ctx.Set(vars.TeamIdMetadataName, teamRole.OwnerId)
+	}
+	return teamRole, nil
+}
+
+func (a *authServiceImpl) CheckPermission(ctx *gin.Context, resource string, action string) error {
+	teamRole, err := a.GetTeamRole(ctx, util.GetUserIdByCookie(ctx))
+	if err != nil {
+		return err
+	}
+	// API Key 请求还需在 Key 的权限范围内
+	if value, ok := ctx.Get(vars.ApiKeyMetadataName); ok {
+		if apiKey, ok := value.(*api_key_dto.ApiKey); ok && !apiKey.HasScope(resource, action) {
+			return sm_error.NewHttpError(error_code.ApiKeyScopeInvalid, "API Key 未授权访问该资源")
+		}
+	}
+	if teamRole.IsOwner() || roleAllows(teamRole.Role, resource, action) {
+		return nil
+	}
+	return sm_error.NewHttpError(error_code.UserNoPermission)
+}
+
+// CanViewCost 所有者和默认拥有该权限的角色可以查看, 其余子账号需要单独授权.
+// API Key 请求还需 Key 的范围允许 product 上的 view_cost 操作, 按 HasScope 的规则即
+// product:view_cost 或 product:write
+func (a *authServiceImpl) CanViewCost(ctx *gin.Context) (bool, error) {
+	userId := util.GetUserIdByCookie(ctx)
+	teamRole, err := a.GetTeamRole(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	if value, ok := ctx.Get(vars.ApiKeyMetadataName); ok {
+		if apiKey, ok := value.(*api_key_dto.ApiKey); ok && !apiKey.HasScope(user_dto.ResourceProduct, user_dto.ActionViewCost) {
+			return false, nil
+		}
+	}
+	if teamRole.IsOwner() || roleAllows(teamRole.Role, user_dto.ResourceProduct, user_dto.ActionViewCost) {
+		return true, nil
+	}
+	grants, err := a.getGrants(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+	for _, grant := range grants {
+		if grant.ResourceType == user_dto.GrantResourceProduct && grant.Action == user_dto.ActionViewCost {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// WarehouseScope 当前用户可以执行该操作的仓库, restricted 为 false 时不限制仓库.
+// 有写授权的仓库同时可读
+func (a *authServiceImpl) WarehouseScope(ctx *gin.Context, action string) (warehouses []string, restricted bool, err error) {
+	userId := util.GetUserIdByCookie(ctx)
+	teamRole, err := a.GetTeamRole(ctx, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	if teamRole.IsOwner() {
+		return nil, false, nil
+	}
+	grants, err := a.getGrants(ctx, userId)
+	if err != nil {
+		return nil, false, err
+	}
+	warehouses = make([]string, 0)
+	for _, grant := range grants {
+		if grant.ResourceType != user_dto.GrantResourceWarehouse {
+			continue
+		}
+		restricted = true
+		if grant.Action == action || grant.Action == user_dto.ActionWrite {
+			warehouses = append(warehouses, grant.ResourceID)
+		}
+	}
+	return warehouses, restricted, nil
+}
+
+func (a *authServiceImpl) CheckWarehouse(ctx *gin.Context, warehouse string, action string) error {
+	warehouses, restricted, err := a.WarehouseScope(ctx, action)
+	if err != nil {
+		return err
+	}
+	if !restricted {
+		return nil
+	}
+	for _, w := range warehouses {
+		if w == warehouse {
+			return nil
+		}
+	}
+	return sm_error.NewHttpError(error_code.WarehouseNoPermission)
+}
+
+// ValidRole 可以分配给子账号的角色, 不包括所有者
+func (a *authServiceImpl) ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// ValidGrant 仓库授权的操作为读或写, 商品授权只有查看成本价
+func (a *authServiceImpl) ValidGrant(grant *user_dto.UserGrant) bool {
+	switch grant.ResourceType {
+	case user_dto.GrantResourceWarehouse:
+		return grant.ResourceID != "" && grant.ResourceID != user_dto.GrantAllResources &&
+			(grant.Action == user_dto.ActionRead || grant.Action == user_dto.ActionWrite)
+	case user_dto.GrantResourceProduct:
+		return grant.ResourceID == user_dto.GrantAllResources && grant.Action == user_dto.ActionViewCost
+	}
+	return false
+}
+
+// getGrants 只缓存当前登录用户的授权
+func (a *authServiceImpl) getGrants(ctx *gin.Context, userId string) ([]*user_dto.UserGrant, error) {
+	current := userId == util.GetUserIdByCookie(ctx)
+	if value, ok := ctx.Get(vars.UserGrantMetadataName); ok && current {
+		if grants, ok := value.([]*user_dto.UserGrant); ok {
+			return grants, nil
+		}
+	}
+	grants, err := a.userGrantRepo.ListByUser(ctx, util.GetDBFromContext(ctx), userId)
+	if err != nil {
+		return nil, err
+	}
+	if current {
+		ctx.Set(vars.UserGrantMetadataName, grants)
+	}
+	return grants, nil
+}
+
+func roleAllows(role string, resource string, action string) bool {
+	for _, allowed := range rolePermissions[role][resource] {
+		if allowed == action {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/currency_dto"
+	"github.com/shop_management/util"
+	"time"
+)
+
+type CurrencyService interface {
+	GetBaseCurrency(ctx *gin.Context) (string, error)
+	SaveBaseCurrency(ctx *gin.Context, currency string) error
+	SaveRate(ctx *gin.Context, dto *currency_dto.ExchangeRate) (*currency_dto.ExchangeRate, error)
+	// ImportRates 从 excel 导入汇率, 列依次为: 源币种, 目标币种, 汇率, 生效日期(2006-01-02)
+	ImportRates(ctx *gin.Context, url string) (int, error)
+	ListRates(ctx *gin.Context, req *currency_dto.RateListReq) ([]*currency_dto.ExchangeRate, error)
+	// Convert 按 at 当日有效的汇率换算金额, 币种为空时视为团队本位币
+	Convert(ctx *gin.Context, amount util.Money, from string, to string, at time.Time) (util.Money, error)
+}
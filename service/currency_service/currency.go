@@ -0,0 +1,199 @@
+package currency_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/currency_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/currency_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/file_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var currencyCodeRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
+
+type currencyServiceImpl struct {
+	currencyRepo repository.CurrencyRepo
+	fileService  service.FileServiceInterface
+}
+
+func NewCurrencyServiceImpl() service.CurrencyService {
+	return &currencyServiceImpl{
+		currencyRepo: currency_repo.NewCurrencyRepoImpl(),
+		fileService:  file_service.NewFileService(),
+	}
+}
+
+func (c *currencyServiceImpl) GetBaseCurrency(ctx *gin.Context) (string, error) {
+	setting, err := c.currencyRepo.GetSetting(ctx, util.GetDBFromContext(ctx), util.GetTeamId(ctx))
+	if err != nil {
+		return "", err
+	}
+	return setting.BaseCurrency, nil
+}
+
+func (c *currencyServiceImpl) SaveBaseCurrency(ctx *gin.Context, currency string) error {
+	currency, err := normalizeCode(currency)
+	if err != nil {
+		return err
+	}
+	return c.currencyRepo.SaveSetting(ctx, util.GetDBFromContext(ctx), &currency_dto.CurrencySetting{
+		UserID:       util.GetTeamId(ctx),
+		BaseCurrency: currency,
+	})
+}
+
+func (c *currencyServiceImpl) SaveRate(ctx *gin.Context, dto *currency_dto.ExchangeRate) (*currency_dto.ExchangeRate, error) {
+	err := checkRate(dto)
+	if err != nil {
+		return nil, err
+	}
+	dto.UserID = util.GetTeamId(ctx)
+	dto.Source = currency_dto.RateSourceManual
+	err = c.currencyRepo.SaveRate(ctx, util.GetDBFromContext(ctx), dto)
+	if err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+func (c *currencyServiceImpl) ImportRates(ctx *gin.Context, url string) (int, error) {
+	db := util.GetDBFromContext(ctx)
+	userId := util.GetTeamId(ctx)
+	rates := make([]*currency_dto.ExchangeRate, 0)
+	rowNum := 0
+	err := c.fileService.ParseExcel(ctx, url, func(ctx *gin.Context, rows [][]string) error {
+		for _, row := range rows {
+			rowNum++
+			if len(row) < 4 {
+				return sm_error.NewHttpError(error_code.CurrencyImportInvalid, "第"+strconv.Itoa(rowNum)+"行列数不足")
+			}
+			rate, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+			if err != nil {
+				// 第一行允许是表头
+				if rowNum == 1 {
+					continue
+				}
+				return sm_error.NewHttpError(error_code.CurrencyImportInvalid, "第"+strconv.Itoa(rowNum)+"行汇率不正确")
+			}
+			effectiveDate, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(row[3]), time.Local)
+			if err != nil {
+				return sm_error.NewHttpError(error_code.CurrencyImportInvalid, "第"+strconv.Itoa(rowNum)+"行日期不正确")
+			}
+			dto := &currency_dto.ExchangeRate{
+				UserID:        userId,
+				FromCurrency:  row[0],
+				ToCurrency:    row[1],
+				Rate:          rate,
+				EffectiveDate: effectiveDate,
+				Source:        currency_dto.RateSourceImport,
+			}
+			err = checkRate(dto)
+			if err != nil {
+				return err
+			}
+			rates = append(rates, dto)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(*sm_error.Error); ok {
+			return 0, err
+		}
+		return 0, sm_error.NewHttpError(error_code.CurrencyImportInvalid, err.Error())
+	}
+
+	// 全部校验通过后再写入, 避免导入一半
+	tx := db.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	for _, rate := range rates {
+		err = c.currencyRepo.SaveRate(ctx, tx, rate)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(rates), nil
+}
+
+func (c *currencyServiceImpl) ListRates(ctx *gin.Context, req *currency_dto.RateListReq) ([]*currency_dto.ExchangeRate, error) {
+	req.UserID = util.GetTeamId(ctx)
+	req.FromCurrency = strings.ToUpper(strings.TrimSpace(req.FromCurrency))
+	req.ToCurrency = strings.ToUpper(strings.TrimSpace(req.ToCurrency))
+	return c.currencyRepo.ListRates(ctx, util.GetDBFromContext(ctx), req)
+}
+
+// Convert 优先使用正向汇率, 没有时使用反向汇率的倒数
+func (c *currencyServiceImpl) Convert(ctx *gin.Context, amount util.Money, from string, to string, at time.Time) (util.Money, error) {
+	if from == "" || to == "" {
+		base, err := c.GetBaseCurrency(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if from == "" {
+			from = base
+		}
+		if to == "" {
+			to = base
+		}
+	}
+	if from == to || amount == 0 {
+		return amount, nil
+	}
+	db := util.GetDBFromContext(ctx)
+	userId := util.GetTeamId(ctx)
+	rate, err := c.currencyRepo.GetRate(ctx, db, userId, from, to, at)
+	if err != nil {
+		return 0, err
+	}
+	if rate != nil {
+		return amount.MulRate(rate.Rate), nil
+	}
+	rate, err = c.currencyRepo.GetRate(ctx, db, userId, to, from, at)
+	if err != nil {
+		return 0, err
+	}
+	if rate != nil {
+		return amount.MulRate(1 / rate.Rate), nil
+	}
+	return 0, sm_error.NewHttpError(error_code.CurrencyRateNoExists, from+" -> "+to+" "+at.Format("2006-01-02")+" 未找到汇率")
+}
+
+func checkRate(dto *currency_dto.ExchangeRate) error {
+	from, err := normalizeCode(dto.FromCurrency)
+	if err != nil {
+		return err
+	}
+	to, err := normalizeCode(dto.ToCurrency)
+	if err != nil {
+		return err
+	}
+	if from == to {
+		return sm_error.NewHttpError(error_code.CurrencyRateInvalid, "源币种与目标币种不能相同")
+	}
+	if dto.Rate <= 0 {
+		return sm_error.NewHttpError(error_code.CurrencyRateInvalid)
+	}
+	dto.FromCurrency = from
+	dto.ToCurrency = to
+	return nil
+}
+
+func normalizeCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !currencyCodeRegexp.MatchString(code) {
+		return "", sm_error.NewHttpError(error_code.CurrencyCodeInvalid)
+	}
+	return code, nil
+}
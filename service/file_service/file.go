@@ -3,6 +3,7 @@ package file_service
 import (
 	"github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/gin-gonic/gin"
+	"github.com/shop_management/config"
 	"github.com/shop_management/dto/file_dto"
 	"github.com/shop_management/sm_error"
 	"github.com/shop_management/sm_error/error_code"
@@ -53,19 +54,11 @@ func (f *fileService) UploadFile(ctx *gin.Context, req *file_dto.UploadReq) (str
 	if err != nil {
 		return "", err
 	}
-	provider, err := oss.NewEnvironmentVariableCredentialsProvider()
-	if err != nil {
-		return "", err
-	}
 	defer func() {
 		tempLocalFile.Close()
 		os.Remove(finalFileName)
 	}()
-	client, err := oss.New("oss-cn-shenzhen.aliyuncs.com", os.Getenv("OSS_ACCESS_KEY_ID"), os.Getenv("OSS_ACCESS_KEY_SECRET"), oss.SetCredentialsProvider(&provider))
-	if err != nil {
-		return "", err
-	}
-	bucket, err := client.Bucket("szwkoss")
+	bucket, err := newOssBucket()
 	if err != nil {
 		return "", err
 	}
@@ -74,7 +67,7 @@ func (f *fileService) UploadFile(ctx *gin.Context, req *file_dto.UploadReq) (str
 		return "", err
 	}
 
-	return "https://szwkoss.oss-cn-shenzhen.aliyuncs.com/" + finalFileName, nil
+	return config.Get().Oss.PublicURL(finalFileName), nil
 }
 
 func (f *fileService) UploadRemoteFile(ctx *gin.Context, url string, suffix string) (string, error) {
@@ -86,15 +79,7 @@ func (f *fileService) UploadRemoteFile(ctx *gin.Context, url string, suffix stri
 	}
 	defer resp.Body.Close()
 
-	provider, err := oss.NewEnvironmentVariableCredentialsProvider()
-	if err != nil {
-		return "", err
-	}
-	client, err := oss.New("oss-cn-shenzhen.aliyuncs.com", os.Getenv("OSS_ACCESS_KEY_ID"), os.Getenv("OSS_ACCESS_KEY_SECRET"), oss.SetCredentialsProvider(&provider))
-	if err != nil {
-		return "", err
-	}
-	bucket, err := client.Bucket("szwkoss")
+	bucket, err := newOssBucket()
 	if err != nil {
 		return "", err
 	}
@@ -102,7 +87,7 @@ func (f *fileService) UploadRemoteFile(ctx *gin.Context, url string, suffix stri
 	if err != nil {
 		return "", err
 	}
-	return "https://szwkoss.oss-cn-shenzhen.aliyuncs.com/" + finalFileName, nil
+	return config.Get().Oss.PublicURL(finalFileName), nil
 }
 
 func (f *fileService) UploadLocalFile(ctx *gin.Context, path string, suffix string) (string, error) {
@@ -114,15 +99,7 @@ func (f *fileService) UploadLocalFile(ctx *gin.Context, path string, suffix stri
 
 	defer open.Close()
 
-	provider, err := oss.NewEnvironmentVariableCredentialsProvider()
-	if err != nil {
-		return "", err
-	}
-	client, err := oss.New("oss-cn-shenzhen.aliyuncs.com", os.Getenv("OSS_ACCESS_KEY_ID"), os.Getenv("OSS_ACCESS_KEY_SECRET"), oss.SetCredentialsProvider(&provider))
-	if err != nil {
-		return "", err
-	}
-	bucket, err := client.Bucket("szwkoss")
+	bucket, err := newOssBucket()
 	if err != nil {
 		return "", err
 	}
@@ -130,7 +107,7 @@ func (f *fileService) UploadLocalFile(ctx *gin.Context, path string, suffix stri
 	if err != nil {
 		return "", err
 	}
-	return "https://szwkoss.oss-cn-shenzhen.aliyuncs.com/" + finalFileName, nil
+	return config.Get().Oss.PublicURL(finalFileName), nil
 }
 
 func (f *fileService) ParseExcel(ctx *gin.Context, url string, dealData func(*gin.Context, [][]string) error) error {
@@ -176,3 +153,13 @@ func (f *fileService) ParseExcel(ctx *gin.Context, url string, dealData func(*gi
 	}
 	return nil
 }
+
+// newOssBucket AccessKey 取自配置 oss, 可通过环境变量 OSS_ACCESS_KEY_ID, OSS_ACCESS_KEY_SECRET 覆盖
+func newOssBucket() (*oss.Bucket, error) {
+	cfg := config.Get().Oss
+	client, err := oss.New(cfg.Endpoint, cfg.AccessKeyId, cfg.AccessKeySecret)
+	if err != nil {
+		return nil, err
+	}
+	return client.Bucket(cfg.Bucket)
+}
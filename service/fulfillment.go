@@ -0,0 +1,16 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/fulfillment_dto"
+)
+
+type FulfillmentService interface {
+	GeneratePickList(ctx *gin.Context, orderId string) ([]*fulfillment_dto.PickTask, error)
+	PickTaskList(ctx *gin.Context, req *fulfillment_dto.PickTaskListReq) ([]*fulfillment_dto.PickTask, error)
+	ClaimPickTask(ctx *gin.Context, taskId string) error
+	FinishPickTask(ctx *gin.Context, taskId string) error
+	Pack(ctx *gin.Context, req *fulfillment_dto.PackReq) (*fulfillment_dto.PackRecord, error)
+	Ship(ctx *gin.Context, req *fulfillment_dto.ShipReq) (*fulfillment_dto.Shipment, error)
+	Timeline(ctx *gin.Context, orderId string) (*fulfillment_dto.FulfillmentTimeline, error)
+}
@@ -0,0 +1,420 @@
+package fulfillment_service
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/fulfillment_dto"
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/dto/product_dto"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/fulfillment_repo"
+	"github.com/shop_management/repository/order_repo"
+	"github.com/shop_management/repository/product_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/auth_service"
+	"github.com/shop_management/service/order_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"sort"
+	"strings"
+	"time"
+)
+
+type fulfillmentServiceImpl struct {
+	fulfillmentRepo repository.FulfillmentRepo
+	orderRepo       repository.OrderRepo
+	productRepo     repository.ProductRepo
+	orderService    service.OrderService
+	authService     service.AuthService
+}
+
+func NewFulfillmentServiceImpl() service.FulfillmentService {
+	return &fulfillmentServiceImpl{
+		fulfillmentRepo: fulfillment_repo.NewFulfillmentRepoImpl(),
+		orderRepo:       order_repo.NewOrderRepoImpl(),
+		productRepo:     product_repo.NewProductRepoImpl(),
+		orderService:    order_service.NewOrderServiceImpl(),
+		authService:     auth_service.NewAuthServiceImpl(),
+	}
+}
+
+// GeneratePickList 按仓库和库位(StoragePos)对已确认订单的商品分组生成拣货任务
+func (f *fulfillmentServiceImpl) GeneratePickList(ctx *gin.Context, orderId string) ([]*fulfillment_dto.PickTask, error) {
+	order, err := f.orderService.Detail(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != order_dto.OrderStatusConfirmed {
+		return nil, sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+	}
+	productMap, err := f.getOrderProducts(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	ok, err := f.orderRepo.UpdateStatus(ctx, tx, orderId, order_dto.OrderStatusConfirmed, order_dto.OrderStatusPicking)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+		return nil, err
+	}
+	taskMap := make(map[string]*fulfillment_dto.PickTask)
+	tasks := make([]*fulfillment_dto.PickTask, 0)
+	for _, item := range order.Items {
+		product := productMap[item.ProductID]
+		key := product.Warehouse + "|" + product.StoragePos
+		task, ok := taskMap[key]
+		if !ok {
+			task = &fulfillment_dto.PickTask{
+				OrderID:    orderId,
+				Warehouse:  product.Warehouse,
+				StoragePos: product.StoragePos,
+				Status:     fulfillment_dto.PickTaskStatusPending,
+				Items:      make([]*fulfillment_dto.PickTaskItem, 0),
+			}
+			taskMap[key] = task
+			tasks = append(tasks, task)
+		}
+		task.Items = append(task.Items, &fulfillment_dto.PickTaskItem{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			ProductName: product.Name,
+			StorageCode: product.StorageCode,
+			Quantity:    item.Quantity,
+		})
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Warehouse != tasks[j].Warehouse {
+			return tasks[i].Warehouse < tasks[j].Warehouse
+		}
+		return tasks[i].StoragePos < tasks[j].StoragePos
+	})
+	err = f.fulfillmentRepo.AddPickTasks(ctx, tx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (f *fulfillmentServiceImpl) PickTaskList(ctx *gin.Context, req *fulfillment_dto.PickTaskListReq) ([]*fulfillment_dto.PickTask, error) {
+	if req.OrderID != "" {
+		if _, err := f.orderService.Detail(ctx, req.OrderID); err != nil {
+			return nil, err
+		}
+	} else {
+		// 不指定订单时只能查看自己领取的任务
+		req.AssigneeID = util.GetUserIdByCookie(ctx)
+	}
+	warehouses, restricted, err := f.authService.WarehouseScope(ctx, user_dto.ActionRead)
+	if err != nil {
+		return nil, err
+	}
+	if restricted {
+		req.Warehouses = warehouses
+	}
+	tasks, err := f.fulfillmentRepo.ListPickTask(ctx, util.GetDBFromContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	err = f.fillProductName(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (f *fulfillmentServiceImpl) ClaimPickTask(ctx *gin.Context, taskId string) error {
+	task, err := f.getPickTask(ctx, taskId)
+	if err != nil {
+		return err
+	}
+	if task.Status != fulfillment_dto.PickTaskStatusPending {
+		return sm_error.NewHttpError(error_code.PickTaskClaimed)
+	}
+	err = f.authService.CheckWarehouse(ctx, task.Warehouse, user_dto.ActionWrite)
+	if err != nil {
+		return err
+	}
+	ok, err := f.fulfillmentRepo.ClaimPickTask(ctx, util.GetDBFromContext(ctx), taskId, util.GetUserIdByCookie(ctx), time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.PickTaskClaimed)
+	}
+	return nil
+}
+
+func (f *fulfillmentServiceImpl) FinishPickTask(ctx *gin.Context, taskId string) error {
+	task, err := f.getPickTask(ctx, taskId)
+	if err != nil {
+		return err
+	}
+	if task.Status != fulfillment_dto.PickTaskStatusClaimed || task.AssigneeID != util.GetUserIdByCookie(ctx) {
+		return sm_error.NewHttpError(error_code.PickTaskNotClaimedByMe)
+	}
+	err = f.authService.CheckWarehouse(ctx, task.Warehouse, user_dto.ActionWrite)
+	if err != nil {
+		return err
+	}
+
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	ok, err := f.fulfillmentRepo.FinishPickTask(ctx, tx, taskId, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.PickTaskNotClaimedByMe)
+		return err
+	}
+	tasks, err := f.fulfillmentRepo.ListPickTask(ctx, tx, &fulfillment_dto.PickTaskListReq{OrderID: task.OrderID})
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		if t.Status != fulfillment_dto.PickTaskStatusPicked {
+			return nil
+		}
+	}
+	// 所有拣货任务完成后订单进入待打包
+	_, err = f.orderRepo.UpdateStatus(ctx, tx, task.OrderID, order_dto.OrderStatusPicking, order_dto.OrderStatusPicked)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Pack 每扫描一次库位码代表一件商品, 扫描结果需与订单明细数量完全一致
+func (f *fulfillmentServiceImpl) Pack(ctx *gin.Context, req *fulfillment_dto.PackReq) (*fulfillment_dto.PackRecord, error) {
+	order, err := f.orderService.Detail(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != order_dto.OrderStatusPicked {
+		return nil, sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+	}
+	productMap, err := f.getOrderProducts(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	expected := make(map[string]int)
+	for _, item := range order.Items {
+		expected[productMap[item.ProductID].StorageCode] += item.Quantity
+	}
+	scanned := make(map[string]int)
+	for _, code := range req.StorageCodes {
+		scanned[strings.TrimSpace(code)]++
+	}
+	if len(expected) != len(scanned) {
+		return nil, sm_error.NewHttpError(error_code.PackScanMismatch)
+	}
+	for code, nums := range expected {
+		if scanned[code] != nums {
+			return nil, sm_error.NewHttpError(error_code.PackScanMismatch, fmt.Sprintf("库位码%s应扫描%d件, 实际扫描%d件", code, nums, scanned[code]))
+		}
+	}
+
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	ok, err := f.orderRepo.UpdateStatus(ctx, tx, order.ID, order_dto.OrderStatusPicked, order_dto.OrderStatusPacked)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+		return nil, err
+	}
+	record := &fulfillment_dto.PackRecord{
+		OrderID:      order.ID,
+		PackerID:     util.GetUserIdByCookie(ctx),
+		ScannedCodes: req.StorageCodes,
+		PackTime:     time.Now(),
+	}
+	err = f.fulfillmentRepo.AddPackRecord(ctx, tx, record)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (f *fulfillmentServiceImpl) Ship(ctx *gin.Context, req *fulfillment_dto.ShipReq) (*fulfillment_dto.Shipment, error) {
+	order, err := f.orderService.Detail(ctx, req.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != order_dto.OrderStatusPacked {
+		return nil, sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+	}
+
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	ok, err := f.orderRepo.UpdateStatus(ctx, tx, order.ID, order_dto.OrderStatusPacked, order_dto.OrderStatusShipped)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+		return nil, err
+	}
+	shipment := &fulfillment_dto.Shipment{
+		OrderID:    order.ID,
+		Carrier:    req.Carrier,
+		TrackingNo: req.TrackingNo,
+		ShipperID:  util.GetUserIdByCookie(ctx),
+		ShipTime:   time.Now(),
+	}
+	err = f.fulfillmentRepo.AddShipment(ctx, tx, shipment)
+	if err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+func (f *fulfillmentServiceImpl) Timeline(ctx *gin.Context, orderId string) (*fulfillment_dto.FulfillmentTimeline, error) {
+	order, err := f.orderService.Detail(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	db := util.GetDBFromContext(ctx)
+	tasks, err := f.fulfillmentRepo.ListPickTask(ctx, db, &fulfillment_dto.PickTaskListReq{OrderID: orderId})
+	if err != nil {
+		return nil, err
+	}
+	packRecord, err := f.fulfillmentRepo.GetPackRecordByOrderId(ctx, db, orderId)
+	if err != nil {
+		return nil, err
+	}
+	shipment, err := f.fulfillmentRepo.GetShipmentByOrderId(ctx, db, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &fulfillment_dto.FulfillmentTimeline{
+		OrderID:     orderId,
+		ConfirmTime: order.ConfirmTime,
+	}
+	allPicked := len(tasks) > 0
+	for _, task := range tasks {
+		if task.ClaimTime != nil && (timeline.FirstClaimTime == nil || task.ClaimTime.Before(*timeline.FirstClaimTime)) {
+			timeline.FirstClaimTime = task.ClaimTime
+		}
+		if task.PickedTime == nil {
+			allPicked = false
+			continue
+		}
+		if timeline.LastPickedTime == nil || task.PickedTime.After(*timeline.LastPickedTime) {
+			timeline.LastPickedTime = task.PickedTime
+		}
+	}
+	if !allPicked {
+		timeline.LastPickedTime = nil
+	}
+	if packRecord != nil {
+		timeline.PackTime = &packRecord.PackTime
+	}
+	if shipment != nil {
+		timeline.ShipTime = &shipment.ShipTime
+	}
+	timeline.PickSeconds = secondsBetween(timeline.ConfirmTime, timeline.LastPickedTime)
+	timeline.PackSeconds = secondsBetween(timeline.LastPickedTime, timeline.PackTime)
+	timeline.ShipSeconds = secondsBetween(timeline.PackTime, timeline.ShipTime)
+	timeline.TotalSeconds = secondsBetween(timeline.ConfirmTime, timeline.ShipTime)
+	return timeline, nil
+}
+
+func (f *fulfillmentServiceImpl) getPickTask(ctx *gin.Context, taskId string) (*fulfillment_dto.PickTask, error) {
+	task, err := f.fulfillmentRepo.GetPickTaskById(ctx, util.GetDBFromContext(ctx), taskId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, sm_error.NewHttpError(error_code.PickTaskNoExists)
+	}
+	// 校验当前用户是否属于订单所在团队
+	if _, err := f.orderService.Detail(ctx, task.OrderID); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func (f *fulfillmentServiceImpl) getOrderProducts(ctx *gin.Context, order *order_dto.SalesOrder) (map[string]*product_dto.Product, error) {
+	productIds := make([]string, 0, len(order.Items))
+	for _, item := range order.Items {
+		productIds = append(productIds, item.ProductID)
+	}
+	products, err := f.productRepo.GetByIds(ctx, util.GetDBFromContext(ctx), productIds)
+	if err != nil {
+		return nil, err
+	}
+	productMap := make(map[string]*product_dto.Product)
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+	for _, item := range order.Items {
+		if _, ok := productMap[item.ProductID]; !ok {
+			return nil, sm_error.NewHttpError(error_code.OrderProductNoExists)
+		}
+	}
+	return productMap, nil
+}
+
+func (f *fulfillmentServiceImpl) fillProductName(ctx *gin.Context, tasks []*fulfillment_dto.PickTask) error {
+	productIds := make([]string, 0)
+	for _, task := range tasks {
+		for _, item := range task.Items {
+			productIds = append(productIds, item.ProductID)
+		}
+	}
+	products, err := f.productRepo.GetByIds(ctx, util.GetDBFromContext(ctx), productIds)
+	if err != nil {
+		return err
+	}
+	nameMap := make(map[string]string)
+	for _, product := range products {
+		nameMap[product.ID] = product.Name
+	}
+	for _, task := range tasks {
+		for _, item := range task.Items {
+			item.ProductName = nameMap[item.ProductID]
+		}
+	}
+	return nil
+}
+
+func secondsBetween(start *time.Time, end *time.Time) int64 {
+	if start == nil || end == nil {
+		return 0
+	}
+	return int64(end.Sub(*start).Seconds())
+}
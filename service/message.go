@@ -0,0 +1,9 @@
+package service
+
+import "github.com/gin-gonic/gin"
+
+// MessageSender 短信, 邮件等消息通道, 通过 message_service.RegisterSender 注册实现.
+// to 为手机号或邮箱, 取决于通道用于短信还是邮件
+type MessageSender interface {
+	Send(ctx *gin.Context, to string, content string) error
+}
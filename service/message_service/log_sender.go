@@ -0,0 +1,39 @@
+package message_service
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/config"
+	"github.com/shop_management/service"
+	"github.com/shop_management/vars"
+	"os"
+	"sync"
+	"time"
+)
+
+var logSenderMu sync.Mutex
+
+type logSender struct {
+	path string
+}
+
+// NewLogSender 开发用消息通道, 不真正发送, 只追加写入配置 message.log_path 指定的文件
+func NewLogSender() service.MessageSender {
+	return &logSender{path: config.Get().Message.LogPath}
+}
+
+func (l *logSender) Send(ctx *gin.Context, to string, content string) error {
+	logSenderMu.Lock()
+	defer logSenderMu.Unlock()
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		vars.Log.Errorf("logSender.Send open file error:%v,path: %v", err, l.path)
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file, "%s\t%s\t%s\n", time.Now().Format("2006-01-02 15:04:05"), to, content)
+	if err != nil {
+		vars.Log.Errorf("logSender.Send write error:%v,path: %v", err, l.path)
+	}
+	return err
+}
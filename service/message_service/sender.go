@@ -0,0 +1,44 @@
+package message_service
+
+import (
+	"github.com/shop_management/config"
+	"github.com/shop_management/service"
+	"sync"
+)
+
+// defaultSender 配置的 message.sender 未注册时使用, 开发环境将消息写入本地日志文件
+const defaultSender = "log"
+
+var (
+	sendersMu sync.RWMutex
+	senders   = map[string]func() service.MessageSender{
+		defaultSender: NewLogSender,
+	}
+)
+
+// RegisterSender 注册消息通道, 名称与配置 message.sender 对应
+func RegisterSender(name string, factory func() service.MessageSender) {
+	sendersMu.Lock()
+	defer sendersMu.Unlock()
+	senders[name] = factory
+}
+
+// NewMessageSender 按配置 message.sender 选择短信通道, 未注册的名称回退到日志通道
+func NewMessageSender() service.MessageSender {
+	return newSender(config.Get().Message.Sender)
+}
+
+// NewEmailSender 按配置 message.email_sender 选择邮件通道, 未注册的名称回退到日志通道
+func NewEmailSender() service.MessageSender {
+	return newSender(config.Get().Message.EmailSender)
+}
+
+func newSender(name string) service.MessageSender {
+	sendersMu.RLock()
+	defer sendersMu.RUnlock()
+	factory, ok := senders[name]
+	if !ok {
+		factory = senders[defaultSender]
+	}
+	return factory()
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/order_dto"
+)
+
+type OrderService interface {
+	Add(ctx *gin.Context, req *order_dto.AddOrderReq) (*order_dto.SalesOrder, error)
+	Confirm(ctx *gin.Context, orderId string) error
+	Detail(ctx *gin.Context, orderId string) (*order_dto.SalesOrder, error)
+	Invoice(ctx *gin.Context, orderId string) (*order_dto.Invoice, error)
+	List(ctx *gin.Context, req *order_dto.OrderListReq) (*order_dto.OrderListResp, error)
+}
@@ -0,0 +1,183 @@
+package order_service
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/order_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/promotion_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"math/rand"
+	"time"
+)
+
+type orderServiceImpl struct {
+	orderRepo        repository.OrderRepo
+	promotionService service.PromotionService
+}
+
+func NewOrderServiceImpl() service.OrderService {
+	return &orderServiceImpl{
+		orderRepo:        order_repo.NewOrderRepoImpl(),
+		promotionService: promotion_service.NewPromotionServiceImpl(),
+	}
+}
+
+func (o *orderServiceImpl) Add(ctx *gin.Context, req *order_dto.AddOrderReq) (*order_dto.SalesOrder, error) {
+	if len(req.Items) == 0 {
+		return nil, sm_error.NewHttpError(error_code.OrderItemEmpty)
+	}
+	evaluateItems := make([]*promotion_dto.EvaluateItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		evaluateItems = append(evaluateItems, &promotion_dto.EvaluateItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+	priced, err := o.promotionService.Evaluate(ctx, &promotion_dto.EvaluateReq{
+		CustomerID:  req.CustomerID,
+		CouponCodes: req.CouponCodes,
+		Items:       evaluateItems,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(priced.RejectedCoupons) > 0 {
+		rejected := priced.RejectedCoupons[0]
+		return nil, sm_error.NewHttpError(error_code.PromotionCouponRejected, rejected.CouponCode+": "+rejected.Reason)
+	}
+	order := &order_dto.SalesOrder{
+		OrderNo:        generateOrderNo(),
+		UserID:         util.GetUserIdByCookie(ctx),
+		CustomerID:     req.CustomerID,
+		CustomerName:   req.CustomerName,
+		Remark:         req.Remark,
+		Status:         order_dto.OrderStatusDraft,
+		Currency:       priced.Currency,
+		TotalAmount:    priced.GrossAmount,
+		DiscountAmount: priced.DiscountAmount,
+		NetAmount:      priced.NetAmount,
+		TaxAmount:      priced.TaxAmount,
+		Items:          make([]*order_dto.SalesOrderItem, 0, len(priced.Lines)),
+	}
+	for _, line := range priced.Lines {
+		order.Items = append(order.Items, &order_dto.SalesOrderItem{
+			ProductID:      line.ProductID,
+			Quantity:       line.Quantity,
+			UnitPrice:      line.UnitPrice,
+			DiscountAmount: line.DiscountAmount,
+			Amount:         line.GrossAmount,
+			TaxRate:        line.TaxRate,
+			NetAmount:      line.NetAmount,
+			TaxAmount:      line.TaxAmount,
+		})
+	}
+
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = o.orderRepo.Add(ctx, tx, order)
+	if err != nil {
+		return nil, err
+	}
+	err = o.promotionService.Redeem(ctx, tx, order.ID, priced)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (o *orderServiceImpl) Confirm(ctx *gin.Context, orderId string) error {
+	db := util.GetDBFromContext(ctx)
+	order, err := o.getWithPermission(ctx, orderId)
+	if err != nil {
+		return err
+	}
+	if order.Status != order_dto.OrderStatusDraft {
+		return sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+	}
+	ok, err := o.orderRepo.Confirm(ctx, db, orderId, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+	}
+	return nil
+}
+
+func (o *orderServiceImpl) Detail(ctx *gin.Context, orderId string) (*order_dto.SalesOrder, error) {
+	return o.getWithPermission(ctx, orderId)
+}
+
+// Invoice 根据下单时记录的行税额生成发票, 税率变更不影响已下单的发票
+func (o *orderServiceImpl) Invoice(ctx *gin.Context, orderId string) (*order_dto.Invoice, error) {
+	order, err := o.getWithPermission(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status == order_dto.OrderStatusDraft || order.Status == order_dto.OrderStatusCanceled {
+		return nil, sm_error.NewHttpError(error_code.OrderStatusIncorrect)
+	}
+	invoice := &order_dto.Invoice{
+		InvoiceNo:    "INV" + order.OrderNo,
+		Order:        order,
+		NetAmount:    order.NetAmount,
+		TaxAmount:    order.TaxAmount,
+		TotalAmount:  order.TotalAmount,
+		TaxBreakdown: make([]*tax_dto.TaxBreakdown, 0),
+	}
+	breakdownMap := make(map[float64]*tax_dto.TaxBreakdown)
+	for _, item := range order.Items {
+		breakdown, ok := breakdownMap[item.TaxRate]
+		if !ok {
+			breakdown = &tax_dto.TaxBreakdown{TaxRate: item.TaxRate}
+			breakdownMap[item.TaxRate] = breakdown
+			invoice.TaxBreakdown = append(invoice.TaxBreakdown, breakdown)
+		}
+		breakdown.NetAmount += item.NetAmount
+		breakdown.TaxAmount += item.TaxAmount
+	}
+	return invoice, nil
+}
+
+func (o *orderServiceImpl) List(ctx *gin.Context, req *order_dto.OrderListReq) (*order_dto.OrderListResp, error) {
+	list, err := o.orderRepo.List(ctx, util.GetDBFromContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	return &order_dto.OrderListResp{
+		Pager: req.Pager,
+		List:  list,
+	}, nil
+}
+
+// getWithPermission 订单属于当前团队即可操作, 具体能做什么由角色权限决定
+func (o *orderServiceImpl) getWithPermission(ctx *gin.Context, orderId string) (*order_dto.SalesOrder, error) {
+	order, err := o.orderRepo.GetById(ctx, util.GetDBFromContext(ctx), orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, sm_error.NewHttpError(error_code.OrderNoExists)
+	}
+	if order.TeamID != util.GetTeamId(ctx) {
+		return nil, sm_error.NewHttpError(error_code.OrderNoPermission)
+	}
+	return order, nil
+}
+
+func generateOrderNo() string {
+	return fmt.Sprintf("SO%s%04d", time.Now().Format("20060102150405"), rand.Intn(10000))
+}
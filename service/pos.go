@@ -0,0 +1,14 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/pos_dto"
+	"github.com/shop_management/util"
+)
+
+type PosService interface {
+	OpenShift(ctx *gin.Context, openingCash util.Money) (*pos_dto.PosShift, error)
+	CurrentShift(ctx *gin.Context) (*pos_dto.PosShift, error)
+	CloseShift(ctx *gin.Context, closingCash util.Money) (*pos_dto.PosShift, error)
+	Checkout(ctx *gin.Context, req *pos_dto.CheckoutReq) (*pos_dto.Receipt, error)
+}
@@ -0,0 +1,329 @@
+package pos_service
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/dto/pos_dto"
+	"github.com/shop_management/dto/product_dto"
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/order_repo"
+	"github.com/shop_management/repository/pos_repo"
+	"github.com/shop_management/repository/product_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/currency_service"
+	"github.com/shop_management/service/promotion_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+type posServiceImpl struct {
+	posRepo          repository.PosRepo
+	productRepo      repository.ProductRepo
+	orderRepo        repository.OrderRepo
+	promotionService service.PromotionService
+	currencyService  service.CurrencyService
+}
+
+func NewPosServiceImpl() service.PosService {
+	return &posServiceImpl{
+		posRepo:          pos_repo.NewPosRepoImpl(),
+		productRepo:      product_repo.NewProductRepoImpl(),
+		orderRepo:        order_repo.NewOrderRepoImpl(),
+		promotionService: promotion_service.NewPromotionServiceImpl(),
+		currencyService:  currency_service.NewCurrencyServiceImpl(),
+	}
+}
+
+func (p *posServiceImpl) OpenShift(ctx *gin.Context, openingCash util.Money) (*pos_dto.PosShift, error) {
+	db := util.GetDBFromContext(ctx)
+	userId := util.GetUserIdByCookie(ctx)
+	shift, err := p.posRepo.GetOpenShift(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	if shift != nil {
+		return nil, sm_error.NewHttpError(error_code.PosShiftAlreadyOpen)
+	}
+	currency, err := p.currencyService.GetBaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shift = &pos_dto.PosShift{
+		UserID:      userId,
+		Status:      pos_dto.ShiftStatusOpen,
+		Currency:    currency,
+		OpeningCash: openingCash,
+		OpenTime:    time.Now(),
+	}
+	err = p.posRepo.AddShift(ctx, db, shift)
+	if err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+func (p *posServiceImpl) CurrentShift(ctx *gin.Context) (*pos_dto.PosShift, error) {
+	db := util.GetDBFromContext(ctx)
+	shift, err := p.posRepo.GetOpenShift(ctx, db, util.GetUserIdByCookie(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if shift == nil {
+		return nil, sm_error.NewHttpError(error_code.PosShiftNotOpen)
+	}
+	shift.ExpectedCash, err = p.expectedCash(ctx, shift)
+	if err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// CloseShift 应有现金 = 开班备用金 + 现金收款 - 找零, 与实点现金的差额记为长短款
+func (p *posServiceImpl) CloseShift(ctx *gin.Context, closingCash util.Money) (*pos_dto.PosShift, error) {
+	shift, err := p.CurrentShift(ctx)
+	if err != nil {
+		return nil, err
+	}
+	closeTime := time.Now()
+	shift.Status = pos_dto.ShiftStatusClosed
+	shift.ClosingCash = closingCash
+	shift.CashDifference = shift.ClosingCash - shift.ExpectedCash
+	shift.CloseTime = &closeTime
+	ok, err := p.posRepo.CloseShift(ctx, util.GetDBFromContext(ctx), shift)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sm_error.NewHttpError(error_code.PosShiftNotOpen)
+	}
+	return shift, nil
+}
+
+func (p *posServiceImpl) Checkout(ctx *gin.Context, req *pos_dto.CheckoutReq) (*pos_dto.Receipt, error) {
+	if len(req.Items) == 0 {
+		return nil, sm_error.NewHttpError(error_code.PosItemEmpty)
+	}
+	db := util.GetDBFromContext(ctx)
+	userId := util.GetUserIdByCookie(ctx)
+	shift, err := p.posRepo.GetOpenShift(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	if shift == nil {
+		return nil, sm_error.NewHttpError(error_code.PosShiftNotOpen)
+	}
+	items, err := p.resolveItems(ctx, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	priced, err := p.promotionService.Evaluate(ctx, &promotion_dto.EvaluateReq{
+		CustomerID:  req.CustomerID,
+		CouponCodes: req.CouponCodes,
+		Items:       items,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(priced.RejectedCoupons) > 0 {
+		rejected := priced.RejectedCoupons[0]
+		return nil, sm_error.NewHttpError(error_code.PromotionCouponRejected, rejected.CouponCode+": "+rejected.Reason)
+	}
+	payments, paidAmount, changeAmount, err := settleTenders(req.Tenders, priced.GrossAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	order := &order_dto.SalesOrder{
+		OrderNo:        fmt.Sprintf("POS%s%04d", now.Format("20060102150405"), rand.Intn(10000)),
+		UserID:         userId,
+		CustomerID:     req.CustomerID,
+		Status:         order_dto.OrderStatusCompleted,
+		Currency:       priced.Currency,
+		TotalAmount:    priced.GrossAmount,
+		DiscountAmount: priced.DiscountAmount,
+		NetAmount:      priced.NetAmount,
+		TaxAmount:      priced.TaxAmount,
+		ConfirmTime:    &now,
+		Items:          make([]*order_dto.SalesOrderItem, 0, len(priced.Lines)),
+	}
+	for _, line := range priced.Lines {
+		order.Items = append(order.Items, &order_dto.SalesOrderItem{
+			ProductID:      line.ProductID,
+			Quantity:       line.Quantity,
+			UnitPrice:      line.UnitPrice,
+			DiscountAmount: line.DiscountAmount,
+			Amount:         line.GrossAmount,
+			TaxRate:        line.TaxRate,
+			NetAmount:      line.NetAmount,
+			TaxAmount:      line.TaxAmount,
+		})
+	}
+
+	tx := db.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = p.orderRepo.Add(ctx, tx, order)
+	if err != nil {
+		return nil, err
+	}
+	// 门店销售当场出库, 库存不足时整单回滚
+	for _, line := range priced.Lines {
+		ok, e := p.productRepo.DecrStock(ctx, tx, line.ProductID, line.Quantity)
+		if e != nil {
+			err = e
+			return nil, err
+		}
+		if !ok {
+			err = sm_error.NewHttpError(error_code.PosStockInsufficient, line.ProductName+" 库存不足")
+			return nil, err
+		}
+	}
+	err = p.promotionService.Redeem(ctx, tx, order.ID, priced)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range payments {
+		payment.ShiftID = shift.ID
+		payment.OrderID = order.ID
+	}
+	err = p.posRepo.AddPayments(ctx, tx, payments)
+	if err != nil {
+		return nil, err
+	}
+
+	tenders := make([]*pos_dto.Tender, 0, len(payments))
+	for _, payment := range payments {
+		tenders = append(tenders, &pos_dto.Tender{TenderType: payment.TenderType, Amount: payment.Amount})
+	}
+	return &pos_dto.Receipt{
+		OrderID:        order.ID,
+		ReceiptNo:      order.OrderNo,
+		ShiftID:        shift.ID,
+		CashierID:      userId,
+		Currency:       priced.Currency,
+		Lines:          priced.Lines,
+		OriginalAmount: priced.OriginalAmount,
+		DiscountAmount: priced.DiscountAmount,
+		NetAmount:      priced.NetAmount,
+		TaxAmount:      priced.TaxAmount,
+		TaxBreakdown:   priced.TaxBreakdown,
+		TotalAmount:    priced.GrossAmount,
+		PaidAmount:     paidAmount,
+		ChangeAmount:   changeAmount,
+		Tenders:        tenders,
+		CheckoutTime:   now,
+	}, nil
+}
+
+// resolveItems 将扫描的条码或库位码解析为商品, 同一商品多次扫描合并数量
+func (p *posServiceImpl) resolveItems(ctx *gin.Context, scanned []*pos_dto.CheckoutItem) ([]*promotion_dto.EvaluateItem, error) {
+	codes := make([]string, 0, len(scanned))
+	for _, item := range scanned {
+		item.Code = strings.TrimSpace(item.Code)
+		codes = append(codes, item.Code)
+	}
+	products, err := p.productRepo.GetByCodes(ctx, util.GetDBFromContext(ctx), codes)
+	if err != nil {
+		return nil, err
+	}
+	codeMap := make(map[string]*product_dto.Product)
+	for _, product := range products {
+		if product.StorageCode != "" {
+			codeMap[product.StorageCode] = product
+		}
+		if product.Barcode != "" {
+			codeMap[product.Barcode] = product
+		}
+	}
+	itemMap := make(map[string]*promotion_dto.EvaluateItem)
+	items := make([]*promotion_dto.EvaluateItem, 0, len(scanned))
+	for _, item := range scanned {
+		product, ok := codeMap[item.Code]
+		if !ok {
+			return nil, sm_error.NewHttpError(error_code.PosCodeNotFound, item.Code+" 未找到商品")
+		}
+		quantity := item.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		if exists, ok := itemMap[product.ID]; ok {
+			exists.Quantity += quantity
+			continue
+		}
+		evaluateItem := &promotion_dto.EvaluateItem{ProductID: product.ID, Quantity: quantity}
+		itemMap[product.ID] = evaluateItem
+		items = append(items, evaluateItem)
+	}
+	return items, nil
+}
+
+func (p *posServiceImpl) expectedCash(ctx *gin.Context, shift *pos_dto.PosShift) (util.Money, error) {
+	payments, err := p.posRepo.ListPaymentByShiftId(ctx, util.GetDBFromContext(ctx), shift.ID)
+	if err != nil {
+		return 0, err
+	}
+	expected := shift.OpeningCash
+	for _, payment := range payments {
+		if payment.TenderType == pos_dto.TenderTypeCash {
+			expected += payment.Amount - payment.ChangeAmount
+		}
+	}
+	return expected, nil
+}
+
+// settleTenders 支持多种支付方式组合, 只有现金可以找零
+func settleTenders(tenders []*pos_dto.Tender, total util.Money) ([]*pos_dto.PosPayment, util.Money, util.Money, error) {
+	payments := make([]*pos_dto.PosPayment, 0, len(tenders))
+	paid, nonCash, cash := util.Money(0), util.Money(0), util.Money(0)
+	var cashPayment *pos_dto.PosPayment
+	for _, tender := range tenders {
+		if tender.Amount <= 0 {
+			return nil, 0, 0, sm_error.NewHttpError(error_code.PosTenderInvalid)
+		}
+		switch tender.TenderType {
+		case pos_dto.TenderTypeCash:
+			cash += tender.Amount
+		case pos_dto.TenderTypeCard, pos_dto.TenderTypeWechat, pos_dto.TenderTypeAlipay:
+			nonCash += tender.Amount
+		default:
+			return nil, 0, 0, sm_error.NewHttpError(error_code.PosTenderInvalid)
+		}
+		paid += tender.Amount
+		// 多笔现金合并为一条收款记录, 便于记录找零
+		if tender.TenderType == pos_dto.TenderTypeCash && cashPayment != nil {
+			cashPayment.Amount += tender.Amount
+			continue
+		}
+		payment := &pos_dto.PosPayment{TenderType: tender.TenderType, Amount: tender.Amount}
+		if tender.TenderType == pos_dto.TenderTypeCash {
+			cashPayment = payment
+		}
+		payments = append(payments, payment)
+	}
+	if paid < total {
+		return nil, 0, 0, sm_error.NewHttpError(error_code.PosTenderInsufficient)
+	}
+	if nonCash > total {
+		return nil, 0, 0, sm_error.NewHttpError(error_code.PosTenderInvalid, "非现金支付金额不能超过应收金额")
+	}
+	change := paid - total
+	if change > 0 {
+		if change > cash {
+			return nil, 0, 0, sm_error.NewHttpError(error_code.PosTenderInvalid)
+		}
+		cashPayment.ChangeAmount = change
+	}
+	return payments, paid, change, nil
+}
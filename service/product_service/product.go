@@ -3,30 +3,75 @@ package product_service
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/shop_management/dto/product_dto"
+	"github.com/shop_management/dto/user_dto"
 	"github.com/shop_management/repository"
 	"github.com/shop_management/repository/product_repo"
 	"github.com/shop_management/service"
+	"github.com/shop_management/service/auth_service"
 	"github.com/shop_management/util"
 )
 
 type productServiceImpl struct {
 	productRepo repository.ProductRepo
+	authService service.AuthService
 }
 
 func NewProductServiceImpl() service.ProductService {
 	return &productServiceImpl{
 		productRepo: product_repo.NewProductRepoImpl(),
+		authService: auth_service.NewAuthServiceImpl(),
 	}
 }
 
 func (p *productServiceImpl) Add(ctx *gin.Context, dto *product_dto.Product) error {
-	err := p.productRepo.AddProduct(ctx, util.GetDBFromContext(ctx), dto)
+	err := p.authService.CheckWarehouse(ctx, dto.Warehouse, user_dto.ActionWrite)
+	if err != nil {
+		return err
+	}
+	err = p.productRepo.AddProduct(ctx, util.GetDBFromContext(ctx), dto)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (p *productServiceImpl) List(ctx *gin.Context, dto *product_dto.ProductListReq) (*product_dto.ProductListResp, error) {
-	return nil, nil
+// List 有仓库授权的子账号只能查看被授权仓库的商品, 没有查看成本权限时不返回成本价和采购价
+func (p *productServiceImpl) List(ctx *gin.Context, req *product_dto.ProductListReq) (*product_dto.ProductListResp, error) {
+	if req.Warehouse != "" {
+		err := p.authService.CheckWarehouse(ctx, req.Warehouse, user_dto.ActionRead)
+		if err != nil {
+			return nil, err
+		}
+	}
+	warehouses, restricted, err := p.authService.WarehouseScope(ctx, user_dto.ActionRead)
+	if err != nil {
+		return nil, err
+	}
+	if restricted {
+		req.Warehouses = warehouses
+	}
+	list, err := p.productRepo.List(ctx, util.GetDBFromContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	err = p.redactCost(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	return &product_dto.ProductListResp{
+		Pager: req.Pager,
+		Data:  list,
+	}, nil
+}
+
+func (p *productServiceImpl) redactCost(ctx *gin.Context, list []*product_dto.Product) error {
+	canViewCost, err := p.authService.CanViewCost(ctx)
+	if err != nil || canViewCost {
+		return err
+	}
+	for _, product := range list {
+		product.CostPrice = 0
+		product.PurchasePrice = 0
+	}
+	return nil
 }
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/promotion_dto"
+	"gorm.io/gorm"
+)
+
+type PromotionService interface {
+	Add(ctx *gin.Context, dto *promotion_dto.Promotion) (*promotion_dto.Promotion, error)
+	List(ctx *gin.Context) ([]*promotion_dto.Promotion, error)
+	Disable(ctx *gin.Context, id string) error
+	Evaluate(ctx *gin.Context, req *promotion_dto.EvaluateReq) (*promotion_dto.EvaluateResp, error)
+	Redeem(ctx *gin.Context, tx *gorm.DB, orderId string, resp *promotion_dto.EvaluateResp) error
+}
@@ -0,0 +1,138 @@
+package promotion_service
+
+import (
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/util"
+	"sort"
+)
+
+// lineState 记录每一行在计算过程中的状态, exclusive 表示已应用了不可叠加的优惠
+type lineState struct {
+	line      *promotion_dto.EvaluateLine
+	exclusive bool
+}
+
+func (l *lineState) remaining() util.Money {
+	return l.line.OriginalAmount - l.line.DiscountAmount
+}
+
+func (l *lineState) apply(p *promotion_dto.Promotion, couponCode string, amount util.Money) {
+	l.line.Discounts = append(l.line.Discounts, &promotion_dto.AppliedDiscount{
+		PromotionID:   p.ID,
+		PromotionName: p.Name,
+		Type:          p.Type,
+		CouponCode:    couponCode,
+		Amount:        amount,
+	})
+	l.line.DiscountAmount += amount
+	if !p.Stackable {
+		l.exclusive = true
+	}
+}
+
+// canApply 不可叠加的优惠只能用于没有其他优惠的行, 且应用后该行不再接受其他优惠
+func (l *lineState) canApply(p *promotion_dto.Promotion) bool {
+	if l.exclusive {
+		return false
+	}
+	if !p.Stackable && len(l.line.Discounts) > 0 {
+		return false
+	}
+	return p.Category == "" || p.Category == l.line.Category
+}
+
+// evaluate 先按优先级计算单品类优惠, 再计算订单级的优惠券
+func evaluate(promotions []*promotion_dto.Promotion, coupons map[string]string, lines []*promotion_dto.EvaluateLine) []*promotion_dto.RejectedCoupon {
+	sorted := make([]*promotion_dto.Promotion, len(promotions))
+	copy(sorted, promotions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Priority > sorted[j].Priority
+	})
+	states := make([]*lineState, 0, len(lines))
+	for _, line := range lines {
+		states = append(states, &lineState{line: line})
+	}
+
+	for _, p := range sorted {
+		if p.Type == promotion_dto.PromotionTypeCoupon {
+			continue
+		}
+		for _, state := range states {
+			if !state.canApply(p) {
+				continue
+			}
+			amount := util.MinMoney(lineDiscount(p, state), state.remaining())
+			if amount <= 0 {
+				continue
+			}
+			state.apply(p, "", amount)
+		}
+	}
+
+	rejected := make([]*promotion_dto.RejectedCoupon, 0)
+	for _, p := range sorted {
+		if p.Type != promotion_dto.PromotionTypeCoupon {
+			continue
+		}
+		code, ok := coupons[p.ID]
+		if !ok {
+			continue
+		}
+		eligible := make([]*lineState, 0)
+		base := util.Money(0)
+		for _, state := range states {
+			if state.canApply(p) && state.remaining() > 0 {
+				eligible = append(eligible, state)
+				base += state.remaining()
+			}
+		}
+		if len(eligible) == 0 || base < p.Rule.MinOrderAmount {
+			rejected = append(rejected, &promotion_dto.RejectedCoupon{CouponCode: code, Reason: "未达到优惠券使用条件"})
+			continue
+		}
+		total := util.MinMoney(p.Rule.Amount, base)
+		allocated := util.Money(0)
+		for i, state := range eligible {
+			amount := total.MulDiv(state.remaining(), base)
+			if i == len(eligible)-1 {
+				amount = total - allocated
+			}
+			amount = util.MinMoney(amount, state.remaining())
+			allocated += amount
+			if amount > 0 {
+				state.apply(p, code, amount)
+			}
+		}
+	}
+
+	for _, state := range states {
+		state.line.FinalAmount = state.remaining()
+	}
+	return rejected
+}
+
+func lineDiscount(p *promotion_dto.Promotion, state *lineState) util.Money {
+	line := state.line
+	switch p.Type {
+	case promotion_dto.PromotionTypePercentOff:
+		return state.remaining().MulRate(p.Rule.Percent / 100)
+	case promotion_dto.PromotionTypeBuyXGetY:
+		group := p.Rule.BuyQuantity + p.Rule.FreeQuantity
+		if p.Rule.BuyQuantity <= 0 || p.Rule.FreeQuantity <= 0 {
+			return 0
+		}
+		freeNums := line.Quantity / group * p.Rule.FreeQuantity
+		return line.UnitPrice.Mul(freeNums)
+	case promotion_dto.PromotionTypeTiered:
+		percent := 0.0
+		minQuantity := 0
+		for _, tier := range p.Rule.Tiers {
+			if line.Quantity >= tier.MinQuantity && tier.MinQuantity >= minQuantity {
+				minQuantity = tier.MinQuantity
+				percent = tier.Percent
+			}
+		}
+		return state.remaining().MulRate(percent / 100)
+	}
+	return 0
+}
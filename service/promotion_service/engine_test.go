@@ -0,0 +1,171 @@
+package promotion_service
+
+import (
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/util"
+	"testing"
+)
+
+func newLine(category string, quantity int, unitPrice util.Money) *promotion_dto.EvaluateLine {
+	return &promotion_dto.EvaluateLine{
+		Category:       category,
+		Quantity:       quantity,
+		UnitPrice:      unitPrice,
+		OriginalAmount: unitPrice.Mul(quantity),
+	}
+}
+
+func percentOff(id string, category string, percent float64, stackable bool, priority int) *promotion_dto.Promotion {
+	return &promotion_dto.Promotion{
+		ID:        id,
+		Type:      promotion_dto.PromotionTypePercentOff,
+		Category:  category,
+		Rule:      &promotion_dto.PromotionRule{Percent: percent},
+		Stackable: stackable,
+		Priority:  priority,
+	}
+}
+
+func coupon(id string, amount util.Money, minOrderAmount util.Money, stackable bool) *promotion_dto.Promotion {
+	return &promotion_dto.Promotion{
+		ID:        id,
+		Type:      promotion_dto.PromotionTypeCoupon,
+		Rule:      &promotion_dto.PromotionRule{Amount: amount, MinOrderAmount: minOrderAmount},
+		Stackable: stackable,
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		promotions []*promotion_dto.Promotion
+		coupons    map[string]string
+		lines      []*promotion_dto.EvaluateLine
+		// wantFinal 各行优惠后的金额, wantApplied 各行依次应用的优惠
+		wantFinal    []util.Money
+		wantApplied  [][]string
+		wantRejected []string
+	}{
+		{
+			name:        "percent off by category",
+			promotions:  []*promotion_dto.Promotion{percentOff("p1", "food", 10, true, 0)},
+			lines:       []*promotion_dto.EvaluateLine{newLine("food", 1, 10000), newLine("toy", 1, 10000)},
+			wantFinal:   []util.Money{9000, 10000},
+			wantApplied: [][]string{{"p1"}, nil},
+		},
+		{
+			name: "stackable promotions apply in priority order on the remaining amount",
+			promotions: []*promotion_dto.Promotion{
+				{ID: "tier", Type: promotion_dto.PromotionTypeTiered, Stackable: true, Priority: 1, Rule: &promotion_dto.PromotionRule{
+					Tiers: []*promotion_dto.PromotionTier{{MinQuantity: 2, Percent: 10}, {MinQuantity: 5, Percent: 20}},
+				}},
+				percentOff("p1", "", 10, true, 5),
+			},
+			lines:       []*promotion_dto.EvaluateLine{newLine("toy", 5, 2000)},
+			wantFinal:   []util.Money{7200},
+			wantApplied: [][]string{{"p1", "tier"}},
+		},
+		{
+			name:        "exclusive promotion blocks later ones",
+			promotions:  []*promotion_dto.Promotion{percentOff("p1", "", 10, true, 1), percentOff("p2", "", 50, false, 9)},
+			lines:       []*promotion_dto.EvaluateLine{newLine("toy", 1, 10000)},
+			wantFinal:   []util.Money{5000},
+			wantApplied: [][]string{{"p2"}},
+		},
+		{
+			name:        "exclusive promotion skips discounted lines",
+			promotions:  []*promotion_dto.Promotion{percentOff("p1", "food", 10, true, 9), percentOff("p2", "", 50, false, 1)},
+			lines:       []*promotion_dto.EvaluateLine{newLine("food", 1, 10000), newLine("toy", 1, 10000)},
+			wantFinal:   []util.Money{9000, 5000},
+			wantApplied: [][]string{{"p1"}, {"p2"}},
+		},
+		{
+			name: "buy x get y",
+			promotions: []*promotion_dto.Promotion{{ID: "bxgy", Type: promotion_dto.PromotionTypeBuyXGetY, Stackable: true,
+				Rule: &promotion_dto.PromotionRule{BuyQuantity: 2, FreeQuantity: 1}}},
+			lines:       []*promotion_dto.EvaluateLine{newLine("toy", 7, 1000)},
+			wantFinal:   []util.Money{5000},
+			wantApplied: [][]string{{"bxgy"}},
+		},
+		{
+			name:        "coupon split by remaining amount",
+			promotions:  []*promotion_dto.Promotion{percentOff("p1", "food", 50, true, 0), coupon("c1", 3000, 5000, true)},
+			coupons:     map[string]string{"c1": "SAVE30"},
+			lines:       []*promotion_dto.EvaluateLine{newLine("food", 1, 6000), newLine("toy", 1, 6000)},
+			wantFinal:   []util.Money{2000, 4000},
+			wantApplied: [][]string{{"p1", "c1"}, {"c1"}},
+		},
+		{
+			name:        "coupon capped at order amount",
+			promotions:  []*promotion_dto.Promotion{coupon("c1", 5000, 0, true)},
+			coupons:     map[string]string{"c1": "SAVE50"},
+			lines:       []*promotion_dto.EvaluateLine{newLine("toy", 1, 1999), newLine("toy", 1, 1)},
+			wantFinal:   []util.Money{0, 0},
+			wantApplied: [][]string{{"c1"}, {"c1"}},
+		},
+		{
+			name:         "coupon below minimum is rejected",
+			promotions:   []*promotion_dto.Promotion{coupon("c1", 1000, 5000, true)},
+			coupons:      map[string]string{"c1": "SAVE10"},
+			lines:        []*promotion_dto.EvaluateLine{newLine("toy", 1, 4999)},
+			wantFinal:    []util.Money{4999},
+			wantApplied:  [][]string{nil},
+			wantRejected: []string{"SAVE10"},
+		},
+		{
+			name:         "exclusive coupon rejected when every line is discounted",
+			promotions:   []*promotion_dto.Promotion{percentOff("p1", "", 10, true, 0), coupon("c1", 1000, 0, false)},
+			coupons:      map[string]string{"c1": "SAVE10"},
+			lines:        []*promotion_dto.EvaluateLine{newLine("toy", 1, 10000)},
+			wantFinal:    []util.Money{9000},
+			wantApplied:  [][]string{{"p1"}},
+			wantRejected: []string{"SAVE10"},
+		},
+		{
+			name:        "coupon not entered is ignored",
+			promotions:  []*promotion_dto.Promotion{coupon("c1", 1000, 0, true)},
+			lines:       []*promotion_dto.EvaluateLine{newLine("toy", 1, 10000)},
+			wantFinal:   []util.Money{10000},
+			wantApplied: [][]string{nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rejected := evaluate(tt.promotions, tt.coupons, tt.lines)
+			for i, line := range tt.lines {
+				if line.FinalAmount != tt.wantFinal[i] {
+					t.Errorf("line %d final = %v, want %v", i, line.FinalAmount, tt.wantFinal[i])
+				}
+				if line.FinalAmount != line.OriginalAmount-line.DiscountAmount {
+					t.Errorf("line %d final %v != original %v - discount %v", i, line.FinalAmount, line.OriginalAmount, line.DiscountAmount)
+				}
+				applied := make([]string, 0, len(line.Discounts))
+				for _, d := range line.Discounts {
+					applied = append(applied, d.PromotionID)
+				}
+				if !equalStrings(applied, tt.wantApplied[i]) {
+					t.Errorf("line %d applied = %v, want %v", i, applied, tt.wantApplied[i])
+				}
+			}
+			codes := make([]string, 0, len(rejected))
+			for _, r := range rejected {
+				codes = append(codes, r.CouponCode)
+			}
+			if !equalStrings(codes, tt.wantRejected) {
+				t.Errorf("rejected = %v, want %v", codes, tt.wantRejected)
+			}
+		})
+	}
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,282 @@
+package promotion_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/product_dto"
+	"github.com/shop_management/dto/promotion_dto"
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/product_repo"
+	"github.com/shop_management/repository/promotion_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/currency_service"
+	"github.com/shop_management/service/tax_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+type promotionServiceImpl struct {
+	promotionRepo   repository.PromotionRepo
+	productRepo     repository.ProductRepo
+	taxService      service.TaxService
+	currencyService service.CurrencyService
+}
+
+func NewPromotionServiceImpl() service.PromotionService {
+	return &promotionServiceImpl{
+		promotionRepo:   promotion_repo.NewPromotionRepoImpl(),
+		productRepo:     product_repo.NewProductRepoImpl(),
+		taxService:      tax_service.NewTaxServiceImpl(),
+		currencyService: currency_service.NewCurrencyServiceImpl(),
+	}
+}
+
+func (p *promotionServiceImpl) Add(ctx *gin.Context, dto *promotion_dto.Promotion) (*promotion_dto.Promotion, error) {
+	err := checkRule(dto)
+	if err != nil {
+		return nil, err
+	}
+	dto.UserID = util.GetTeamId(ctx)
+	dto.Status = promotion_dto.PromotionStatusEnabled
+	dto.UsedCount = 0
+	dto.CouponCode = strings.TrimSpace(dto.CouponCode)
+	err = p.promotionRepo.Add(ctx, util.GetDBFromContext(ctx), dto)
+	if err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+func (p *promotionServiceImpl) List(ctx *gin.Context) ([]*promotion_dto.Promotion, error) {
+	return p.promotionRepo.List(ctx, util.GetDBFromContext(ctx), util.GetTeamId(ctx))
+}
+
+func (p *promotionServiceImpl) Disable(ctx *gin.Context, id string) error {
+	db := util.GetDBFromContext(ctx)
+	promotion, err := p.promotionRepo.GetById(ctx, db, id)
+	if err != nil {
+		return err
+	}
+	if promotion == nil || promotion.UserID != util.GetTeamId(ctx) {
+		return sm_error.NewHttpError(error_code.PromotionNoExists)
+	}
+	return p.promotionRepo.UpdateStatus(ctx, db, id, promotion_dto.PromotionStatusDisabled)
+}
+
+func (p *promotionServiceImpl) Evaluate(ctx *gin.Context, req *promotion_dto.EvaluateReq) (*promotion_dto.EvaluateResp, error) {
+	db := util.GetDBFromContext(ctx)
+	lines, err := p.buildLines(ctx, db, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	promotions, err := p.promotionRepo.ListActive(ctx, db, util.GetTeamId(ctx), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := p.currencyService.GetBaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &promotion_dto.EvaluateResp{
+		CustomerID:      req.CustomerID,
+		Currency:        currency,
+		Lines:           lines,
+		RejectedCoupons: make([]*promotion_dto.RejectedCoupon, 0),
+	}
+	// 优惠券只在客户提供券码时生效, 且每个客户只能使用一次
+	couponMap := make(map[string]*promotion_dto.Promotion)
+	for _, promotion := range promotions {
+		if promotion.Type == promotion_dto.PromotionTypeCoupon && promotion.CouponCode != "" {
+			couponMap[promotion.CouponCode] = promotion
+		}
+	}
+	coupons := make(map[string]string)
+	for _, code := range req.CouponCodes {
+		code = strings.TrimSpace(code)
+		promotion, ok := couponMap[code]
+		if !ok {
+			resp.RejectedCoupons = append(resp.RejectedCoupons, &promotion_dto.RejectedCoupon{CouponCode: code, Reason: "优惠券无效或已过期"})
+			continue
+		}
+		if req.CustomerID == "" {
+			resp.RejectedCoupons = append(resp.RejectedCoupons, &promotion_dto.RejectedCoupon{CouponCode: code, Reason: "使用优惠券需要指定客户"})
+			continue
+		}
+		redemption, err := p.promotionRepo.GetRedemption(ctx, db, promotion.ID, req.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		if redemption != nil {
+			resp.RejectedCoupons = append(resp.RejectedCoupons, &promotion_dto.RejectedCoupon{CouponCode: code, Reason: "该客户已使用过此优惠券"})
+			continue
+		}
+		coupons[promotion.ID] = code
+	}
+
+	rejected := evaluate(promotions, coupons, lines)
+	resp.RejectedCoupons = append(resp.RejectedCoupons, rejected...)
+	for _, line := range lines {
+		resp.OriginalAmount += line.OriginalAmount
+		resp.DiscountAmount += line.DiscountAmount
+		resp.FinalAmount += line.FinalAmount
+	}
+	err = p.applyTax(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// applyTax 在优惠计算完成后按折扣后的行金额计算税额
+func (p *promotionServiceImpl) applyTax(ctx *gin.Context, resp *promotion_dto.EvaluateResp) error {
+	taxLines := make([]*tax_dto.CalculateLine, 0, len(resp.Lines))
+	for _, line := range resp.Lines {
+		taxLines = append(taxLines, &tax_dto.CalculateLine{
+			ProductID: line.ProductID,
+			Category:  line.Category,
+			Amount:    line.FinalAmount,
+		})
+	}
+	taxed, err := p.taxService.Calculate(ctx, &tax_dto.CalculateReq{
+		CustomerID: resp.CustomerID,
+		Lines:      taxLines,
+	})
+	if err != nil {
+		return err
+	}
+	for i, line := range resp.Lines {
+		line.TaxRate = taxed.Lines[i].TaxRate
+		line.NetAmount = taxed.Lines[i].NetAmount
+		line.TaxAmount = taxed.Lines[i].TaxAmount
+		line.GrossAmount = taxed.Lines[i].GrossAmount
+	}
+	resp.PriceIncludesTax = taxed.PriceIncludesTax
+	resp.TaxExempt = taxed.Exempt
+	resp.NetAmount = taxed.NetAmount
+	resp.TaxAmount = taxed.TaxAmount
+	resp.GrossAmount = taxed.GrossAmount
+	resp.TaxBreakdown = taxed.Breakdown
+	return nil
+}
+
+// Redeem 在下单事务中扣减促销使用次数并记录优惠券核销
+func (p *promotionServiceImpl) Redeem(ctx *gin.Context, tx *gorm.DB, orderId string, resp *promotion_dto.EvaluateResp) error {
+	applied := make(map[string]*promotion_dto.AppliedDiscount)
+	amounts := make(map[string]util.Money)
+	ids := make([]string, 0)
+	for _, line := range resp.Lines {
+		for _, discount := range line.Discounts {
+			if _, ok := applied[discount.PromotionID]; !ok {
+				applied[discount.PromotionID] = discount
+				ids = append(ids, discount.PromotionID)
+			}
+			amounts[discount.PromotionID] += discount.Amount
+		}
+	}
+	for _, id := range ids {
+		ok, err := p.promotionRepo.IncrUsage(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return sm_error.NewHttpError(error_code.PromotionUsageExceeded)
+		}
+		discount := applied[id]
+		if discount.Type != promotion_dto.PromotionTypeCoupon {
+			continue
+		}
+		redemption, err := p.promotionRepo.GetRedemption(ctx, tx, id, resp.CustomerID)
+		if err != nil {
+			return err
+		}
+		if redemption != nil {
+			return sm_error.NewHttpError(error_code.PromotionCouponUsed)
+		}
+		err = p.promotionRepo.AddRedemption(ctx, tx, &promotion_dto.CouponRedemption{
+			PromotionID: id,
+			CustomerID:  resp.CustomerID,
+			OrderID:     orderId,
+			CouponCode:  discount.CouponCode,
+			Amount:      amounts[id],
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *promotionServiceImpl) buildLines(ctx *gin.Context, db *gorm.DB, items []*promotion_dto.EvaluateItem) ([]*promotion_dto.EvaluateLine, error) {
+	productIds := make([]string, 0, len(items))
+	for _, item := range items {
+		productIds = append(productIds, item.ProductID)
+	}
+	products, err := p.productRepo.GetByIds(ctx, db, productIds)
+	if err != nil {
+		return nil, err
+	}
+	productMap := make(map[string]*product_dto.Product)
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+	lines := make([]*promotion_dto.EvaluateLine, 0, len(items))
+	for _, item := range items {
+		product, ok := productMap[item.ProductID]
+		if !ok || item.Quantity <= 0 {
+			return nil, sm_error.NewHttpError(error_code.OrderProductNoExists)
+		}
+		// 始终按商品售价计价, 售价可能以其他币种定价, 统一换算为本位币
+		unitPrice, err := p.currencyService.Convert(ctx, product.BasePrice, product.SaleCurrency, "", time.Now())
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, &promotion_dto.EvaluateLine{
+			ProductID:      product.ID,
+			ProductName:    product.Name,
+			Category:       product.Category,
+			Quantity:       item.Quantity,
+			UnitPrice:      unitPrice,
+			OriginalAmount: unitPrice.Mul(item.Quantity),
+			Discounts:      make([]*promotion_dto.AppliedDiscount, 0),
+		})
+	}
+	return lines, nil
+}
+
+func checkRule(p *promotion_dto.Promotion) error {
+	if p.Rule == nil || !p.EndTime.After(p.StartTime) {
+		return sm_error.NewHttpError(error_code.PromotionRuleInvalid)
+	}
+	rule := p.Rule
+	switch p.Type {
+	case promotion_dto.PromotionTypePercentOff:
+		if rule.Percent <= 0 || rule.Percent > 100 {
+			return sm_error.NewHttpError(error_code.PromotionRuleInvalid, "折扣百分比需在0到100之间")
+		}
+	case promotion_dto.PromotionTypeBuyXGetY:
+		if rule.BuyQuantity <= 0 || rule.FreeQuantity <= 0 {
+			return sm_error.NewHttpError(error_code.PromotionRuleInvalid, "买赠数量必须大于0")
+		}
+	case promotion_dto.PromotionTypeTiered:
+		if len(rule.Tiers) == 0 {
+			return sm_error.NewHttpError(error_code.PromotionRuleInvalid, "阶梯折扣至少需要一个阶梯")
+		}
+		for _, tier := range rule.Tiers {
+			if tier.MinQuantity <= 0 || tier.Percent <= 0 || tier.Percent > 100 {
+				return sm_error.NewHttpError(error_code.PromotionRuleInvalid, "阶梯数量或折扣不正确")
+			}
+		}
+	case promotion_dto.PromotionTypeCoupon:
+		if rule.Amount <= 0 || strings.TrimSpace(p.CouponCode) == "" {
+			return sm_error.NewHttpError(error_code.PromotionRuleInvalid, "优惠券需要券码和面额")
+		}
+	default:
+		return sm_error.NewHttpError(error_code.PromotionRuleInvalid)
+	}
+	return nil
+}
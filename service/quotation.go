@@ -0,0 +1,18 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/dto/quotation_dto"
+)
+
+type QuotationService interface {
+	Add(ctx *gin.Context, req *quotation_dto.SaveQuotationReq) (*quotation_dto.Quotation, error)
+	Revise(ctx *gin.Context, req *quotation_dto.SaveQuotationReq) (*quotation_dto.Quotation, error)
+	Detail(ctx *gin.Context, quotationId string) (*quotation_dto.Quotation, error)
+	List(ctx *gin.Context, req *quotation_dto.QuotationListReq) (*quotation_dto.QuotationListResp, error)
+	Versions(ctx *gin.Context, quotationId string) ([]*quotation_dto.Quotation, error)
+	Convert(ctx *gin.Context, quotationId string) (*order_dto.SalesOrder, error)
+	Cancel(ctx *gin.Context, quotationId string) error
+	ExpireOverdue(ctx *gin.Context) error
+}
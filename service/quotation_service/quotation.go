@@ -0,0 +1,369 @@
+package quotation_service
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/dto/product_dto"
+	"github.com/shop_management/dto/quotation_dto"
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/order_repo"
+	"github.com/shop_management/repository/product_repo"
+	"github.com/shop_management/repository/quotation_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/currency_service"
+	"github.com/shop_management/service/tax_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"math/rand"
+	"time"
+)
+
+type quotationServiceImpl struct {
+	quotationRepo   repository.QuotationRepo
+	productRepo     repository.ProductRepo
+	orderRepo       repository.OrderRepo
+	taxService      service.TaxService
+	currencyService service.CurrencyService
+}
+
+func NewQuotationServiceImpl() service.QuotationService {
+	return &quotationServiceImpl{
+		quotationRepo:   quotation_repo.NewQuotationRepoImpl(),
+		productRepo:     product_repo.NewProductRepoImpl(),
+		orderRepo:       order_repo.NewOrderRepoImpl(),
+		taxService:      tax_service.NewTaxServiceImpl(),
+		currencyService: currency_service.NewCurrencyServiceImpl(),
+	}
+}
+
+func (q *quotationServiceImpl) Add(ctx *gin.Context, req *quotation_dto.SaveQuotationReq) (*quotation_dto.Quotation, error) {
+	quotation, err := q.build(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	quotation.QuotationNo = fmt.Sprintf("QT%s%04d", time.Now().Format("20060102150405"), rand.Intn(10000))
+	quotation.Version = 1
+	quotation.UserID = util.GetUserIdByCookie(ctx)
+
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = q.quotationRepo.Add(ctx, tx, quotation)
+	if err != nil {
+		return nil, err
+	}
+	err = q.holdStock(ctx, tx, quotation)
+	if err != nil {
+		return nil, err
+	}
+	return quotation, nil
+}
+
+// Revise 修订报价单: 保留原版本并标记为已被替代, 以相同报价单号生成新版本
+func (q *quotationServiceImpl) Revise(ctx *gin.Context, req *quotation_dto.SaveQuotationReq) (*quotation_dto.Quotation, error) {
+	current, err := q.getWithPermission(ctx, req.QuotationID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != quotation_dto.QuotationStatusActive {
+		return nil, sm_error.NewHttpError(error_code.QuotationStatusIncorrect)
+	}
+	quotation, err := q.build(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	quotation.QuotationNo = current.QuotationNo
+	quotation.Version = current.Version + 1
+	quotation.UserID = current.UserID
+
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	ok, err := q.quotationRepo.UpdateStatus(ctx, tx, current.ID, quotation_dto.QuotationStatusActive, quotation_dto.QuotationStatusSuperseded, "")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.QuotationStatusIncorrect)
+		return nil, err
+	}
+	err = q.releaseStock(ctx, tx, current)
+	if err != nil {
+		return nil, err
+	}
+	err = q.quotationRepo.Add(ctx, tx, quotation)
+	if err != nil {
+		return nil, err
+	}
+	err = q.holdStock(ctx, tx, quotation)
+	if err != nil {
+		return nil, err
+	}
+	return quotation, nil
+}
+
+func (q *quotationServiceImpl) Detail(ctx *gin.Context, quotationId string) (*quotation_dto.Quotation, error) {
+	return q.getWithPermission(ctx, quotationId)
+}
+
+func (q *quotationServiceImpl) List(ctx *gin.Context, req *quotation_dto.QuotationListReq) (*quotation_dto.QuotationListResp, error) {
+	list, err := q.quotationRepo.List(ctx, util.GetDBFromContext(ctx), req)
+	if err != nil {
+		return nil, err
+	}
+	return &quotation_dto.QuotationListResp{
+		Pager: req.Pager,
+		List:  list,
+	}, nil
+}
+
+func (q *quotationServiceImpl) Versions(ctx *gin.Context, quotationId string) ([]*quotation_dto.Quotation, error) {
+	quotation, err := q.getWithPermission(ctx, quotationId)
+	if err != nil {
+		return nil, err
+	}
+	return q.quotationRepo.ListVersions(ctx, util.GetDBFromContext(ctx), quotation.QuotationNo)
+}
+
+// Convert 将有效的报价单按报价价格转为草稿销售订单, 预留的库存同时释放
+func (q *quotationServiceImpl) Convert(ctx *gin.Context, quotationId string) (*order_dto.SalesOrder, error) {
+	quotation, err := q.getWithPermission(ctx, quotationId)
+	if err != nil {
+		return nil, err
+	}
+	if quotation.Status != quotation_dto.QuotationStatusActive {
+		return nil, sm_error.NewHttpError(error_code.QuotationStatusIncorrect)
+	}
+	if quotation.ValidUntil.Before(time.Now()) {
+		return nil, sm_error.NewHttpError(error_code.QuotationExpired)
+	}
+	order := &order_dto.SalesOrder{
+		OrderNo:      fmt.Sprintf("SO%s%04d", time.Now().Format("20060102150405"), rand.Intn(10000)),
+		UserID:       quotation.UserID,
+		CustomerID:   quotation.CustomerID,
+		CustomerName: quotation.CustomerName,
+		Remark:       "报价单 " + quotation.QuotationNo + " 转入",
+		Status:       order_dto.OrderStatusDraft,
+		Currency:     quotation.Currency,
+		TotalAmount:  quotation.TotalAmount,
+		NetAmount:    quotation.NetAmount,
+		TaxAmount:    quotation.TaxAmount,
+		Items:        make([]*order_dto.SalesOrderItem, 0, len(quotation.Items)),
+	}
+	for _, item := range quotation.Items {
+		order.Items = append(order.Items, &order_dto.SalesOrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			Amount:    item.Amount,
+			TaxRate:   item.TaxRate,
+			NetAmount: item.NetAmount,
+			TaxAmount: item.TaxAmount,
+		})
+	}
+
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = q.orderRepo.Add(ctx, tx, order)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := q.quotationRepo.UpdateStatus(ctx, tx, quotation.ID, quotation_dto.QuotationStatusActive, quotation_dto.QuotationStatusConverted, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.QuotationStatusIncorrect)
+		return nil, err
+	}
+	err = q.releaseStock(ctx, tx, quotation)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (q *quotationServiceImpl) Cancel(ctx *gin.Context, quotationId string) error {
+	quotation, err := q.getWithPermission(ctx, quotationId)
+	if err != nil {
+		return err
+	}
+	return q.close(ctx, quotation, quotation_dto.QuotationStatusCanceled)
+}
+
+// ExpireOverdue 由定时任务调用, 将超过有效期的报价单置为过期并释放预留库存
+func (q *quotationServiceImpl) ExpireOverdue(ctx *gin.Context) error {
+	list, err := q.quotationRepo.ListExpired(ctx, util.GetDBFromContext(ctx), time.Now())
+	if err != nil {
+		return err
+	}
+	for _, quotation := range list {
+		err = q.close(ctx, quotation, quotation_dto.QuotationStatusExpired)
+		if err != nil {
+			vars.Log.Errorf("quotationServiceImpl.ExpireOverdue error:%v,quotation: %v", err, quotation.ID)
+		}
+	}
+	return nil
+}
+
+func (q *quotationServiceImpl) close(ctx *gin.Context, quotation *quotation_dto.Quotation, toStatus int) (err error) {
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	ok, err := q.quotationRepo.UpdateStatus(ctx, tx, quotation.ID, quotation_dto.QuotationStatusActive, toStatus, "")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.QuotationStatusIncorrect)
+		return err
+	}
+	return q.releaseStock(ctx, tx, quotation)
+}
+
+func (q *quotationServiceImpl) build(ctx *gin.Context, req *quotation_dto.SaveQuotationReq) (*quotation_dto.Quotation, error) {
+	if len(req.Items) == 0 {
+		return nil, sm_error.NewHttpError(error_code.QuotationItemEmpty)
+	}
+	if !req.ValidUntil.After(time.Now()) {
+		return nil, sm_error.NewHttpError(error_code.QuotationExpired)
+	}
+	productIds := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		productIds = append(productIds, item.ProductID)
+	}
+	products, err := q.productRepo.GetByIds(ctx, util.GetDBFromContext(ctx), productIds)
+	if err != nil {
+		return nil, err
+	}
+	productMap := make(map[string]*product_dto.Product)
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+	currency, err := q.currencyService.GetBaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quotation := &quotation_dto.Quotation{
+		CustomerID:   req.CustomerID,
+		Currency:     currency,
+		CustomerName: req.CustomerName,
+		Remark:       req.Remark,
+		Status:       quotation_dto.QuotationStatusActive,
+		HoldStock:    req.HoldStock,
+		ValidUntil:   req.ValidUntil,
+		Items:        make([]*quotation_dto.QuotationItem, 0, len(req.Items)),
+	}
+	taxLines := make([]*tax_dto.CalculateLine, 0, len(req.Items))
+	for _, item := range req.Items {
+		product, ok := productMap[item.ProductID]
+		if !ok || item.Quantity <= 0 {
+			return nil, sm_error.NewHttpError(error_code.OrderProductNoExists)
+		}
+		unitPrice := item.UnitPrice
+		if unitPrice <= 0 {
+			unitPrice, err = q.currencyService.Convert(ctx, product.BasePrice, product.SaleCurrency, currency, time.Now())
+			if err != nil {
+				return nil, err
+			}
+		}
+		quotation.Items = append(quotation.Items, &quotation_dto.QuotationItem{
+			ProductID: product.ID,
+			Quantity:  item.Quantity,
+			UnitPrice: unitPrice,
+		})
+		taxLines = append(taxLines, &tax_dto.CalculateLine{
+			ProductID: product.ID,
+			Category:  product.Category,
+			Amount:    unitPrice.Mul(item.Quantity),
+		})
+	}
+	taxed, err := q.taxService.Calculate(ctx, &tax_dto.CalculateReq{
+		CustomerID: req.CustomerID,
+		Lines:      taxLines,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range quotation.Items {
+		item.TaxRate = taxed.Lines[i].TaxRate
+		item.NetAmount = taxed.Lines[i].NetAmount
+		item.TaxAmount = taxed.Lines[i].TaxAmount
+		item.Amount = taxed.Lines[i].GrossAmount
+	}
+	quotation.NetAmount = taxed.NetAmount
+	quotation.TaxAmount = taxed.TaxAmount
+	quotation.TotalAmount = taxed.GrossAmount
+	return quotation, nil
+}
+
+// holdStock 软预留库存, 只占用可用库存(stock - held_nums), 不实际扣减
+func (q *quotationServiceImpl) holdStock(ctx *gin.Context, tx *gorm.DB, quotation *quotation_dto.Quotation) error {
+	if !quotation.HoldStock {
+		return nil
+	}
+	for _, item := range quotation.Items {
+		ok, err := q.productRepo.HoldStock(ctx, tx, item.ProductID, item.Quantity)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return sm_error.NewHttpError(error_code.QuotationStockInsufficient)
+		}
+	}
+	return nil
+}
+
+func (q *quotationServiceImpl) releaseStock(ctx *gin.Context, tx *gorm.DB, quotation *quotation_dto.Quotation) error {
+	if !quotation.HoldStock {
+		return nil
+	}
+	for _, item := range quotation.Items {
+		err := q.productRepo.ReleaseStock(ctx, tx, item.ProductID, item.Quantity)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getWithPermission 与订单相同, 报价单属于当前团队即可操作, 具体能做什么由角色权限决定
+func (q *quotationServiceImpl) getWithPermission(ctx *gin.Context, quotationId string) (*quotation_dto.Quotation, error) {
+	quotation, err := q.quotationRepo.GetById(ctx, util.GetDBFromContext(ctx), quotationId)
+	if err != nil {
+		return nil, err
+	}
+	if quotation == nil {
+		return nil, sm_error.NewHttpError(error_code.QuotationNoExists)
+	}
+	if quotation.TeamID != util.GetTeamId(ctx) {
+		return nil, sm_error.NewHttpError(error_code.QuotationNoPermission)
+	}
+	return quotation, nil
+}
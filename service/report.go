@@ -0,0 +1,11 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/report_dto"
+)
+
+type ReportService interface {
+	Sales(ctx *gin.Context, req *report_dto.SalesReportReq) (*report_dto.SalesReport, error)
+	InventoryValuation(ctx *gin.Context) (*report_dto.InventoryValuation, error)
+}
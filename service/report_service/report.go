@@ -0,0 +1,145 @@
+package report_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/order_dto"
+	"github.com/shop_management/dto/report_dto"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/order_repo"
+	"github.com/shop_management/repository/product_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/auth_service"
+	"github.com/shop_management/service/currency_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"time"
+)
+
+type reportServiceImpl struct {
+	orderRepo       repository.OrderRepo
+	productRepo     repository.ProductRepo
+	currencyService service.CurrencyService
+	authService     service.AuthService
+}
+
+func NewReportServiceImpl() service.ReportService {
+	return &reportServiceImpl{
+		orderRepo:       order_repo.NewOrderRepoImpl(),
+		productRepo:     product_repo.NewProductRepoImpl(),
+		currencyService: currency_service.NewCurrencyServiceImpl(),
+		authService:     auth_service.NewAuthServiceImpl(),
+	}
+}
+
+// Sales 统计团队内已确认(含门店收银)的订单, 草稿和已取消的订单不计入
+func (r *reportServiceImpl) Sales(ctx *gin.Context, req *report_dto.SalesReportReq) (*report_dto.SalesReport, error) {
+	base, err := r.currencyService.GetBaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := r.orderRepo.ListByCreateTime(ctx, util.GetDBFromContext(ctx), req.StartTime, req.EndTime, []int{
+		order_dto.OrderStatusConfirmed,
+		order_dto.OrderStatusPicking,
+		order_dto.OrderStatusPicked,
+		order_dto.OrderStatusPacked,
+		order_dto.OrderStatusShipped,
+		order_dto.OrderStatusCompleted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	report := &report_dto.SalesReport{
+		Currency: base,
+		Days:     make([]*report_dto.SalesReportDay, 0),
+	}
+	dayMap := make(map[string]*report_dto.SalesReportDay)
+	for _, order := range orders {
+		netAmount, err := r.currencyService.Convert(ctx, order.NetAmount, order.Currency, base, order.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		taxAmount, err := r.currencyService.Convert(ctx, order.TaxAmount, order.Currency, base, order.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		totalAmount, err := r.currencyService.Convert(ctx, order.TotalAmount, order.Currency, base, order.CreateTime)
+		if err != nil {
+			return nil, err
+		}
+		date := order.CreateTime.Format("2006-01-02")
+		day, ok := dayMap[date]
+		if !ok {
+			day = &report_dto.SalesReportDay{Date: date}
+			dayMap[date] = day
+			report.Days = append(report.Days, day)
+		}
+		day.OrderCount++
+		day.NetAmount += netAmount
+		day.TaxAmount += taxAmount
+		day.TotalAmount += totalAmount
+		report.OrderCount++
+		report.NetAmount += netAmount
+		report.TaxAmount += taxAmount
+		report.TotalAmount += totalAmount
+	}
+	return report, nil
+}
+
+// InventoryValuation 优先使用成本价, 未填写成本价时使用采购价. 整张报表都由成本计算,
+// 没有查看成本权限时拒绝访问; 有仓库授权的子账号只统计被授权的仓库
+func (r *reportServiceImpl) InventoryValuation(ctx *gin.Context) (*report_dto.InventoryValuation, error) {
+	canViewCost, err := r.authService.CanViewCost(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewCost {
+		return nil, sm_error.NewHttpError(error_code.UserNoPermission, "没有查看成本价的权限")
+	}
+	warehouses, restricted, err := r.authService.WarehouseScope(ctx, user_dto.ActionRead)
+	if err != nil {
+		return nil, err
+	}
+	if !restricted {
+		warehouses = nil
+	}
+	base, err := r.currencyService.GetBaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	products, err := r.productRepo.ListInStock(ctx, util.GetDBFromContext(ctx), warehouses)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	valuation := &report_dto.InventoryValuation{
+		Currency: base,
+		Date:     now.Format("2006-01-02"),
+		Lines:    make([]*report_dto.InventoryValuationLine, 0, len(products)),
+	}
+	for _, product := range products {
+		unitCost := product.CostPrice
+		if unitCost <= 0 {
+			unitCost = product.PurchasePrice
+		}
+		amount, err := r.currencyService.Convert(ctx, unitCost.Mul(product.Stock), product.PurchaseCurrency, base, now)
+		if err != nil {
+			return nil, err
+		}
+		unitCostCurrency := product.PurchaseCurrency
+		if unitCostCurrency == "" {
+			unitCostCurrency = base
+		}
+		valuation.Lines = append(valuation.Lines, &report_dto.InventoryValuationLine{
+			ProductID:        product.ID,
+			Name:             product.Name,
+			Stock:            product.Stock,
+			UnitCost:         unitCost,
+			UnitCostCurrency: unitCostCurrency,
+			Amount:           amount,
+		})
+		valuation.TotalAmount += amount
+	}
+	return valuation, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/tax_dto"
+)
+
+type TaxService interface {
+	SaveRule(ctx *gin.Context, dto *tax_dto.TaxRule) (*tax_dto.TaxRule, error)
+	ListRules(ctx *gin.Context) ([]*tax_dto.TaxRule, error)
+	DeleteRule(ctx *gin.Context, id string) error
+	GetSetting(ctx *gin.Context) (*tax_dto.TaxSetting, error)
+	SaveSetting(ctx *gin.Context, dto *tax_dto.TaxSetting) error
+	AddExemption(ctx *gin.Context, dto *tax_dto.TaxExemption) (*tax_dto.TaxExemption, error)
+	ListExemptions(ctx *gin.Context) ([]*tax_dto.TaxExemption, error)
+	DeleteExemption(ctx *gin.Context, customerId string) error
+	// Calculate 所有计价流程(订单, 收银, 报价)统一通过该方法计算税额
+	Calculate(ctx *gin.Context, req *tax_dto.CalculateReq) (*tax_dto.CalculateResp, error)
+}
@@ -0,0 +1,63 @@
+package tax_service
+
+import (
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/util"
+)
+
+// calculate 按类目匹配税率, 类目未配置时使用默认税率(Category 为空的规则).
+// 含税价: 不含税金额 = 行金额 / (1 + 税率), 税额为差额; 免税客户只需支付不含税金额.
+// 不含税价: 税额 = 行金额 * 税率, 免税客户税额为 0.
+func calculate(rules []*tax_dto.TaxRule, setting *tax_dto.TaxSetting, exempt bool, lines []*tax_dto.CalculateLine) *tax_dto.CalculateResp {
+	rateMap := make(map[string]float64)
+	for _, rule := range rules {
+		rateMap[rule.Category] = rule.Rate
+	}
+	resp := &tax_dto.CalculateResp{
+		PriceIncludesTax: setting.PriceIncludesTax,
+		Exempt:           exempt,
+		Lines:            make([]*tax_dto.TaxLine, 0, len(lines)),
+		Breakdown:        make([]*tax_dto.TaxBreakdown, 0),
+	}
+	breakdownMap := make(map[float64]*tax_dto.TaxBreakdown)
+	for _, line := range lines {
+		rate, ok := rateMap[line.Category]
+		if !ok {
+			rate = rateMap[""]
+		}
+		netAmount := line.Amount
+		taxAmount := util.Money(0)
+		if setting.PriceIncludesTax {
+			netAmount = line.Amount.MulRate(1 / (1 + rate/100))
+			taxAmount = line.Amount - netAmount
+		} else {
+			taxAmount = line.Amount.MulRate(rate / 100)
+		}
+		if exempt {
+			rate = 0
+			taxAmount = 0
+		}
+		taxLine := &tax_dto.TaxLine{
+			ProductID:   line.ProductID,
+			Category:    line.Category,
+			TaxRate:     rate,
+			NetAmount:   netAmount,
+			TaxAmount:   taxAmount,
+			GrossAmount: netAmount + taxAmount,
+		}
+		resp.Lines = append(resp.Lines, taxLine)
+		resp.NetAmount += taxLine.NetAmount
+		resp.TaxAmount += taxLine.TaxAmount
+		resp.GrossAmount += taxLine.GrossAmount
+
+		breakdown, ok := breakdownMap[rate]
+		if !ok {
+			breakdown = &tax_dto.TaxBreakdown{TaxRate: rate}
+			breakdownMap[rate] = breakdown
+			resp.Breakdown = append(resp.Breakdown, breakdown)
+		}
+		breakdown.NetAmount += taxLine.NetAmount
+		breakdown.TaxAmount += taxLine.TaxAmount
+	}
+	return resp
+}
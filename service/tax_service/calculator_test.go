@@ -0,0 +1,118 @@
+package tax_service
+
+import (
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/util"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	rules := []*tax_dto.TaxRule{
+		{Category: "", Rate: 13},
+		{Category: "food", Rate: 9},
+		{Category: "book", Rate: 0},
+	}
+	tests := []struct {
+		name             string
+		priceIncludesTax bool
+		exempt           bool
+		lines            []*tax_dto.CalculateLine
+		wantLines        [][3]util.Money // net, tax, gross
+		wantRates        []float64
+		wantTotal        [3]util.Money
+		wantBreakdown    int
+	}{
+		{
+			name:          "exclusive default rate",
+			lines:         []*tax_dto.CalculateLine{{Category: "toy", Amount: 10000}},
+			wantLines:     [][3]util.Money{{10000, 1300, 11300}},
+			wantRates:     []float64{13},
+			wantTotal:     [3]util.Money{10000, 1300, 11300},
+			wantBreakdown: 1,
+		},
+		{
+			name:          "exclusive by category",
+			lines:         []*tax_dto.CalculateLine{{Category: "food", Amount: 10000}, {Category: "book", Amount: 5000}},
+			wantLines:     [][3]util.Money{{10000, 900, 10900}, {5000, 0, 5000}},
+			wantRates:     []float64{9, 0},
+			wantTotal:     [3]util.Money{15000, 900, 15900},
+			wantBreakdown: 2,
+		},
+		{
+			name:             "inclusive splits gross",
+			priceIncludesTax: true,
+			lines:            []*tax_dto.CalculateLine{{Category: "toy", Amount: 11300}},
+			wantLines:        [][3]util.Money{{10000, 1300, 11300}},
+			wantRates:        []float64{13},
+			wantTotal:        [3]util.Money{10000, 1300, 11300},
+			wantBreakdown:    1,
+		},
+		{
+			name:             "inclusive rounds net to cents",
+			priceIncludesTax: true,
+			lines:            []*tax_dto.CalculateLine{{Category: "food", Amount: 999}},
+			wantLines:        [][3]util.Money{{917, 82, 999}},
+			wantRates:        []float64{9},
+			wantTotal:        [3]util.Money{917, 82, 999},
+			wantBreakdown:    1,
+		},
+		{
+			name:          "exclusive exempt",
+			exempt:        true,
+			lines:         []*tax_dto.CalculateLine{{Category: "toy", Amount: 10000}},
+			wantLines:     [][3]util.Money{{10000, 0, 10000}},
+			wantRates:     []float64{0},
+			wantTotal:     [3]util.Money{10000, 0, 10000},
+			wantBreakdown: 1,
+		},
+		{
+			name:             "inclusive exempt pays net only",
+			priceIncludesTax: true,
+			exempt:           true,
+			lines:            []*tax_dto.CalculateLine{{Category: "toy", Amount: 11300}},
+			wantLines:        [][3]util.Money{{10000, 0, 10000}},
+			wantRates:        []float64{0},
+			wantTotal:        [3]util.Money{10000, 0, 10000},
+			wantBreakdown:    1,
+		},
+		{
+			name:          "same rate merged in breakdown",
+			lines:         []*tax_dto.CalculateLine{{Category: "toy", Amount: 1000}, {Category: "", Amount: 2000}},
+			wantLines:     [][3]util.Money{{1000, 130, 1130}, {2000, 260, 2260}},
+			wantRates:     []float64{13, 13},
+			wantTotal:     [3]util.Money{3000, 390, 3390},
+			wantBreakdown: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := calculate(rules, &tax_dto.TaxSetting{PriceIncludesTax: tt.priceIncludesTax}, tt.exempt, tt.lines)
+			if len(resp.Lines) != len(tt.wantLines) {
+				t.Fatalf("lines = %d, want %d", len(resp.Lines), len(tt.wantLines))
+			}
+			for i, line := range resp.Lines {
+				got := [3]util.Money{line.NetAmount, line.TaxAmount, line.GrossAmount}
+				if got != tt.wantLines[i] {
+					t.Errorf("line %d net/tax/gross = %v, want %v", i, got, tt.wantLines[i])
+				}
+				if line.TaxRate != tt.wantRates[i] {
+					t.Errorf("line %d rate = %v, want %v", i, line.TaxRate, tt.wantRates[i])
+				}
+			}
+			total := [3]util.Money{resp.NetAmount, resp.TaxAmount, resp.GrossAmount}
+			if total != tt.wantTotal {
+				t.Errorf("total net/tax/gross = %v, want %v", total, tt.wantTotal)
+			}
+			if len(resp.Breakdown) != tt.wantBreakdown {
+				t.Errorf("breakdown = %d, want %d", len(resp.Breakdown), tt.wantBreakdown)
+			}
+			var breakdownTax util.Money
+			for _, b := range resp.Breakdown {
+				breakdownTax += b.TaxAmount
+			}
+			if breakdownTax != resp.TaxAmount {
+				t.Errorf("breakdown tax = %v, want %v", breakdownTax, resp.TaxAmount)
+			}
+		})
+	}
+}
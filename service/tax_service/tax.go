@@ -0,0 +1,118 @@
+package tax_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/tax_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/tax_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"strings"
+)
+
+type taxServiceImpl struct {
+	taxRepo repository.TaxRepo
+}
+
+func NewTaxServiceImpl() service.TaxService {
+	return &taxServiceImpl{
+		taxRepo: tax_repo.NewTaxRepoImpl(),
+	}
+}
+
+func (t *taxServiceImpl) SaveRule(ctx *gin.Context, dto *tax_dto.TaxRule) (*tax_dto.TaxRule, error) {
+	if dto.Rate < 0 || dto.Rate > 100 {
+		return nil, sm_error.NewHttpError(error_code.TaxRuleInvalid)
+	}
+	dto.UserID = util.GetTeamId(ctx)
+	dto.Category = strings.TrimSpace(dto.Category)
+	err := t.taxRepo.SaveRule(ctx, util.GetDBFromContext(ctx), dto)
+	if err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+func (t *taxServiceImpl) ListRules(ctx *gin.Context) ([]*tax_dto.TaxRule, error) {
+	return t.taxRepo.ListRules(ctx, util.GetDBFromContext(ctx), util.GetTeamId(ctx))
+}
+
+func (t *taxServiceImpl) DeleteRule(ctx *gin.Context, id string) error {
+	db := util.GetDBFromContext(ctx)
+	rule, err := t.taxRepo.GetRuleById(ctx, db, id)
+	if err != nil {
+		return err
+	}
+	if rule == nil || rule.UserID != util.GetTeamId(ctx) {
+		return sm_error.NewHttpError(error_code.TaxRuleNoExists)
+	}
+	return t.taxRepo.DeleteRule(ctx, db, id)
+}
+
+func (t *taxServiceImpl) GetSetting(ctx *gin.Context) (*tax_dto.TaxSetting, error) {
+	return t.taxRepo.GetSetting(ctx, util.GetDBFromContext(ctx), util.GetTeamId(ctx))
+}
+
+func (t *taxServiceImpl) SaveSetting(ctx *gin.Context, dto *tax_dto.TaxSetting) error {
+	dto.UserID = util.GetTeamId(ctx)
+	return t.taxRepo.SaveSetting(ctx, util.GetDBFromContext(ctx), dto)
+}
+
+func (t *taxServiceImpl) AddExemption(ctx *gin.Context, dto *tax_dto.TaxExemption) (*tax_dto.TaxExemption, error) {
+	db := util.GetDBFromContext(ctx)
+	dto.UserID = util.GetTeamId(ctx)
+	dto.CustomerID = strings.TrimSpace(dto.CustomerID)
+	exists, err := t.taxRepo.GetExemption(ctx, db, dto.UserID, dto.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if exists != nil {
+		return nil, sm_error.NewHttpError(error_code.TaxExemptionExists)
+	}
+	err = t.taxRepo.AddExemption(ctx, db, dto)
+	if err != nil {
+		return nil, err
+	}
+	return dto, nil
+}
+
+func (t *taxServiceImpl) ListExemptions(ctx *gin.Context) ([]*tax_dto.TaxExemption, error) {
+	return t.taxRepo.ListExemptions(ctx, util.GetDBFromContext(ctx), util.GetTeamId(ctx))
+}
+
+func (t *taxServiceImpl) DeleteExemption(ctx *gin.Context, customerId string) error {
+	db := util.GetDBFromContext(ctx)
+	userId := util.GetTeamId(ctx)
+	exists, err := t.taxRepo.GetExemption(ctx, db, userId, customerId)
+	if err != nil {
+		return err
+	}
+	if exists == nil {
+		return sm_error.NewHttpError(error_code.TaxExemptionNoExists)
+	}
+	return t.taxRepo.DeleteExemption(ctx, db, userId, customerId)
+}
+
+func (t *taxServiceImpl) Calculate(ctx *gin.Context, req *tax_dto.CalculateReq) (*tax_dto.CalculateResp, error) {
+	db := util.GetDBFromContext(ctx)
+	userId := util.GetTeamId(ctx)
+	rules, err := t.taxRepo.ListRules(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	setting, err := t.taxRepo.GetSetting(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	exempt := false
+	if req.CustomerID != "" {
+		exemption, err := t.taxRepo.GetExemption(ctx, db, userId, req.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		exempt = exemption != nil
+	}
+	return calculate(rules, setting, exempt, req.Lines), nil
+}
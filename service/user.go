@@ -3,24 +3,60 @@ package service
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/shop_management/dto/user_dto"
+	"mime/multipart"
 )
 
 type UserService interface {
 	Register(ctx *gin.Context, req *user_dto.RegisterUserReq) error
-	Login(ctx *gin.Context, req *user_dto.UserLogin) (string, error)
-	//ModifyPwd(ctx *gin.Context, req *user_dto.ModifyUserPasswordReq) error
+	Login(ctx *gin.Context, req *user_dto.UserLogin) (*user_dto.LoginResult, error)
+	LoginVerify(ctx *gin.Context, req *user_dto.LoginVerifyReq) (string, error)
+	ModifyPwd(ctx *gin.Context, req *user_dto.ModifyUserPasswordReq) error
+	SendResetCode(ctx *gin.Context, req *user_dto.SendResetCodeReq) error
+	ResetPassword(ctx *gin.Context, req *user_dto.ResetPasswordReq) error
 	GetUserProfile(ctx *gin.Context, userId string) (*user_dto.UserProfile, error)
-	//SaveUserProfile(ctx *gin.Context, req *user_dto.UserProfile) error
-	//Deactivate(ctx *gin.Context, userId string) error
-	//GenerateApiSecret(ctx *gin.Context, req *user_dto.GenerateApiSecret) error
-	//GetApiSecret(ctx *gin.Context, userId string) (*user_dto.GetApiSecret, error)
-	//UserList(ctx *gin.Context, req *user_dto.UserListReq) (*user_dto.UserListResp, error)
-	//SaveUser(ctx *gin.Context, req *user_dto.User) error
-	//GetUserIdBySecret(ctx *gin.Context, key string, secret string) (string, error)
+	ListSession(ctx *gin.Context) ([]*user_dto.Session, error)
+	RevokeSession(ctx *gin.Context, req *user_dto.RevokeSessionReq) error
+	RevokeAllSession(ctx *gin.Context) error
+	SaveUserProfile(ctx *gin.Context, req *user_dto.SaveUserProfileReq) error
+	UploadAvatar(ctx *gin.Context, file *multipart.FileHeader) (string, error)
+	OidcAuthURL(ctx *gin.Context) (string, error)
+	OidcLogin(ctx *gin.Context, req *user_dto.OidcLoginReq) (*user_dto.LoginResult, error)
+	OidcLinkURL(ctx *gin.Context) (string, error)
+	OidcLink(ctx *gin.Context, req *user_dto.OidcLoginReq) error
 }
 
 type UserTeamService interface {
 	SubUserList(ctx *gin.Context, req *user_dto.SubUserListReq) (*user_dto.SubUserListResp, error)
 	AddSubUser(ctx *gin.Context, req *user_dto.AddSubUserReq) error
 	DelSubUser(ctx *gin.Context, req *user_dto.DelSubUserReq) error
+	AssignRole(ctx *gin.Context, req *user_dto.AssignRoleReq) error
+	ForceLogout(ctx *gin.Context, req *user_dto.ForceLogoutReq) error
+	LockoutList(ctx *gin.Context, req *user_dto.LoginLockoutListReq) (*user_dto.LoginLockoutListResp, error)
+	GrantList(ctx *gin.Context, req *user_dto.GrantListReq) ([]*user_dto.UserGrant, error)
+	AddGrant(ctx *gin.Context, req *user_dto.AddGrantReq) (*user_dto.UserGrant, error)
+	DelGrant(ctx *gin.Context, req *user_dto.DelGrantReq) error
+}
+
+type UserInvitationService interface {
+	Invite(ctx *gin.Context, req *user_dto.InviteReq) (*user_dto.Invitation, error)
+	List(ctx *gin.Context, req *user_dto.InvitationListReq) (*user_dto.InvitationListResp, error)
+	Detail(ctx *gin.Context, token string) (*user_dto.Invitation, error)
+	Accept(ctx *gin.Context, token string) error
+	Decline(ctx *gin.Context, token string) error
+}
+
+type UserTwoFactorService interface {
+	Enroll(ctx *gin.Context) (*user_dto.TwoFactorEnrollment, error)
+	Activate(ctx *gin.Context, req *user_dto.TwoFactorCodeReq) ([]string, error)
+	Disable(ctx *gin.Context, req *user_dto.TwoFactorCodeReq) error
+	RegenerateRecoveryCodes(ctx *gin.Context, req *user_dto.TwoFactorCodeReq) ([]string, error)
+}
+
+type UserDeactivationService interface {
+	Deactivate(ctx *gin.Context, req *user_dto.DeactivateReq) error
+	DeactivateMember(ctx *gin.Context, req *user_dto.DeactivateMemberReq) error
+	DeactivatedList(ctx *gin.Context) ([]*user_dto.DeactivatedUser, error)
+	Reactivate(ctx *gin.Context, req *user_dto.ReactivateReq) error
+	ReactivateSelf(ctx *gin.Context, req *user_dto.UserLogin) error
+	PurgeExpired(ctx *gin.Context) error
 }
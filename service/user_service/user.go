@@ -1,32 +1,75 @@
 package user_service
 
 import (
+	cryptorand "crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/shop_management/dto/file_dto"
 	"github.com/shop_management/dto/user_dto"
 	"github.com/shop_management/redis/user_redis"
 	"github.com/shop_management/repository"
 	"github.com/shop_management/repository/user_repo"
 	"github.com/shop_management/service"
+	"github.com/shop_management/service/auth_service"
+	"github.com/shop_management/service/file_service"
+	"github.com/shop_management/service/message_service"
 	"github.com/shop_management/sm_error"
 	"github.com/shop_management/sm_error/error_code"
 	"github.com/shop_management/util"
-	"math/rand"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"io"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
 )
 
 type userServiceImpl struct {
-	userRepo repository.UserRepo
+	userRepo          repository.UserRepo
+	loginLockoutRepo  repository.LoginLockoutRepo
+	messageSender     service.MessageSender
+	fileService       service.FileServiceInterface
+	authService       service.AuthService
+	invitationService *userInvitationServiceImpl
+	twoFactorService  *userTwoFactorServiceImpl
+	userIdentityRepo  repository.UserIdentityRepo
+	oidcConfig        *util.OidcConfig
+	oidcClient        *util.OidcClient
 }
 
 func NewUserServiceImpl() service.UserService {
+	oidcConfig, oidcClient := loadOidc()
 	return &userServiceImpl{
-		userRepo: user_repo.NewUserRepoImpl(),
+		userRepo:          user_repo.NewUserRepoImpl(),
+		loginLockoutRepo:  user_repo.NewLoginLockoutRepoImpl(),
+		messageSender:     message_service.NewMessageSender(),
+		fileService:       file_service.NewFileService(),
+		authService:       auth_service.NewAuthServiceImpl(),
+		invitationService: newUserInvitationServiceImpl(),
+		twoFactorService:  newUserTwoFactorServiceImpl(),
+		userIdentityRepo:  user_repo.NewUserIdentityRepoImpl(),
+		oidcConfig:        oidcConfig,
+		oidcClient:        oidcClient,
 	}
 }
 
 const tokenExpireTime = 3600 * 24 * 3
 
+// avatarMaxSize 头像最大 2MB
+const avatarMaxSize = 2 << 20
+
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
 func (u *userServiceImpl) Register(ctx *gin.Context, req *user_dto.RegisterUserReq) error {
 	var err error
 	tx := util.GetDBFromContext(ctx).Begin()
@@ -47,218 +90,468 @@ func (u *userServiceImpl) Register(ctx *gin.Context, req *user_dto.RegisterUserR
 	if req.Password != req.ConfirmPassword {
 		return sm_error.NewHttpError(error_code.UserConfirmPasswordIncorrect)
 	}
-	err = u.userRepo.Add(ctx, tx, &user_dto.User{
+	password, err := util.HashPassword(req.Password)
+	if err != nil {
+		vars.Log.Errorf("userServiceImpl.Register hash password error:%v", err)
+		return sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	user := &user_dto.User{
 		Id:       uuid.New().String(),
 		Name:     req.Name,
 		Phone:    req.Phone,
-		Password: req.Password,
-	})
+		Password: password,
+	}
+	// 通过邀请链接注册, 邮箱邀请以邀请中的邮箱作为账号邮箱, 注册后直接加入团队
+	var invitation *user_dto.Invitation
+	if req.InviteToken != "" {
+		invitation, err = u.invitationService.parse(ctx, tx, req.InviteToken)
+		if err != nil {
+			return err
+		}
+		if invitation.Phone == "" {
+			user.Email = invitation.Email
+		}
+	}
+	err = u.userRepo.Add(ctx, tx, user)
 	if err != nil {
 		return err
 	}
+	if invitation != nil {
+		err = u.invitationService.accept(ctx, tx, invitation, user)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (u *userServiceImpl) Login(ctx *gin.Context, req *user_dto.UserLogin) (string, error) {
+// Login 同一手机号或 IP 连续登录失败后需等待一段时间再试, 失败过多时临时锁定.
+// 开启两步验证的用户密码校验通过后不设置 cookie, 需通过 LoginVerify 校验动态码后登录
+func (u *userServiceImpl) Login(ctx *gin.Context, req *user_dto.UserLogin) (*user_dto.LoginResult, error) {
+	err := user_redis.CheckLoginAllowed(ctx, req.Phone, ctx.ClientIP())
+	if err != nil {
+		return nil, err
+	}
 	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
 	accountDetail, err := u.userRepo.GetByPhone(ctx, tx, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	if accountDetail == nil {
+		err = u.loginFailed(ctx, req.Phone, nil, sm_error.NewHttpError(error_code.UserLoginFailed))
+		return nil, err
+	}
+	ok, needsRehash := util.VerifyPassword(accountDetail.Password, req.Password)
+	if !ok {
+		err = u.loginFailed(ctx, req.Phone, accountDetail, sm_error.NewHttpError(error_code.UserLoginFailed))
+		return nil, err
+	}
+	// 密码正确后才提示账号已停用, 避免通过登录接口探测账号状态
+	if accountDetail.IsDeactivated() {
+		err = sm_error.NewHttpError(error_code.UserDeactivated)
+		return nil, err
+	}
+	if accountDetail.IsDisabled() {
+		err = sm_error.NewHttpError(error_code.UserDisabled)
+		return nil, err
+	}
+	// 旧的明文密码或哈希参数已调整的记录, 登录成功后重新计算哈希, 失败不影响本次登录
+	if needsRehash {
+		_ = u.savePassword(ctx, tx, accountDetail.Id, req.Password)
+	}
+	twoFactorEnabled, err := u.twoFactorService.enabled(ctx, tx, accountDetail.Id)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactorEnabled {
+		var challengeToken string
+		challengeToken, err = generateRandomToken()
+		if err != nil {
+			return nil, err
+		}
+		err = user_redis.SetLoginChallenge(ctx, challengeToken, accountDetail.Id, req.Phone)
+		if err != nil {
+			return nil, err
+		}
+		return &user_dto.LoginResult{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
+	}
+	user_redis.ClearLoginFailure(ctx, req.Phone)
+	err = u.createSession(ctx, accountDetail.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &user_dto.LoginResult{UserId: accountDetail.Id}, nil
+}
+
+// LoginVerify 两步登录的第二步, 校验动态码或恢复码. 错误次数计入该手机号的登录失败次数
+func (u *userServiceImpl) LoginVerify(ctx *gin.Context, req *user_dto.LoginVerifyReq) (string, error) {
+	userId, phone, err := user_redis.GetLoginChallenge(ctx, req.ChallengeToken)
+	if err != nil {
+		return "", err
+	}
+	err = user_redis.CheckLoginAllowed(ctx, phone, ctx.ClientIP())
+	if err != nil {
+		return "", err
+	}
+	usedRecoveryCode, err := u.twoFactorService.verify(ctx, util.GetDBFromContext(ctx), userId, req.Code)
+	if err != nil {
+		if httpErr, ok := err.(*sm_error.Error); !ok || httpErr.ErrorCode != error_code.UserTwoFactorCodeIncorrect {
+			return "", err
+		}
+		user_redis.FailLoginChallenge(ctx, req.ChallengeToken)
+		return "", u.loginFailed(ctx, phone, &user_dto.User{Id: userId}, err)
+	}
+	if usedRecoveryCode {
+		vars.Log.Infof("userServiceImpl.LoginVerify recovery code used, user_id:%s", userId)
+	}
+	user_redis.ClearLoginChallenge(ctx, req.ChallengeToken)
+	// 账号可能在两步之间被停用或禁用, 与 Login 一样在设置会话前检查
+	user, err := u.userRepo.GetById(ctx, util.GetDBFromContext(ctx), userId)
+	if err != nil {
+		return "", err
+	}
+	if user == nil || user.IsDeactivated() {
+		return "", sm_error.NewHttpError(error_code.UserDeactivated)
+	}
+	if user.IsDisabled() {
+		return "", sm_error.NewHttpError(error_code.UserDisabled)
+	}
+	user_redis.ClearLoginFailure(ctx, phone)
+	err = u.createSession(ctx, userId)
 	if err != nil {
 		return "", err
 	}
+	return userId, nil
+}
+
+// createSession 设置session到redis, 同一用户可以在多个设备上同时登录
+func (u *userServiceImpl) createSession(ctx *gin.Context, userId string) error {
+	token, err := generateRandomToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = user_redis.SetSession(ctx, token, &user_dto.Session{
+		UserID:     userId,
+		UserAgent:  ctx.Request.UserAgent(),
+		Ip:         ctx.ClientIP(),
+		CreateTime: now,
+		ExpireTime: now.Add(tokenExpireTime * time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	// 设置cookie
+	ctx.SetCookie("token", token, tokenExpireTime, "/", "", false, false)
+	ctx.SetCookie("user_id", userId, tokenExpireTime, "/", "", false, false)
+	return nil
+}
+
+// loginFailed 记录登录失败并返回 failErr, 已注册的手机号因此被锁定时返回锁定错误并记录锁定事件,
+// 供所在团队的所有者查看. 手机号不存在与密码错误返回相同的错误, 避免通过登录接口探测注册用户
+func (u *userServiceImpl) loginFailed(ctx *gin.Context, phone string, user *user_dto.User, failErr error) error {
+	lockedUntil, err := user_redis.RecordLoginFailure(ctx, phone, ctx.ClientIP())
+	if err != nil {
+		return err
+	}
+	if lockedUntil.IsZero() {
+		return failErr
+	}
+	if user == nil {
+		return sm_error.NewHttpError(error_code.UserLoginLocked)
+	}
+	teamRole, err := u.authService.GetTeamRole(ctx, user.Id)
+	if err == nil {
+		err = u.loginLockoutRepo.Add(ctx, util.GetDBFromContext(ctx), &user_dto.LoginLockout{
+			TeamID:      teamRole.OwnerId,
+			UserID:      user.Id,
+			Phone:       phone,
+			Ip:          ctx.ClientIP(),
+			UserAgent:   ctx.Request.UserAgent(),
+			LockedUntil: lockedUntil,
+		})
+	}
+	if err != nil {
+		vars.Log.Errorf("userServiceImpl.loginFailed record lockout error:%v, user_id:%s", err, user.Id)
+	}
+	return sm_error.NewHttpError(error_code.UserLoginLocked)
+}
+
+func (u *userServiceImpl) ModifyPwd(ctx *gin.Context, req *user_dto.ModifyUserPasswordReq) error {
+	tx := util.GetDBFromContext(ctx).Begin()
+	var err error
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	accountDetail, err := u.userRepo.GetById(ctx, tx, req.UserId)
+	if err != nil {
+		return err
+	}
 	if accountDetail == nil {
-		return "", sm_error.NewHttpError(error_code.UserLoginFailed, "Email not exists!")
-	}
-	if accountDetail.Password == req.Password {
-		token := generateRandomToken()
-		// 设置cookie
-		ctx.SetCookie("token", token, tokenExpireTime, "/", "", false, false)
-		ctx.SetCookie("user_id", accountDetail.Id, tokenExpireTime, "/", "", false, false)
-		// 设置session到redis
-		user_redis.ClearSession(ctx, accountDetail.Id)
-		user_redis.SetSession(ctx, accountDetail.Id, token, tokenExpireTime)
-		tx.Commit()
-		return accountDetail.Id, nil
-	} else {
-		return "", sm_error.NewHttpError(error_code.UserLoginFailed)
+		err = sm_error.NewHttpError(error_code.UserNoExists)
+		return err
 	}
+	if ok, _ := util.VerifyPassword(accountDetail.Password, req.OldPassword); !ok {
+		err = sm_error.NewHttpError(error_code.UserModifyPasswordFailed, "原密码不正确")
+		return err
+	}
+	if req.NewPassword != req.ConfirmPassword {
+		err = sm_error.NewHttpError(error_code.UserConfirmPasswordIncorrect)
+		return err
+	}
+	err = u.savePassword(ctx, tx, accountDetail.Id, req.NewPassword)
+	if err != nil {
+		return err
+	}
+	_ = user_redis.ClearSession(ctx, accountDetail.Id)
+	return nil
 }
 
-//	func (u *userServiceImpl) ModifyPwd(ctx *gin.Context, req *user_dto.ModifyUserPasswordReq) error {
-//		tx := util.GetDBFromContext(ctx).Begin()
-//		var err error
-//		defer func() {
-//			if err != nil {
-//				tx.Rollback()
-//			} else {
-//				tx.Commit()
-//			}
-//		}()
-//		accountDetail, err := u.userRepo.GetById(ctx, tx, req.UserId)
-//		if err != nil {
-//			return err
-//		}
-//		if accountDetail.Password != req.OldPassword {
-//			return js_error.NewHttpError(error_code.UserModifyPasswordFailed)
-//		}
-//		if req.NewPassword != req.ConfirmPassword {
-//			return js_error.NewHttpError(error_code.UserModifyPasswordFailed)
-//		}
-//		err = u.userRepo.ModifyPassword(ctx, tx, accountDetail.Id, req.NewPassword)
-//		if err != nil {
-//			return err
-//		}
-//		_ = user_redis.ClearSession(ctx, accountDetail.Id)
-//		return nil
-//	}
+// SendResetCode 手机号未注册时同样返回成功, 避免通过该接口探测注册用户
+func (u *userServiceImpl) SendResetCode(ctx *gin.Context, req *user_dto.SendResetCodeReq) error {
+	err := user_redis.AllowSendResetCode(ctx, req.Phone)
+	if err != nil {
+		return err
+	}
+	accountDetail, err := u.userRepo.GetByPhone(ctx, util.GetDBFromContext(ctx), req.Phone)
+	if err != nil {
+		return err
+	}
+	if accountDetail == nil {
+		return nil
+	}
+	code, err := generateResetCode()
+	if err != nil {
+		vars.Log.Errorf("userServiceImpl.SendResetCode generate code error:%v", err)
+		return sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	err = user_redis.SetResetCode(ctx, req.Phone, code)
+	if err != nil {
+		return err
+	}
+	err = u.messageSender.Send(ctx, req.Phone, fmt.Sprintf("您的密码重置验证码为%s, 10分钟内有效, 请勿泄露给他人", code))
+	if err != nil {
+		user_redis.ClearResetCode(ctx, req.Phone)
+		return sm_error.NewHttpError(error_code.UserSendMessageFailed)
+	}
+	return nil
+}
+
+// ResetPassword 重置成功后作废验证码并清除该用户的所有登录会话
+func (u *userServiceImpl) ResetPassword(ctx *gin.Context, req *user_dto.ResetPasswordReq) error {
+	if req.NewPassword != req.ConfirmPassword {
+		return sm_error.NewHttpError(error_code.UserConfirmPasswordIncorrect)
+	}
+	err := user_redis.CheckResetCode(ctx, req.Phone, req.Code)
+	if err != nil {
+		return err
+	}
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	accountDetail, err := u.userRepo.GetByPhone(ctx, tx, req.Phone)
+	if err != nil {
+		return err
+	}
+	if accountDetail == nil {
+		err = sm_error.NewHttpError(error_code.UserResetCodeIncorrect)
+		return err
+	}
+	err = u.savePassword(ctx, tx, accountDetail.Id, req.NewPassword)
+	if err != nil {
+		return err
+	}
+	user_redis.ClearResetCode(ctx, req.Phone)
+	_ = user_redis.ClearSession(ctx, accountDetail.Id)
+	return nil
+}
+
+func (u *userServiceImpl) savePassword(ctx *gin.Context, tx *gorm.DB, id string, password string) error {
+	hashed, err := util.HashPassword(password)
+	if err != nil {
+		vars.Log.Errorf("userServiceImpl.savePassword hash password error:%v,id: %v", err, id)
+		return sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	return u.userRepo.ModifyPassword(ctx, tx, id, hashed)
+}
+
+// GetUserProfile userId 为空时返回当前用户的资料, 其他用户的资料只能在同一团队内查看
 func (u *userServiceImpl) GetUserProfile(ctx *gin.Context, userId string) (*user_dto.UserProfile, error) {
+	if userId == "" {
+		userId = util.GetUserIdByCookie(ctx)
+	}
 	user, err := u.userRepo.GetById(ctx, util.GetDBFromContext(ctx), userId)
 	if err != nil {
-		return nil, sm_error.NewHttpError(error_code.UserModifyPasswordFailed)
+		return nil, err
 	}
 	if user == nil {
 		return nil, sm_error.NewHttpError(error_code.UserNoExists)
 	}
+	teamRole, err := u.authService.GetTeamRole(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	// 不在同一团队时与用户不存在返回相同的错误
+	if user.Id != util.GetUserIdByCookie(ctx) && teamRole.OwnerId != util.GetTeamId(ctx) {
+		return nil, sm_error.NewHttpError(error_code.UserNoExists)
+	}
 	return &user_dto.UserProfile{
+		UserId:    user.Id,
 		AvatarUrl: user.AvatarUrl,
 		Name:      user.Name,
 		Email:     user.Email,
 		Phone:     user.Phone,
+		Role:      teamRole.Role,
+		IsAdmin:   teamRole.IsOwner(),
 	}, nil
 }
 
-//	func (u *userServiceImpl) SaveUserProfile(ctx *gin.Context, req *user_dto.UserProfile) error {
-//		user, err := u.userRepo.GetById(ctx, util.GetDBFromContext(ctx), req.UserId)
-//		if err != nil {
-//			return err
-//		}
-//		if user == nil {
-//			return js_error.NewHttpError(error_code.UserNoExists)
-//		}
-//		err = u.userRepo.SaveProfile(ctx, util.GetDBFromContext(ctx), req)
-//		if err != nil {
-//			return err
-//		}
-//		return nil
-//	}
-//
-//	func (u *userServiceImpl) Deactivate(ctx *gin.Context, userId string) error {
-//		err := u.userRepo.Delete(ctx, util.GetDBFromContext(ctx), userId)
-//		if err != nil {
-//			return err
-//		}
-//		_ = user_redis.ClearSession(ctx, userId)
-//		return nil
-//	}
-func generateRandomToken() string {
-	bytes := make([]byte, 24)
-	_, err := rand.Read(bytes)
+// SaveUserProfile 修改当前用户的名称和邮箱, 邮箱不能与其他用户重复. 手机号作为登录账号不在此修改
+func (u *userServiceImpl) SaveUserProfile(ctx *gin.Context, req *user_dto.SaveUserProfileReq) error {
+	userId := util.GetUserIdByCookie(ctx)
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return sm_error.NewHttpError(error_code.UserEmailInvalid)
+		}
+	}
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	if email != "" {
+		var exists *user_dto.User
+		exists, err = u.userRepo.GetByEmail(ctx, tx, email)
+		if err != nil {
+			return err
+		}
+		if exists != nil && exists.Id != userId {
+			err = sm_error.NewHttpError(error_code.UserEmailExists)
+			return err
+		}
+	}
+	err = u.userRepo.SaveProfile(ctx, tx, &user_dto.UserProfile{
+		UserId: userId,
+		Name:   req.Name,
+		Email:  email,
+	})
+	return err
+}
+
+// UploadAvatar 上传当前用户的头像, 按文件内容而不是扩展名判断图片类型
+func (u *userServiceImpl) UploadAvatar(ctx *gin.Context, file *multipart.FileHeader) (string, error) {
+	if file.Size > avatarMaxSize {
+		return "", sm_error.NewHttpError(error_code.FileSizeOutOfMax)
+	}
+	contentType, err := detectContentType(file)
 	if err != nil {
-		return ""
+		vars.Log.Errorf("userServiceImpl.UploadAvatar read file error:%v", err)
+		return "", sm_error.NewHttpError(error_code.FileUploadError)
+	}
+	if !avatarContentTypes[contentType] {
+		return "", sm_error.NewHttpError(error_code.FileTypeNotAllowed)
+	}
+	url, err := u.fileService.UploadFile(ctx, &file_dto.UploadReq{File: file, MaxSize: avatarMaxSize})
+	if err != nil {
+		if _, ok := err.(*sm_error.Error); ok {
+			return "", err
+		}
+		vars.Log.Errorf("userServiceImpl.UploadAvatar upload error:%v", err)
+		return "", sm_error.NewHttpError(error_code.FileUploadError)
+	}
+	err = u.userRepo.UpdateAvatar(ctx, util.GetDBFromContext(ctx), util.GetUserIdByCookie(ctx), url)
+	if err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+func detectContentType(file *multipart.FileHeader) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
 	}
-	sessionValue := hex.EncodeToString(bytes)
-	return sessionValue
+	return http.DetectContentType(head[:n]), nil
 }
 
-//
-//func (u *userServiceImpl) GenerateApiSecret(ctx *gin.Context, req *user_dto.GenerateApiSecret) error {
-//	user, err := u.userRepo.GetById(ctx, util.GetDBFromContext(ctx), req.UserId)
-//	if err != nil {
-//		return err
-//	}
-//	if user == nil {
-//		return js_error.NewHttpError(error_code.UserNoExists)
-//	}
-//	apiSecret := generateRandomToken()
-//	err = u.userRepo.SaveSecret(ctx, util.GetDBFromContext(ctx), req.UserId, req.ApiKey, apiSecret)
-//	if err != nil {
-//		return err
-//	}
-//	return nil
-//}
-//
-//func (u *userServiceImpl) GetApiSecret(ctx *gin.Context, userId string) (*user_dto.GetApiSecret, error) {
-//	user, err := u.userRepo.GetById(ctx, util.GetDBFromContext(ctx), userId)
-//	if err != nil {
-//		return nil, err
-//	}
-//	if user == nil {
-//		return nil, js_error.NewHttpError(error_code.UserNoExists)
-//	}
-//	return &user_dto.GetApiSecret{
-//		ApiKey:    user.ApiKey,
-//		ApiSecret: user.ApiSecret,
-//	}, nil
-//}
-//
-//func (u *userServiceImpl) UserList(ctx *gin.Context, req *user_dto.UserListReq) (*user_dto.UserListResp, error) {
-//	list, err := u.userRepo.List(ctx, util.GetDBFromContext(ctx), req)
-//	if err != nil {
-//		return nil, err
-//	}
-//	ids := make([]string, len(list))
-//	for i, user := range list {
-//		ids[i] = user.Id
-//	}
-//	balanceMap, err := u.userBalanceService.BatchGetUserBalance(ctx, ids)
-//	if err != nil {
-//		return nil, err
-//	}
-//	for _, user := range list {
-//		if v, ok := balanceMap[user.Id]; ok {
-//			user.Balance = v.Balance
-//			user.Currency = v.Currency
-//		}
-//	}
-//	return &user_dto.UserListResp{
-//		Pager: req.Pager,
-//		List:  list,
-//	}, nil
-//}
-//
-//func (u *userServiceImpl) SaveUser(ctx *gin.Context, req *user_dto.User) error {
-//	user, err := u.userRepo.GetById(ctx, util.GetDBFromContext(ctx), req.Id)
-//	if err != nil {
-//		return err
-//	}
-//	if user == nil {
-//		return js_error.NewHttpError(error_code.UserNoExists)
-//	}
-//	ub, err := u.userBalanceService.GetByUserId(ctx, req.Id)
-//	if err != nil {
-//		return err
-//	}
-//	if ub != nil {
-//		ub.Balance = req.Balance
-//		ub.Currency = req.Currency
-//	} else {
-//		ub = &user_dto.UserBalance{
-//			UserId:   req.Id,
-//			Balance:  req.Balance,
-//			Currency: req.Currency,
-//		}
-//	}
-//
-//	user.Name = req.Name
-//	user.Email = req.Name
-//	user.Password = req.Password
-//	user.PhoneNumber = req.PhoneNumber
-//	err = u.userRepo.Save(ctx, util.GetDBFromContext(ctx), user)
-//	if err != nil {
-//		return err
-//	}
-//	return nil
-//}
-//
-//func (u *userServiceImpl) GetUserIdBySecret(ctx *gin.Context, key string, secret string) (string, error) {
-//	user, err := u.userRepo.GetBySecret(ctx, util.GetDBFromContext(ctx), secret)
-//	if err != nil {
-//		return "", err
-//	}
-//	if user == nil {
-//		return "", js_error.NewHttpError(error_code.UserSecretIncorrect)
-//	}
-//	if user.ApiKey != key {
-//		return "", js_error.NewHttpError(error_code.UserSecretIncorrect)
-//	}
-//	return user.Id, nil
-//}
+// ListSession 当前用户在各设备上的登录会话, 标记出本次请求所用的会话
+func (u *userServiceImpl) ListSession(ctx *gin.Context) ([]*user_dto.Session, error) {
+	list, err := user_redis.ListSession(ctx, util.GetUserIdByCookie(ctx))
+	if err != nil {
+		return nil, err
+	}
+	token, _ := ctx.Cookie("token")
+	current := user_redis.GetSessionId(token)
+	for _, session := range list {
+		session.Current = session.ID == current
+	}
+	return list, nil
+}
+
+func (u *userServiceImpl) RevokeSession(ctx *gin.Context, req *user_dto.RevokeSessionReq) error {
+	ok, err := user_redis.DelSession(ctx, util.GetUserIdByCookie(ctx), req.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.UserSessionNoExists)
+	}
+	return nil
+}
+
+// RevokeAllSession 注销所有设备上的会话, 包括本次请求所用的会话
+func (u *userServiceImpl) RevokeAllSession(ctx *gin.Context) error {
+	err := user_redis.ClearSession(ctx, util.GetUserIdByCookie(ctx))
+	if err != nil {
+		return err
+	}
+	ctx.SetCookie("token", "", -1, "/", "", false, false)
+	ctx.SetCookie("user_id", "", -1, "/", "", false, false)
+	return nil
+}
+
+// generateResetCode 6位数字验证码
+func generateResetCode() (string, error) {
+	n, err := cryptorand.Int(cryptorand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// generateRandomToken 会话令牌和两步登录的挑战令牌, 需不可预测
+func generateRandomToken() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := cryptorand.Read(bytes); err != nil {
+		vars.Log.Errorf("userServiceImpl.generateRandomToken error:%v", err)
+		return "", sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	return hex.EncodeToString(bytes), nil
+}
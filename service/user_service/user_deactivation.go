@@ -0,0 +1,307 @@
+package user_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/config"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/redis/user_redis"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/api_key_repo"
+	"github.com/shop_management/repository/user_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/auth_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+type userDeactivationServiceImpl struct {
+	userRepo      repository.UserRepo
+	userTeamRepo  repository.UserTeamRepo
+	apiKeyRepo    repository.ApiKeyRepo
+	twoFactorRepo repository.UserTwoFactorRepo
+	identityRepo  repository.UserIdentityRepo
+	grantRepo     repository.UserGrantRepo
+	authService   service.AuthService
+	// retention 停用账号的保留期, 取自配置 user.retention_days, 默认30天
+	retention time.Duration
+}
+
+func NewUserDeactivationServiceImpl() service.UserDeactivationService {
+	return &userDeactivationServiceImpl{
+		userRepo:      user_repo.NewUserRepoImpl(),
+		userTeamRepo:  user_repo.NewUserTeamRepoImpl(),
+		apiKeyRepo:    api_key_repo.NewApiKeyRepoImpl(),
+		twoFactorRepo: user_repo.NewUserTwoFactorRepoImpl(),
+		identityRepo:  user_repo.NewUserIdentityRepoImpl(),
+		grantRepo:     user_repo.NewUserGrantRepoImpl(),
+		authService:   auth_service.NewAuthServiceImpl(),
+		retention:     time.Duration(config.Get().User.RetentionDays) * 24 * time.Hour,
+	}
+}
+
+// Deactivate 用户停用自己的账号, 需要确认密码. 团队所有者需先移除团队中的子账号
+func (u *userDeactivationServiceImpl) Deactivate(ctx *gin.Context, req *user_dto.DeactivateReq) error {
+	userId := util.GetUserIdByCookie(ctx)
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	user, err := u.userRepo.GetById(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		err = sm_error.NewHttpError(error_code.UserNoExists)
+		return err
+	}
+	if ok, _ := util.VerifyPassword(user.Password, req.Password); !ok {
+		err = sm_error.NewHttpError(error_code.UserLoginFailed, "密码不正确")
+		return err
+	}
+	teamRole, err := u.authService.GetTeamRole(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if teamRole.IsOwner() {
+		var members int64
+		members, err = u.userTeamRepo.CountMembers(ctx, tx, userId)
+		if err != nil {
+			return err
+		}
+		if members > 0 {
+			err = sm_error.NewHttpError(error_code.UserTeamNotEmpty)
+			return err
+		}
+	}
+	err = u.deactivate(ctx, tx, userId, teamRole)
+	return err
+}
+
+// DeactivateMember 团队所有者停用子账号
+func (u *userDeactivationServiceImpl) DeactivateMember(ctx *gin.Context, req *user_dto.DeactivateMemberReq) error {
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	member, err := u.userTeamRepo.GetById(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.UserId != util.GetUserIdByCookie(ctx) {
+		err = sm_error.NewHttpError(error_code.UserTeamNoExists)
+		return err
+	}
+	err = u.deactivate(ctx, tx, member.SubUserId, &user_dto.TeamRole{
+		UserId:  member.SubUserId,
+		OwnerId: member.UserId,
+		Role:    member.Role,
+	})
+	return err
+}
+
+// deactivate 停用账号: 移出团队, 作废 API Key, 注销所有会话. 账号数据在保留期内保留
+func (u *userDeactivationServiceImpl) deactivate(ctx *gin.Context, tx *gorm.DB, userId string, teamRole *user_dto.TeamRole) error {
+	ok, err := u.userRepo.Deactivate(ctx, tx, userId, teamRole.OwnerId, teamRole.Role)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.UserDeactivated)
+	}
+	err = u.userTeamRepo.DelBySubUserId(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	err = u.apiKeyRepo.RevokeByUser(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	return user_redis.ClearSession(ctx, userId)
+}
+
+// DeactivatedList 团队中已停用、尚未清除的账号
+func (u *userDeactivationServiceImpl) DeactivatedList(ctx *gin.Context) ([]*user_dto.DeactivatedUser, error) {
+	users, err := u.userRepo.ListDeactivated(ctx, util.GetDBFromContext(ctx), util.GetTeamId(ctx))
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*user_dto.DeactivatedUser, 0, len(users))
+	for _, user := range users {
+		// 团队所有者自己停用的账号不在此列出, 由本人通过 ReactivateSelf 恢复
+		if user.Id == user.DeactivatedTeamId {
+			continue
+		}
+		list = append(list, &user_dto.DeactivatedUser{
+			UserId:          user.Id,
+			Name:            user.Name,
+			Phone:           user.Phone,
+			Role:            user.DeactivatedRole,
+			DeactivatedTime: *user.DeletedTime,
+			PurgeTime:       user.DeletedTime.Add(u.retention),
+		})
+	}
+	return list, nil
+}
+
+// Reactivate 团队所有者在保留期内恢复停用的子账号, 恢复后以原角色重新加入团队
+func (u *userDeactivationServiceImpl) Reactivate(ctx *gin.Context, req *user_dto.ReactivateReq) error {
+	teamId := util.GetTeamId(ctx)
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	user, err := u.userRepo.GetById(ctx, tx, req.UserId)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsDeactivated() || user.DeactivatedTeamId != teamId || user.Id == teamId {
+		err = sm_error.NewHttpError(error_code.UserNoExists)
+		return err
+	}
+	ok, err := u.userRepo.Reactivate(ctx, tx, user.Id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.UserNoExists)
+		return err
+	}
+	err = u.userTeamRepo.AddSubUser(ctx, tx, user.Id, teamId, user.DeactivatedRole)
+	return err
+}
+
+// ReactivateSelf 团队所有者在保留期内用手机号和密码恢复自己停用的账号, 与登录共用失败次数限制.
+// 恢复后需重新登录, 已启用的两步验证仍然生效. 子账号只能由团队所有者恢复
+func (u *userDeactivationServiceImpl) ReactivateSelf(ctx *gin.Context, req *user_dto.UserLogin) error {
+	err := user_redis.CheckLoginAllowed(ctx, req.Phone, ctx.ClientIP())
+	if err != nil {
+		return err
+	}
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	user, err := u.userRepo.GetByPhone(ctx, tx, req.Phone)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		err = u.reactivateFailed(ctx, req.Phone)
+		return err
+	}
+	if ok, _ := util.VerifyPassword(user.Password, req.Password); !ok {
+		err = u.reactivateFailed(ctx, req.Phone)
+		return err
+	}
+	user_redis.ClearLoginFailure(ctx, req.Phone)
+	// 未停用的账号直接返回成功
+	if !user.IsDeactivated() {
+		return nil
+	}
+	if user.Id != user.DeactivatedTeamId {
+		err = sm_error.NewHttpError(error_code.UserReactivateNotAllowed)
+		return err
+	}
+	if !user.DeletedTime.Add(u.retention).After(time.Now()) {
+		err = sm_error.NewHttpError(error_code.UserReactivateNotAllowed, "账号已超过保留期, 无法恢复")
+		return err
+	}
+	ok, err := u.userRepo.Reactivate(ctx, tx, user.Id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err = sm_error.NewHttpError(error_code.UserNoExists)
+		return err
+	}
+	vars.Log.Infof("userDeactivationServiceImpl.ReactivateSelf reactivated user_id:%s", user.Id)
+	return nil
+}
+
+// reactivateFailed 计入登录失败次数, 手机号不存在与密码错误返回相同的错误
+func (u *userDeactivationServiceImpl) reactivateFailed(ctx *gin.Context, phone string) error {
+	lockedUntil, err := user_redis.RecordLoginFailure(ctx, phone, ctx.ClientIP())
+	if err != nil {
+		return err
+	}
+	if !lockedUntil.IsZero() {
+		return sm_error.NewHttpError(error_code.UserLoginLocked)
+	}
+	return sm_error.NewHttpError(error_code.UserLoginFailed)
+}
+
+// PurgeExpired 清除超过保留期的停用账号, 由定时任务调用. 团队业务数据属于团队所有者, 不在此清除
+func (u *userDeactivationServiceImpl) PurgeExpired(ctx *gin.Context) error {
+	db := util.GetDBFromContext(ctx)
+	users, err := u.userRepo.ListDeactivatedBefore(ctx, db, time.Now().Add(-u.retention))
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		err = u.purge(ctx, db, user.Id)
+		if err != nil {
+			vars.Log.Errorf("userDeactivationServiceImpl.PurgeExpired error:%v, user_id:%s", err, user.Id)
+			continue
+		}
+		vars.Log.Infof("userDeactivationServiceImpl.PurgeExpired purged user_id:%s", user.Id)
+	}
+	return nil
+}
+
+func (u *userDeactivationServiceImpl) purge(ctx *gin.Context, db *gorm.DB, userId string) error {
+	var err error
+	tx := db.Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = u.twoFactorRepo.Delete(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	err = u.identityRepo.DeleteByUser(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	err = u.apiKeyRepo.DeleteByUser(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	err = u.userTeamRepo.DelBySubUserId(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	// 停用期间保留授权以便恢复, 清除时授权属于原团队
+	err = u.grantRepo.DeleteByUser(ctx, util.WithoutTeamScope(tx), userId)
+	if err != nil {
+		return err
+	}
+	err = u.userRepo.Delete(ctx, tx, userId)
+	return err
+}
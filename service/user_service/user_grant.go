@@ -0,0 +1,67 @@
+package user_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"strings"
+)
+
+// GrantList 经理可以查看团队成员的授权, 只有所有者可以修改
+func (u *userTeamServiceImpl) GrantList(ctx *gin.Context, req *user_dto.GrantListReq) ([]*user_dto.UserGrant, error) {
+	member, err := u.userTeamRepo.GetById(ctx, util.GetDBFromContext(ctx), req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.UserId != util.GetTeamId(ctx) {
+		return nil, sm_error.NewHttpError(error_code.UserTeamNoExists)
+	}
+	return u.userGrantRepo.ListByUser(ctx, util.GetDBFromContext(ctx), member.SubUserId)
+}
+
+func (u *userTeamServiceImpl) AddGrant(ctx *gin.Context, req *user_dto.AddGrantReq) (*user_dto.UserGrant, error) {
+	grant := &user_dto.UserGrant{
+		ResourceType: req.ResourceType,
+		ResourceID:   strings.TrimSpace(req.ResourceID),
+		Action:       req.Action,
+	}
+	if grant.ResourceType == user_dto.GrantResourceProduct && grant.ResourceID == "" {
+		grant.ResourceID = user_dto.GrantAllResources
+	}
+	if !u.authService.ValidGrant(grant) {
+		return nil, sm_error.NewHttpError(error_code.GrantInvalid)
+	}
+	member, err := u.getOwnMember(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	grant.UserID = member.SubUserId
+	grants, err := u.userGrantRepo.ListByUser(ctx, util.GetDBFromContext(ctx), grant.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// 重复授予同一授权时返回已有的记录
+	for _, g := range grants {
+		if g.ResourceType == grant.ResourceType && g.ResourceID == grant.ResourceID && g.Action == grant.Action {
+			return g, nil
+		}
+	}
+	err = u.userGrantRepo.Add(ctx, util.GetDBFromContext(ctx), grant)
+	if err != nil {
+		return nil, err
+	}
+	return grant, nil
+}
+
+func (u *userTeamServiceImpl) DelGrant(ctx *gin.Context, req *user_dto.DelGrantReq) error {
+	grant, err := u.userGrantRepo.GetById(ctx, util.GetDBFromContext(ctx), req.Id)
+	if err != nil {
+		return err
+	}
+	if grant == nil {
+		return sm_error.NewHttpError(error_code.GrantNoExists)
+	}
+	return u.userGrantRepo.Delete(ctx, util.GetDBFromContext(ctx), req.Id)
+}
@@ -0,0 +1,234 @@
+package user_service
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/user_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/auth_service"
+	"github.com/shop_management/service/message_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const invitationExpireTime = 7 * 24 * time.Hour
+
+type userInvitationServiceImpl struct {
+	invitationRepo repository.UserInvitationRepo
+	userTeamRepo   repository.UserTeamRepo
+	userRepo       repository.UserRepo
+	authService    service.AuthService
+	messageSender  service.MessageSender
+	emailSender    service.MessageSender
+}
+
+func NewUserInvitationServiceImpl() service.UserInvitationService {
+	return newUserInvitationServiceImpl()
+}
+
+func newUserInvitationServiceImpl() *userInvitationServiceImpl {
+	return &userInvitationServiceImpl{
+		invitationRepo: user_repo.NewUserInvitationRepoImpl(),
+		userTeamRepo:   user_repo.NewUserTeamRepoImpl(),
+		userRepo:       user_repo.NewUserRepoImpl(),
+		authService:    auth_service.NewAuthServiceImpl(),
+		messageSender:  message_service.NewMessageSender(),
+		emailSender:    message_service.NewEmailSender(),
+	}
+}
+
+// Invite 按手机号或邮箱邀请加入当前团队, 返回的 Token 用于接受/拒绝邀请或通过邀请注册
+func (u *userInvitationServiceImpl) Invite(ctx *gin.Context, req *user_dto.InviteReq) (*user_dto.Invitation, error) {
+	phone := strings.TrimSpace(req.Phone)
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if phone == "" && email == "" {
+		return nil, sm_error.NewHttpError(error_code.UserInvitationTargetRequired)
+	}
+	role := req.Role
+	if role == "" {
+		role = user_dto.RoleReadOnly
+	}
+	if !u.authService.ValidRole(role) {
+		return nil, sm_error.NewHttpError(error_code.UserRoleInvalid)
+	}
+	db := util.GetDBFromContext(ctx)
+	ownerId := util.GetTeamId(ctx)
+	if phone != "" {
+		invitee, err := u.userRepo.GetByPhone(ctx, db, phone)
+		if err != nil {
+			return nil, err
+		}
+		if invitee != nil {
+			err = u.checkJoinable(ctx, db, ownerId, invitee.Id)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	invitation := &user_dto.Invitation{
+		UserID:     ownerId,
+		Phone:      phone,
+		Email:      email,
+		Role:       role,
+		Status:     user_dto.InvitationStatusPending,
+		ExpireTime: time.Now().Add(invitationExpireTime),
+	}
+	err := u.invitationRepo.Add(ctx, db, invitation)
+	if err != nil {
+		return nil, err
+	}
+	invitation.Token = util.SignToken(invitation.ID, strconv.FormatInt(invitation.ExpireTime.Unix(), 10))
+	// 同时填写手机号和邮箱时两边都发送
+	content := fmt.Sprintf("您收到一个团队邀请, 邀请码: %s, 7天内有效", invitation.Token)
+	if phone != "" {
+		err = u.messageSender.Send(ctx, phone, content)
+		if err != nil {
+			return nil, sm_error.NewHttpError(error_code.UserSendMessageFailed)
+		}
+	}
+	if email != "" {
+		err = u.emailSender.Send(ctx, email, content)
+		if err != nil {
+			return nil, sm_error.NewHttpError(error_code.UserSendMessageFailed)
+		}
+	}
+	return invitation, nil
+}
+
+func (u *userInvitationServiceImpl) List(ctx *gin.Context, req *user_dto.InvitationListReq) (*user_dto.InvitationListResp, error) {
+	req.UserID = util.GetTeamId(ctx)
+	return u.invitationRepo.List(ctx, util.GetDBFromContext(ctx), req)
+}
+
+// Detail 未登录也可查看, 用于邀请链接展示邀请人与角色
+func (u *userInvitationServiceImpl) Detail(ctx *gin.Context, token string) (*user_dto.Invitation, error) {
+	db := util.GetDBFromContext(ctx)
+	invitation, err := u.parse(ctx, db, token)
+	if err != nil {
+		return nil, err
+	}
+	inviter, err := u.userRepo.GetById(ctx, db, invitation.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if inviter != nil {
+		invitation.InviterName = inviter.Name
+	}
+	return invitation, nil
+}
+
+func (u *userInvitationServiceImpl) Accept(ctx *gin.Context, token string) error {
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	invitation, err := u.parse(ctx, tx, token)
+	if err != nil {
+		return err
+	}
+	user, err := u.userRepo.GetById(ctx, tx, util.GetUserIdByCookie(ctx))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		err = sm_error.NewHttpError(error_code.UserNoExists)
+		return err
+	}
+	err = u.accept(ctx, tx, invitation, user)
+	return err
+}
+
+func (u *userInvitationServiceImpl) Decline(ctx *gin.Context, token string) error {
+	db := util.GetDBFromContext(ctx)
+	invitation, err := u.parse(ctx, db, token)
+	if err != nil {
+		return err
+	}
+	user, err := u.userRepo.GetById(ctx, db, util.GetUserIdByCookie(ctx))
+	if err != nil {
+		return err
+	}
+	if user == nil || !isInvitee(invitation, user) {
+		return sm_error.NewHttpError(error_code.UserInvitationMismatch)
+	}
+	ok, err := u.invitationRepo.UpdateStatus(ctx, db, invitation.ID, user_dto.InvitationStatusPending, user_dto.InvitationStatusDeclined, user.Id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.UserInvitationInvalid)
+	}
+	return nil
+}
+
+// accept 将被邀请人加入团队, 注册时与创建用户在同一事务中调用
+func (u *userInvitationServiceImpl) accept(ctx *gin.Context, tx *gorm.DB, invitation *user_dto.Invitation, user *user_dto.User) error {
+	if !isInvitee(invitation, user) {
+		return sm_error.NewHttpError(error_code.UserInvitationMismatch)
+	}
+	err := u.checkJoinable(ctx, tx, invitation.UserID, user.Id)
+	if err != nil {
+		return err
+	}
+	ok, err := u.invitationRepo.UpdateStatus(ctx, tx, invitation.ID, user_dto.InvitationStatusPending, user_dto.InvitationStatusAccepted, user.Id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.UserInvitationInvalid)
+	}
+	return u.userTeamRepo.AddSubUser(ctx, tx, user.Id, invitation.UserID, invitation.Role)
+}
+
+// parse 校验令牌签名, 并要求邀请仍为待处理且未过期
+func (u *userInvitationServiceImpl) parse(ctx *gin.Context, db *gorm.DB, token string) (*user_dto.Invitation, error) {
+	parts, ok := util.VerifyToken(token)
+	if !ok || len(parts) != 2 {
+		return nil, sm_error.NewHttpError(error_code.UserInvitationInvalid)
+	}
+	invitation, err := u.invitationRepo.GetById(ctx, db, parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || invitation.Status != user_dto.InvitationStatusPending {
+		return nil, sm_error.NewHttpError(error_code.UserInvitationInvalid)
+	}
+	if invitation.IsExpired(time.Now()) {
+		return nil, sm_error.NewHttpError(error_code.UserInvitationInvalid, "邀请已过期")
+	}
+	return invitation, nil
+}
+
+// checkJoinable 一个用户只能属于一个团队, 且团队所有者不能加入自己的团队
+func (u *userInvitationServiceImpl) checkJoinable(ctx *gin.Context, db *gorm.DB, ownerId string, userId string) error {
+	if ownerId == userId {
+		return sm_error.NewHttpError(error_code.UserTeamMemberExists)
+	}
+	member, err := u.userTeamRepo.GetBySubUserId(ctx, db, userId)
+	if err != nil {
+		return err
+	}
+	if member != nil {
+		return sm_error.NewHttpError(error_code.UserTeamMemberExists)
+	}
+	return nil
+}
+
+func isInvitee(invitation *user_dto.Invitation, user *user_dto.User) bool {
+	if invitation.Phone != "" {
+		return invitation.Phone == user.Phone
+	}
+	return strings.EqualFold(invitation.Email, user.Email)
+}
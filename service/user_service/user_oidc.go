@@ -0,0 +1,216 @@
+package user_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/config"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/redis/user_redis"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"strings"
+	"sync"
+)
+
+// oidcClient 单点登录的身份提供方, 取自配置 oidc. 第一次创建 userService 时初始化,
+// 所有实例共用同一个客户端以共享身份提供方的公钥缓存
+var (
+	oidcOnce   sync.Once
+	oidcConfig *util.OidcConfig
+	oidcClient *util.OidcClient
+)
+
+func loadOidc() (*util.OidcConfig, *util.OidcClient) {
+	oidcOnce.Do(func() {
+		oidcConfig = newOidcConfig(&config.Get().Oidc)
+		oidcClient = util.NewOidcClient(oidcConfig, nil)
+	})
+	return oidcConfig, oidcClient
+}
+
+func newOidcConfig(cfg *config.OidcConfig) *util.OidcConfig {
+	scopes := cfg.Scopes
+	hasOpenid := false
+	for _, scope := range scopes {
+		hasOpenid = hasOpenid || scope == "openid"
+	}
+	if !hasOpenid {
+		scopes = append([]string{"openid"}, scopes...)
+	}
+	return &util.OidcConfig{
+		Issuer:       cfg.Issuer,
+		ClientId:     cfg.ClientId,
+		ClientSecret: cfg.ClientSecret,
+		RedirectUrl:  cfg.RedirectUrl,
+		Scopes:       scopes,
+	}
+}
+
+// OidcAuthURL 单点登录的第一步, 返回身份提供方的登录地址, 由前端跳转
+func (u *userServiceImpl) OidcAuthURL(ctx *gin.Context) (string, error) {
+	return u.oidcAuthURL(ctx, "")
+}
+
+// OidcLinkURL 已登录用户关联企业账号的第一步, 回调后前端调用 OidcLink 完成关联
+func (u *userServiceImpl) OidcLinkURL(ctx *gin.Context) (string, error) {
+	return u.oidcAuthURL(ctx, util.GetUserIdByCookie(ctx))
+}
+
+func (u *userServiceImpl) oidcAuthURL(ctx *gin.Context, linkUserId string) (string, error) {
+	if !u.oidcConfig.Enabled() {
+		return "", sm_error.NewHttpError(error_code.OidcNotEnabled)
+	}
+	state, err := util.NewOidcState()
+	if err != nil {
+		return "", sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	nonce, err := util.NewOidcState()
+	if err != nil {
+		return "", sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	verifier, challenge, err := util.NewPkceVerifier()
+	if err != nil {
+		return "", sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	authUrl, err := u.oidcClient.AuthCodeURL(ctx, state, nonce, challenge)
+	if err != nil {
+		vars.Log.Errorf("userServiceImpl.oidcAuthURL error:%v", err)
+		return "", sm_error.NewHttpError(error_code.OidcLoginFailed)
+	}
+	err = user_redis.SetOidcState(ctx, state, verifier, nonce, linkUserId)
+	if err != nil {
+		return "", err
+	}
+	return authUrl, nil
+}
+
+// oidcExchange 取回 state 并用授权码换取身份信息, state 必须与发起时的用途(登录或关联到 linkUserId)一致
+func (u *userServiceImpl) oidcExchange(ctx *gin.Context, req *user_dto.OidcLoginReq, linkUserId string) (*util.OidcClaims, error) {
+	if !u.oidcConfig.Enabled() {
+		return nil, sm_error.NewHttpError(error_code.OidcNotEnabled)
+	}
+	verifier, nonce, stateUserId, err := user_redis.TakeOidcState(ctx, req.State)
+	if err != nil {
+		return nil, err
+	}
+	if stateUserId != linkUserId {
+		return nil, sm_error.NewHttpError(error_code.OidcStateInvalid)
+	}
+	claims, err := u.oidcClient.Exchange(ctx, req.Code, verifier, nonce)
+	if err != nil {
+		vars.Log.Errorf("userServiceImpl.oidcExchange error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.OidcLoginFailed)
+	}
+	return claims, nil
+}
+
+// OidcLogin 身份提供方回调后用授权码登录. 只能登录已关联该企业账号的账号, 不按邮箱或手机号自动关联,
+// 因为本地账号的邮箱和手机号未经验证. 开启了两步验证的账号与密码登录一样需要校验动态码
+func (u *userServiceImpl) OidcLogin(ctx *gin.Context, req *user_dto.OidcLoginReq) (*user_dto.LoginResult, error) {
+	claims, err := u.oidcExchange(ctx, req, "")
+	if err != nil {
+		return nil, err
+	}
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	user, err := u.oidcUser(ctx, tx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeactivated() {
+		err = sm_error.NewHttpError(error_code.UserDeactivated)
+		return nil, err
+	}
+	if user.IsDisabled() {
+		err = sm_error.NewHttpError(error_code.UserDisabled)
+		return nil, err
+	}
+	twoFactorEnabled, err := u.twoFactorService.enabled(ctx, tx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactorEnabled {
+		var challengeToken string
+		challengeToken, err = generateRandomToken()
+		if err != nil {
+			return nil, err
+		}
+		err = user_redis.SetLoginChallenge(ctx, challengeToken, user.Id, user.Phone)
+		if err != nil {
+			return nil, err
+		}
+		return &user_dto.LoginResult{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
+	}
+	err = u.createSession(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &user_dto.LoginResult{UserId: user.Id}, nil
+}
+
+// oidcUser 按已关联的身份查找账号
+func (u *userServiceImpl) oidcUser(ctx *gin.Context, tx *gorm.DB, claims *util.OidcClaims) (*user_dto.User, error) {
+	identity, err := u.userIdentityRepo.GetBySubject(ctx, tx, u.oidcConfig.Issuer, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil {
+		return nil, sm_error.NewHttpError(error_code.OidcAccountNotLinked)
+	}
+	user, err := u.userRepo.GetById(ctx, tx, identity.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, sm_error.NewHttpError(error_code.OidcAccountNotLinked)
+	}
+	return user, nil
+}
+
+// OidcLink 已登录用户通过身份提供方证明自己持有该企业账号后建立关联, 之后可以用该企业账号登录.
+// 关联流程必须由同一用户发起, 一个企业账号只能关联一个账号
+func (u *userServiceImpl) OidcLink(ctx *gin.Context, req *user_dto.OidcLoginReq) error {
+	userId := util.GetUserIdByCookie(ctx)
+	claims, err := u.oidcExchange(ctx, req, userId)
+	if err != nil {
+		return err
+	}
+	db := util.GetDBFromContext(ctx)
+	issuer := u.oidcConfig.Issuer
+	identity, err := u.userIdentityRepo.GetBySubject(ctx, db, issuer, claims.Subject)
+	if err != nil {
+		return err
+	}
+	if identity != nil {
+		if identity.UserID != userId {
+			return sm_error.NewHttpError(error_code.OidcAccountConflict)
+		}
+		return nil
+	}
+	err = u.userIdentityRepo.Add(ctx, db, &user_dto.UserIdentity{
+		UserID:  userId,
+		Issuer:  issuer,
+		Subject: claims.Subject,
+		Email:   strings.ToLower(strings.TrimSpace(claims.Email)),
+		Phone:   normalizeOidcPhone(claims.PhoneNumber),
+	})
+	if err != nil {
+		return err
+	}
+	vars.Log.Infof("userServiceImpl.OidcLink linked user_id:%s subject:%s", userId, claims.Subject)
+	return nil
+}
+
+// normalizeOidcPhone 身份提供方返回 E.164 格式的手机号, 账号中保存的是不带国家码的大陆手机号
+func normalizeOidcPhone(phone string) string {
+	phone = strings.NewReplacer(" ", "", "-", "").Replace(phone)
+	return strings.TrimPrefix(phone, "+86")
+}
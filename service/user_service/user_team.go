@@ -4,23 +4,33 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/shop_management/dto/common_dto"
 	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/redis/user_redis"
 	"github.com/shop_management/repository"
 	"github.com/shop_management/repository/user_repo"
 	"github.com/shop_management/service"
+	"github.com/shop_management/service/auth_service"
 	"github.com/shop_management/sm_error"
 	"github.com/shop_management/sm_error/error_code"
 	"github.com/shop_management/util"
 )
 
 type userTeamServiceImpl struct {
-	userTeamRepo repository.UserTeamRepo
-	userRepo     repository.UserRepo
+	userTeamRepo      repository.UserTeamRepo
+	userRepo          repository.UserRepo
+	loginLockoutRepo  repository.LoginLockoutRepo
+	userGrantRepo     repository.UserGrantRepo
+	authService       service.AuthService
+	invitationService service.UserInvitationService
 }
 
 func NewUserTeamServiceImpl() service.UserTeamService {
 	return &userTeamServiceImpl{
-		userTeamRepo: user_repo.NewUserTeamRepoImpl(),
-		userRepo:     user_repo.NewUserRepoImpl(),
+		userTeamRepo:      user_repo.NewUserTeamRepoImpl(),
+		userRepo:          user_repo.NewUserRepoImpl(),
+		loginLockoutRepo:  user_repo.NewLoginLockoutRepoImpl(),
+		userGrantRepo:     user_repo.NewUserGrantRepoImpl(),
+		authService:       auth_service.NewAuthServiceImpl(),
+		invitationService: NewUserInvitationServiceImpl(),
 	}
 }
 
@@ -31,7 +41,7 @@ func (u *userTeamServiceImpl) SubUserList(ctx *gin.Context, req *user_dto.SubUse
 	}
 	userIds := make([]string, 0)
 	for _, user := range resp.List {
-		userIds = append(userIds, user.Id)
+		userIds = append(userIds, user.SubUserId)
 	}
 	userDetailList, err := u.userRepo.List(ctx, util.GetDBFromContext(ctx), &user_dto.UserListReq{
 		Pager: &common_dto.Pager{
@@ -57,27 +67,67 @@ func (u *userTeamServiceImpl) SubUserList(ctx *gin.Context, req *user_dto.SubUse
 	return resp, nil
 }
 
+// AddSubUser 不再直接把用户加入团队, 而是向该手机号发出邀请, 对方接受后才成为子账号
 func (u *userTeamServiceImpl) AddSubUser(ctx *gin.Context, req *user_dto.AddSubUserReq) error {
-	subUser, err := u.userRepo.GetByPhone(ctx, util.GetDBFromContext(ctx), req.Phone)
+	_, err := u.invitationService.Invite(ctx, &user_dto.InviteReq{Phone: req.Phone, Role: req.Role})
+	return err
+}
+
+// DelSubUser 移出团队时一并删除子账号的授权, 再次加入时需要重新授权
+func (u *userTeamServiceImpl) DelSubUser(ctx *gin.Context, req *user_dto.DelSubUserReq) error {
+	member, err := u.getOwnMember(ctx, req.Id)
 	if err != nil {
 		return err
 	}
-	if subUser == nil {
-		return sm_error.NewHttpError(error_code.UserNoExists)
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = u.userTeamRepo.DelSubUser(ctx, tx, req.Id)
+	if err != nil {
+		return err
 	}
-	userId, _ := ctx.Cookie("user_id")
+	err = u.userGrantRepo.DeleteByUser(ctx, tx, member.SubUserId)
+	return err
+}
 
-	err = u.userTeamRepo.AddSubUser(ctx, util.GetDBFromContext(ctx), subUser.Id, userId)
+func (u *userTeamServiceImpl) AssignRole(ctx *gin.Context, req *user_dto.AssignRoleReq) error {
+	if !u.authService.ValidRole(req.Role) {
+		return sm_error.NewHttpError(error_code.UserRoleInvalid)
+	}
+	_, err := u.getOwnMember(ctx, req.Id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return u.userTeamRepo.UpdateRole(ctx, util.GetDBFromContext(ctx), req.Id, req.Role)
 }
 
-func (u *userTeamServiceImpl) DelSubUser(ctx *gin.Context, req *user_dto.DelSubUserReq) error {
-	err := u.userTeamRepo.DelSubUser(ctx, util.GetDBFromContext(ctx), req.Id)
+// ForceLogout 团队所有者强制子账号在所有设备上下线
+func (u *userTeamServiceImpl) ForceLogout(ctx *gin.Context, req *user_dto.ForceLogoutReq) error {
+	member, err := u.getOwnMember(ctx, req.Id)
 	if err != nil {
 		return err
 	}
-	return nil
+	return user_redis.ClearSession(ctx, member.SubUserId)
+}
+
+// LockoutList 团队成员因登录失败次数过多被锁定的记录, 按团队隔离
+func (u *userTeamServiceImpl) LockoutList(ctx *gin.Context, req *user_dto.LoginLockoutListReq) (*user_dto.LoginLockoutListResp, error) {
+	return u.loginLockoutRepo.List(ctx, util.GetDBFromContext(ctx), req)
+}
+
+// getOwnMember 只能操作自己团队下的子账号
+func (u *userTeamServiceImpl) getOwnMember(ctx *gin.Context, id string) (*user_dto.SubUser, error) {
+	member, err := u.userTeamRepo.GetById(ctx, util.GetDBFromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.UserId != util.GetUserIdByCookie(ctx) {
+		return nil, sm_error.NewHttpError(error_code.UserTeamNoExists)
+	}
+	return member, nil
 }
@@ -0,0 +1,274 @@
+package user_service
+
+import (
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/user_dto"
+	"github.com/shop_management/redis/user_redis"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/user_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+const (
+	totpIssuer        = "ShopManagement"
+	recoveryCodeCount = 10
+)
+
+type userTwoFactorServiceImpl struct {
+	twoFactorRepo repository.UserTwoFactorRepo
+	userRepo      repository.UserRepo
+}
+
+func NewUserTwoFactorServiceImpl() service.UserTwoFactorService {
+	return newUserTwoFactorServiceImpl()
+}
+
+func newUserTwoFactorServiceImpl() *userTwoFactorServiceImpl {
+	return &userTwoFactorServiceImpl{
+		twoFactorRepo: user_repo.NewUserTwoFactorRepoImpl(),
+		userRepo:      user_repo.NewUserRepoImpl(),
+	}
+}
+
+// Enroll 生成新的密钥, 校验一次动态码后才会启用. 已启用时需先关闭
+func (u *userTwoFactorServiceImpl) Enroll(ctx *gin.Context) (*user_dto.TwoFactorEnrollment, error) {
+	userId := util.GetUserIdByCookie(ctx)
+	db := util.GetDBFromContext(ctx)
+	twoFactor, err := u.twoFactorRepo.GetByUserId(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.Status == user_dto.TwoFactorStatusEnabled {
+		return nil, sm_error.NewHttpError(error_code.UserTwoFactorEnabled)
+	}
+	user, err := u.userRepo.GetById(ctx, db, userId)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, sm_error.NewHttpError(error_code.UserNoExists)
+	}
+	secret, err := util.GenerateTotpSecret()
+	if err != nil {
+		vars.Log.Errorf("userTwoFactorServiceImpl.Enroll generate secret error:%v", err)
+		return nil, sm_error.NewHttpError(error_code.ServerInternalError)
+	}
+	err = u.twoFactorRepo.Save(ctx, db, &user_dto.TwoFactor{
+		UserID: userId,
+		Secret: secret,
+		Status: user_dto.TwoFactorStatusPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user_dto.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    util.TotpURI(totpIssuer, user.Phone, secret),
+	}, nil
+}
+
+// Activate 校验动态码后启用两步验证, 返回一次性恢复码, 恢复码只在此时展示
+func (u *userTwoFactorServiceImpl) Activate(ctx *gin.Context, req *user_dto.TwoFactorCodeReq) ([]string, error) {
+	userId := util.GetUserIdByCookie(ctx)
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	twoFactor, err := u.twoFactorRepo.GetByUserId(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		err = sm_error.NewHttpError(error_code.UserTwoFactorNotEnabled)
+		return nil, err
+	}
+	if twoFactor.Status == user_dto.TwoFactorStatusEnabled {
+		err = sm_error.NewHttpError(error_code.UserTwoFactorEnabled)
+		return nil, err
+	}
+	err = u.verifyTotp(ctx, twoFactor, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	err = u.twoFactorRepo.UpdateStatus(ctx, tx, userId, user_dto.TwoFactorStatusEnabled)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := u.resetRecoveryCodes(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable 关闭两步验证需要校验动态码或恢复码
+func (u *userTwoFactorServiceImpl) Disable(ctx *gin.Context, req *user_dto.TwoFactorCodeReq) error {
+	userId := util.GetUserIdByCookie(ctx)
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = u.verifyLimited(ctx, tx, userId, req.Code)
+	if err != nil {
+		return err
+	}
+	err = u.twoFactorRepo.Delete(ctx, tx, userId)
+	return err
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码, 旧的恢复码作废
+func (u *userTwoFactorServiceImpl) RegenerateRecoveryCodes(ctx *gin.Context, req *user_dto.TwoFactorCodeReq) ([]string, error) {
+	userId := util.GetUserIdByCookie(ctx)
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = u.verifyLimited(ctx, tx, userId, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := u.resetRecoveryCodes(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// enabled 用户是否已启用两步验证
+func (u *userTwoFactorServiceImpl) enabled(ctx *gin.Context, db *gorm.DB, userId string) (bool, error) {
+	twoFactor, err := u.twoFactorRepo.GetByUserId(ctx, db, userId)
+	if err != nil {
+		return false, err
+	}
+	return twoFactor != nil && twoFactor.Status == user_dto.TwoFactorStatusEnabled, nil
+}
+
+// verify 校验已启用用户的6位动态码或恢复码, usedRecoveryCode 表示本次使用的是恢复码
+func (u *userTwoFactorServiceImpl) verify(ctx *gin.Context, db *gorm.DB, userId string, code string) (usedRecoveryCode bool, err error) {
+	twoFactor, err := u.twoFactorRepo.GetByUserId(ctx, db, userId)
+	if err != nil {
+		return false, err
+	}
+	if twoFactor == nil || twoFactor.Status != user_dto.TwoFactorStatusEnabled {
+		return false, sm_error.NewHttpError(error_code.UserTwoFactorNotEnabled)
+	}
+	code = strings.TrimSpace(code)
+	if len(code) == 6 {
+		return false, u.verifyTotp(ctx, twoFactor, code)
+	}
+	ok, err := u.twoFactorRepo.UseRecoveryCode(ctx, db, userId, hashRecoveryCode(code))
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, sm_error.NewHttpError(error_code.UserTwoFactorCodeIncorrect)
+	}
+	return true, nil
+}
+
+// verifyLimited 已登录用户修改两步验证设置时校验动态码或恢复码, 与 LoginVerify 一样错误次数计入该手机号的登录失败次数,
+// 避免持有会话者无限次猜测
+func (u *userTwoFactorServiceImpl) verifyLimited(ctx *gin.Context, db *gorm.DB, userId string, code string) error {
+	user, err := u.userRepo.GetById(ctx, db, userId)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return sm_error.NewHttpError(error_code.UserNoExists)
+	}
+	err = user_redis.CheckLoginAllowed(ctx, user.Phone, ctx.ClientIP())
+	if err != nil {
+		return err
+	}
+	_, err = u.verify(ctx, db, userId, code)
+	if httpErr, ok := err.(*sm_error.Error); !ok || httpErr.ErrorCode != error_code.UserTwoFactorCodeIncorrect {
+		return err
+	}
+	lockedUntil, recordErr := user_redis.RecordLoginFailure(ctx, user.Phone, ctx.ClientIP())
+	if recordErr != nil {
+		return recordErr
+	}
+	if !lockedUntil.IsZero() {
+		vars.Log.Warnf("userTwoFactorServiceImpl.verifyLimited locked, user_id:%s", userId)
+		return sm_error.NewHttpError(error_code.UserLoginLocked)
+	}
+	return err
+}
+
+// verifyTotp 同一动态码在有效期内只能使用一次
+func (u *userTwoFactorServiceImpl) verifyTotp(ctx *gin.Context, twoFactor *user_dto.TwoFactor, code string) error {
+	step, ok := util.VerifyTotp(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return sm_error.NewHttpError(error_code.UserTwoFactorCodeIncorrect)
+	}
+	fresh, err := user_redis.UseTotpStep(ctx, twoFactor.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return sm_error.NewHttpError(error_code.UserTwoFactorCodeIncorrect)
+	}
+	return nil
+}
+
+func (u *userTwoFactorServiceImpl) resetRecoveryCodes(ctx *gin.Context, db *gorm.DB, userId string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			vars.Log.Errorf("userTwoFactorServiceImpl.resetRecoveryCodes generate error:%v", err)
+			return nil, sm_error.NewHttpError(error_code.ServerInternalError)
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	err := u.twoFactorRepo.ReplaceRecoveryCodes(ctx, db, userId, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// generateRecoveryCode 10位 base32 字符, 以 "-" 分为两段便于抄写, 如 ABCDE-FGHIJ
+func generateRecoveryCode() (string, error) {
+	bytes := make([]byte, 10)
+	if _, err := cryptorand.Read(bytes); err != nil {
+		return "", err
+	}
+	code := base32.StdEncoding.EncodeToString(bytes)[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode 忽略大小写和分隔符
+func hashRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/wallet_dto"
+)
+
+type WalletService interface {
+	Detail(ctx *gin.Context, req *wallet_dto.WalletOwner) (*wallet_dto.Wallet, error)
+	EntryList(ctx *gin.Context, req *wallet_dto.WalletEntryListReq) (*wallet_dto.WalletEntryListResp, error)
+	TopUp(ctx *gin.Context, req *wallet_dto.AddWalletEntryReq) (*wallet_dto.WalletEntry, error)
+	Deduct(ctx *gin.Context, req *wallet_dto.AddWalletEntryReq) (*wallet_dto.WalletEntry, error)
+	Refund(ctx *gin.Context, req *wallet_dto.RefundReq) (*wallet_dto.WalletEntry, error)
+	Reconcile(ctx *gin.Context, req *wallet_dto.WalletOwner) (*wallet_dto.Wallet, error)
+	Stat(ctx *gin.Context, req *wallet_dto.WalletOwner) (*wallet_dto.WalletStatistic, error)
+	Trend(ctx *gin.Context, req *wallet_dto.WalletTrendReq) ([]*wallet_dto.WalletTrendItem, error)
+}
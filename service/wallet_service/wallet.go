@@ -0,0 +1,343 @@
+package wallet_service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/dto/wallet_dto"
+	"github.com/shop_management/repository"
+	"github.com/shop_management/repository/user_repo"
+	"github.com/shop_management/repository/wallet_repo"
+	"github.com/shop_management/service"
+	"github.com/shop_management/service/currency_service"
+	"github.com/shop_management/sm_error"
+	"github.com/shop_management/sm_error/error_code"
+	"github.com/shop_management/util"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"time"
+)
+
+// usageTypes 计入消费统计的流水, 退款冲减扣款
+var usageTypes = []string{wallet_dto.EntryTypeDeduction, wallet_dto.EntryTypeRefund}
+
+type walletServiceImpl struct {
+	walletRepo      repository.WalletRepo
+	userTeamRepo    repository.UserTeamRepo
+	currencyService service.CurrencyService
+}
+
+func NewWalletServiceImpl() service.WalletService {
+	return &walletServiceImpl{
+		walletRepo:      wallet_repo.NewWalletRepoImpl(),
+		userTeamRepo:    user_repo.NewUserTeamRepoImpl(),
+		currencyService: currency_service.NewCurrencyServiceImpl(),
+	}
+}
+
+// Detail 尚未充值过的对象返回余额为0的钱包
+func (w *walletServiceImpl) Detail(ctx *gin.Context, req *wallet_dto.WalletOwner) (*wallet_dto.Wallet, error) {
+	db := util.GetDBFromContext(ctx)
+	err := w.checkOwner(ctx, db, req)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := w.walletRepo.GetByOwner(ctx, db, req.OwnerType, req.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet != nil {
+		return wallet, nil
+	}
+	currency, err := w.currencyService.GetBaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet_dto.Wallet{
+		OwnerType: req.OwnerType,
+		OwnerID:   req.OwnerID,
+		Currency:  currency,
+	}, nil
+}
+
+func (w *walletServiceImpl) EntryList(ctx *gin.Context, req *wallet_dto.WalletEntryListReq) (*wallet_dto.WalletEntryListResp, error) {
+	wallet, err := w.getWallet(ctx, util.GetDBFromContext(ctx), &req.WalletOwner)
+	if err != nil {
+		return nil, err
+	}
+	return w.walletRepo.ListEntry(ctx, util.GetDBFromContext(ctx), wallet.ID, req)
+}
+
+// TopUp 充值, 首次充值时创建钱包, 币种为团队的基础币种
+func (w *walletServiceImpl) TopUp(ctx *gin.Context, req *wallet_dto.AddWalletEntryReq) (*wallet_dto.WalletEntry, error) {
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	err = w.checkOwner(ctx, tx, &req.WalletOwner)
+	if err != nil {
+		return nil, err
+	}
+	err = checkCredit(ctx, &req.WalletOwner)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := w.walletRepo.GetByOwner(ctx, tx, req.OwnerType, req.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet == nil {
+		var currency string
+		currency, err = w.currencyService.GetBaseCurrency(ctx)
+		if err != nil {
+			return nil, err
+		}
+		err = w.walletRepo.Add(ctx, tx, &wallet_dto.Wallet{
+			OwnerType: req.OwnerType,
+			OwnerID:   req.OwnerID,
+			Currency:  currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+		wallet, err = w.getWallet(ctx, tx, &req.WalletOwner)
+		if err != nil {
+			return nil, err
+		}
+	}
+	entry, err := w.addEntry(ctx, tx, wallet.ID, &wallet_dto.WalletEntry{
+		Type:      wallet_dto.EntryTypeTopUp,
+		Amount:    req.Amount,
+		Reference: req.Reference,
+		Remark:    req.Remark,
+	})
+	return entry, err
+}
+
+// Deduct 扣款, 余额不足时整笔失败
+func (w *walletServiceImpl) Deduct(ctx *gin.Context, req *wallet_dto.AddWalletEntryReq) (*wallet_dto.WalletEntry, error) {
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	wallet, err := w.getWallet(ctx, tx, &req.WalletOwner)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := w.addEntry(ctx, tx, wallet.ID, &wallet_dto.WalletEntry{
+		Type:      wallet_dto.EntryTypeDeduction,
+		Amount:    -req.Amount,
+		Reference: req.Reference,
+		Remark:    req.Remark,
+	})
+	return entry, err
+}
+
+// Refund 退回扣款, 可以分多次退, 累计不超过原扣款金额
+func (w *walletServiceImpl) Refund(ctx *gin.Context, req *wallet_dto.RefundReq) (*wallet_dto.WalletEntry, error) {
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	deduction, err := w.walletRepo.GetEntryById(ctx, tx, req.EntryID)
+	if err != nil {
+		return nil, err
+	}
+	if deduction == nil || deduction.Type != wallet_dto.EntryTypeDeduction {
+		err = sm_error.NewHttpError(error_code.WalletEntryNoExists)
+		return nil, err
+	}
+	wallet, err := w.walletRepo.GetById(ctx, tx, deduction.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet == nil {
+		err = sm_error.NewHttpError(error_code.WalletNoExists)
+		return nil, err
+	}
+	err = checkCredit(ctx, &wallet_dto.WalletOwner{OwnerType: wallet.OwnerType, OwnerID: wallet.OwnerID})
+	if err != nil {
+		return nil, err
+	}
+	entry, err := w.addEntry(ctx, tx, deduction.WalletID, &wallet_dto.WalletEntry{
+		Type:       wallet_dto.EntryTypeRefund,
+		Amount:     req.Amount,
+		RefEntryID: deduction.ID,
+		Reference:  deduction.Reference,
+		Remark:     req.Remark,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 新增流水前钱包行已被锁定, 同一钱包的退款依次执行, 此时的累计金额不会被并发修改
+	refunded, err := w.walletRepo.SumRefund(ctx, tx, deduction.ID)
+	if err != nil {
+		return nil, err
+	}
+	if refunded > -deduction.Amount {
+		err = sm_error.NewHttpError(error_code.WalletRefundExceeded)
+		return nil, err
+	}
+	return entry, nil
+}
+
+// addEntry 先修改余额再写入流水, 余额修改语句同时完成余额校验并锁定钱包, 保证并发扣款不会透支
+func (w *walletServiceImpl) addEntry(ctx *gin.Context, tx *gorm.DB, walletId string, entry *wallet_dto.WalletEntry) (*wallet_dto.WalletEntry, error) {
+	ok, err := w.walletRepo.ChangeBalance(ctx, tx, walletId, entry.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sm_error.NewHttpError(error_code.WalletInsufficientBalance)
+	}
+	wallet, err := w.walletRepo.GetById(ctx, tx, walletId)
+	if err != nil {
+		return nil, err
+	}
+	entry.WalletID = walletId
+	entry.BalanceAfter = wallet.Balance
+	entry.OperatorID = util.GetUserIdByCookie(ctx)
+	err = w.walletRepo.AddEntry(ctx, tx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// Reconcile 按流水重新计算余额, 修正缓存的余额
+func (w *walletServiceImpl) Reconcile(ctx *gin.Context, req *wallet_dto.WalletOwner) (*wallet_dto.Wallet, error) {
+	var err error
+	tx := util.GetDBFromContext(ctx).Begin()
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+	wallet, err := w.getWallet(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	// 先锁定钱包, 避免计算期间有新的流水写入
+	_, err = w.walletRepo.ChangeBalance(ctx, tx, wallet.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := w.walletRepo.SumAmount(ctx, tx, wallet.ID, nil, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	if balance != wallet.Balance {
+		vars.Log.Warnf("walletServiceImpl.Reconcile wallet_id:%s cached:%s ledger:%s", wallet.ID, wallet.Balance, balance)
+		err = w.walletRepo.SetBalance(ctx, tx, wallet.ID, balance)
+		if err != nil {
+			return nil, err
+		}
+		wallet.Balance = balance
+	}
+	return wallet, nil
+}
+
+func (w *walletServiceImpl) Stat(ctx *gin.Context, req *wallet_dto.WalletOwner) (*wallet_dto.WalletStatistic, error) {
+	db := util.GetDBFromContext(ctx)
+	wallet, err := w.getWallet(ctx, db, req)
+	if err != nil {
+		return nil, err
+	}
+	total, err := w.walletRepo.SumAmount(ctx, db, wallet.ID, usageTypes, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	lastWeek, err := w.walletRepo.SumAmount(ctx, db, wallet.ID, usageTypes, today().AddDate(0, 0, -6))
+	if err != nil {
+		return nil, err
+	}
+	// 扣款为负数, 消费金额取反
+	return &wallet_dto.WalletStatistic{
+		Currency:      wallet.Currency,
+		TotalUsage:    -total,
+		LastWeekUsage: -lastWeek,
+	}, nil
+}
+
+// Trend 最近若干天每天的消费金额, 包括当天, 没有消费的日期为0
+func (w *walletServiceImpl) Trend(ctx *gin.Context, req *wallet_dto.WalletTrendReq) ([]*wallet_dto.WalletTrendItem, error) {
+	db := util.GetDBFromContext(ctx)
+	wallet, err := w.getWallet(ctx, db, &req.WalletOwner)
+	if err != nil {
+		return nil, err
+	}
+	start := today().AddDate(0, 0, 1-req.Days)
+	entries, err := w.walletRepo.ListEntrySince(ctx, db, wallet.ID, usageTypes, start)
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[string]util.Money)
+	for _, entry := range entries {
+		usage[entry.CreateTime.Format("2006-01-02")] -= entry.Amount
+	}
+	list := make([]*wallet_dto.WalletTrendItem, 0, req.Days)
+	for day := start; len(list) < req.Days; day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		list = append(list, &wallet_dto.WalletTrendItem{Day: key, Usage: usage[key]})
+	}
+	return list, nil
+}
+
+func (w *walletServiceImpl) getWallet(ctx *gin.Context, db *gorm.DB, owner *wallet_dto.WalletOwner) (*wallet_dto.Wallet, error) {
+	wallet, err := w.walletRepo.GetByOwner(ctx, db, owner.OwnerType, owner.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if wallet == nil {
+		return nil, sm_error.NewHttpError(error_code.WalletNoExists)
+	}
+	return wallet, nil
+}
+
+// checkOwner 团队成员的钱包只能属于本团队的所有者或子账号, 客户编号由业务自行维护
+func (w *walletServiceImpl) checkOwner(ctx *gin.Context, db *gorm.DB, owner *wallet_dto.WalletOwner) error {
+	if owner.OwnerType != wallet_dto.OwnerTypeUser {
+		return nil
+	}
+	teamId := util.GetTeamId(ctx)
+	if owner.OwnerID == teamId {
+		return nil
+	}
+	ok, err := w.userTeamRepo.IsMember(ctx, db, teamId, owner.OwnerID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return sm_error.NewHttpError(error_code.WalletOwnerInvalid)
+	}
+	return nil
+}
+
+// checkCredit 子账号不能给自己的钱包充值或退款, 团队所有者不受限制
+func checkCredit(ctx *gin.Context, owner *wallet_dto.WalletOwner) error {
+	userId := util.GetUserIdByCookie(ctx)
+	if owner.OwnerType == wallet_dto.OwnerTypeUser && owner.OwnerID == userId && userId != util.GetTeamId(ctx) {
+		return sm_error.NewHttpError(error_code.WalletSelfCredit)
+	}
+	return nil
+}
+
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+}
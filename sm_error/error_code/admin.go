@@ -0,0 +1,7 @@
+package error_code
+
+const (
+	AdminNoPermission       = 10130001
+	AdminTargetNotAllowed   = 10130002
+	ImpersonationNotAllowed = 10130003
+)
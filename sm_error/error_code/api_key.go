@@ -0,0 +1,10 @@
+package error_code
+
+const (
+	ApiKeyNoExists         = 10120001
+	ApiKeyScopeInvalid     = 10120002
+	ApiKeySignatureInvalid = 10120003
+	ApiKeyRequestExpired   = 10120004
+	ApiKeyNonceReused      = 10120005
+	ApiKeyNotAllowed       = 10120006
+)
@@ -0,0 +1,8 @@
+package error_code
+
+const (
+	CurrencyCodeInvalid   = 10110001
+	CurrencyRateInvalid   = 10110002
+	CurrencyRateNoExists  = 10110003
+	CurrencyImportInvalid = 10110004
+)
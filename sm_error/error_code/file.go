@@ -1,6 +1,7 @@
 package error_code
 
 const (
-	FileUploadError  = 10040001
-	FileSizeOutOfMax = 10040002
+	FileUploadError    = 10040001
+	FileSizeOutOfMax   = 10040002
+	FileTypeNotAllowed = 10040003
 )
@@ -0,0 +1,9 @@
+package error_code
+
+const (
+	PickTaskNoExists       = 10060001
+	PickTaskClaimed        = 10060002
+	PickTaskNotClaimedByMe = 10060003
+	PickTaskUnfinished     = 10060004
+	PackScanMismatch       = 10060005
+)
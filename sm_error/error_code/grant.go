@@ -0,0 +1,7 @@
+package error_code
+
+const (
+	GrantInvalid          = 10150001
+	GrantNoExists         = 10150002
+	WarehouseNoPermission = 10150003
+)
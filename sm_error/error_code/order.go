@@ -0,0 +1,9 @@
+package error_code
+
+const (
+	OrderNoExists        = 10050001
+	OrderStatusIncorrect = 10050002
+	OrderItemEmpty       = 10050003
+	OrderProductNoExists = 10050004
+	OrderNoPermission    = 10050005
+)
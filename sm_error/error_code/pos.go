@@ -0,0 +1,11 @@
+package error_code
+
+const (
+	PosShiftNotOpen       = 10080001
+	PosShiftAlreadyOpen   = 10080002
+	PosCodeNotFound       = 10080003
+	PosTenderInsufficient = 10080004
+	PosTenderInvalid      = 10080005
+	PosStockInsufficient  = 10080006
+	PosItemEmpty          = 10080007
+)
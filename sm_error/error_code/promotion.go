@@ -0,0 +1,9 @@
+package error_code
+
+const (
+	PromotionNoExists       = 10070001
+	PromotionRuleInvalid    = 10070002
+	PromotionUsageExceeded  = 10070003
+	PromotionCouponUsed     = 10070004
+	PromotionCouponRejected = 10070005
+)
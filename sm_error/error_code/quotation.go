@@ -0,0 +1,10 @@
+package error_code
+
+const (
+	QuotationNoExists          = 10090001
+	QuotationStatusIncorrect   = 10090002
+	QuotationItemEmpty         = 10090003
+	QuotationExpired           = 10090004
+	QuotationStockInsufficient = 10090005
+	QuotationNoPermission      = 10090006
+)
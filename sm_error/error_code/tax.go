@@ -0,0 +1,8 @@
+package error_code
+
+const (
+	TaxRuleInvalid       = 10100001
+	TaxRuleNoExists      = 10100002
+	TaxExemptionExists   = 10100003
+	TaxExemptionNoExists = 10100004
+)
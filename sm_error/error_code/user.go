@@ -1,11 +1,39 @@
 package error_code
 
 const (
-	UserPhoneExists              = 10020001
-	UserConfirmPasswordIncorrect = 10020002
-	UserLoginFailed              = 10020003
-	UserModifyPasswordFailed     = 10020004
-	UserTokenError               = 10020005
-	UserNoExists                 = 10020006
-	UserSecretIncorrect          = 100020007
+	UserPhoneExists               = 10020001
+	UserConfirmPasswordIncorrect  = 10020002
+	UserLoginFailed               = 10020003
+	UserModifyPasswordFailed      = 10020004
+	UserTokenError                = 10020005
+	UserNoExists                  = 10020006
+	UserSecretIncorrect           = 100020007
+	UserResetCodeIncorrect        = 10020008
+	UserResetCodeTooFrequent      = 10020009
+	UserSendMessageFailed         = 10020010
+	UserNoPermission              = 10020011
+	UserRoleInvalid               = 10020012
+	UserTeamMemberExists          = 10020013
+	UserTeamNoExists              = 10020014
+	UserInvitationInvalid         = 10020015
+	UserInvitationMismatch        = 10020016
+	UserInvitationTargetRequired  = 10020017
+	UserSessionNoExists           = 10020018
+	UserLoginLocked               = 10020019
+	UserLoginTooFrequent          = 10020020
+	UserTwoFactorCodeIncorrect    = 10020021
+	UserTwoFactorEnabled          = 10020022
+	UserTwoFactorNotEnabled       = 10020023
+	UserTwoFactorChallengeInvalid = 10020024
+	UserEmailExists               = 10020025
+	UserEmailInvalid              = 10020026
+	UserDeactivated               = 10020027
+	UserTeamNotEmpty              = 10020028
+	UserDisabled                  = 10020029
+	OidcNotEnabled                = 10020030
+	OidcStateInvalid              = 10020031
+	OidcLoginFailed               = 10020032
+	OidcAccountNotLinked          = 10020033
+	OidcAccountConflict           = 10020034
+	UserReactivateNotAllowed      = 10020035
 )
@@ -0,0 +1,10 @@
+package error_code
+
+const (
+	WalletNoExists            = 10140001
+	WalletInsufficientBalance = 10140002
+	WalletEntryNoExists       = 10140003
+	WalletRefundExceeded      = 10140004
+	WalletOwnerInvalid        = 10140005
+	WalletSelfCredit          = 10140006
+)
@@ -14,7 +14,93 @@ func init() {
 	ErrMap[error_code.UserPhoneExists] = "手机号已经存在"
 	ErrMap[error_code.ReqParamError] = "请求参数错误"
 	ErrMap[error_code.UserNoExists] = "用户不存在"
+	ErrMap[error_code.UserModifyPasswordFailed] = "修改密码失败"
+	ErrMap[error_code.UserResetCodeIncorrect] = "验证码错误或已过期"
+	ErrMap[error_code.UserResetCodeTooFrequent] = "验证码发送过于频繁, 请稍后再试"
+	ErrMap[error_code.UserSendMessageFailed] = "消息发送失败"
+	ErrMap[error_code.UserNoPermission] = "当前角色无权执行该操作"
+	ErrMap[error_code.UserRoleInvalid] = "角色不正确"
+	ErrMap[error_code.UserTeamMemberExists] = "该用户已加入团队"
+	ErrMap[error_code.UserTeamNoExists] = "团队成员不存在"
+	ErrMap[error_code.UserInvitationInvalid] = "邀请无效或已处理"
+	ErrMap[error_code.UserInvitationMismatch] = "当前账号不是被邀请人"
+	ErrMap[error_code.UserInvitationTargetRequired] = "请填写被邀请人的手机号或邮箱"
+	ErrMap[error_code.UserSessionNoExists] = "登录会话不存在"
+	ErrMap[error_code.UserLoginFailed] = "手机号或密码错误"
+	ErrMap[error_code.UserLoginLocked] = "登录失败次数过多, 账号已临时锁定"
+	ErrMap[error_code.UserLoginTooFrequent] = "登录失败次数过多, 请稍后再试"
+	ErrMap[error_code.UserTwoFactorCodeIncorrect] = "动态码或恢复码不正确"
+	ErrMap[error_code.UserTwoFactorEnabled] = "已开启两步验证"
+	ErrMap[error_code.UserTwoFactorNotEnabled] = "未开启两步验证"
+	ErrMap[error_code.UserTwoFactorChallengeInvalid] = "登录验证已失效, 请重新登录"
+	ErrMap[error_code.UserEmailExists] = "邮箱已被其他账号使用"
+	ErrMap[error_code.UserEmailInvalid] = "邮箱格式不正确"
+	ErrMap[error_code.UserDeactivated] = "账号已停用, 请联系团队所有者恢复"
+	ErrMap[error_code.UserTeamNotEmpty] = "请先移除团队中的子账号"
+	ErrMap[error_code.UserDisabled] = "账号已被禁用, 请联系平台客服"
+	ErrMap[error_code.OidcNotEnabled] = "未启用单点登录"
+	ErrMap[error_code.OidcStateInvalid] = "登录已过期, 请重新登录"
+	ErrMap[error_code.OidcLoginFailed] = "单点登录失败, 请稍后重试"
+	ErrMap[error_code.OidcAccountNotLinked] = "该企业账号尚未关联, 请先登录后在账号设置中关联"
+	ErrMap[error_code.OidcAccountConflict] = "该企业账号已关联其他账号"
+	ErrMap[error_code.UserReactivateNotAllowed] = "子账号需由团队所有者恢复"
+	ErrMap[error_code.FileSizeOutOfMax] = "文件大小超出限制"
+	ErrMap[error_code.FileTypeNotAllowed] = "仅支持 JPG, PNG, GIF, WEBP 格式的图片"
 	ErrMap[error_code.DBError] = "数据库出错"
+	ErrMap[error_code.OrderNoExists] = "订单不存在"
+	ErrMap[error_code.OrderStatusIncorrect] = "订单状态不正确"
+	ErrMap[error_code.OrderItemEmpty] = "订单明细不能为空"
+	ErrMap[error_code.OrderProductNoExists] = "订单商品不存在"
+	ErrMap[error_code.OrderNoPermission] = "无权操作该订单"
+	ErrMap[error_code.PickTaskNoExists] = "拣货任务不存在"
+	ErrMap[error_code.PickTaskClaimed] = "拣货任务已被领取"
+	ErrMap[error_code.PickTaskNotClaimedByMe] = "拣货任务未被当前用户领取"
+	ErrMap[error_code.PickTaskUnfinished] = "存在未完成的拣货任务"
+	ErrMap[error_code.PackScanMismatch] = "扫描的库位码与订单商品不一致"
+	ErrMap[error_code.PromotionNoExists] = "促销活动不存在"
+	ErrMap[error_code.PromotionRuleInvalid] = "促销规则不正确"
+	ErrMap[error_code.PromotionUsageExceeded] = "促销活动使用次数已达上限"
+	ErrMap[error_code.PromotionCouponUsed] = "该客户已使用过此优惠券"
+	ErrMap[error_code.PromotionCouponRejected] = "优惠券不可用"
+	ErrMap[error_code.PosShiftNotOpen] = "当前没有营业中的收银班次"
+	ErrMap[error_code.PosShiftAlreadyOpen] = "已有营业中的收银班次"
+	ErrMap[error_code.PosCodeNotFound] = "条码或库位码未找到商品"
+	ErrMap[error_code.PosTenderInsufficient] = "支付金额不足"
+	ErrMap[error_code.PosTenderInvalid] = "支付方式或金额不正确"
+	ErrMap[error_code.PosStockInsufficient] = "商品库存不足"
+	ErrMap[error_code.PosItemEmpty] = "结账商品不能为空"
+	ErrMap[error_code.QuotationNoExists] = "报价单不存在"
+	ErrMap[error_code.QuotationStatusIncorrect] = "报价单状态不正确"
+	ErrMap[error_code.QuotationItemEmpty] = "报价单明细不能为空"
+	ErrMap[error_code.QuotationExpired] = "报价单已过期"
+	ErrMap[error_code.QuotationStockInsufficient] = "商品可用库存不足, 无法预留"
+	ErrMap[error_code.QuotationNoPermission] = "无权操作该报价单"
+	ErrMap[error_code.TaxRuleInvalid] = "税率需在0到100之间"
+	ErrMap[error_code.TaxRuleNoExists] = "税率规则不存在"
+	ErrMap[error_code.TaxExemptionExists] = "该客户已设置免税"
+	ErrMap[error_code.TaxExemptionNoExists] = "该客户未设置免税"
+	ErrMap[error_code.CurrencyCodeInvalid] = "币种代码不正确"
+	ErrMap[error_code.CurrencyRateInvalid] = "汇率必须大于0"
+	ErrMap[error_code.CurrencyRateNoExists] = "未找到对应日期的汇率"
+	ErrMap[error_code.CurrencyImportInvalid] = "汇率文件格式不正确"
+	ErrMap[error_code.ApiKeyNoExists] = "API Key 不存在或已吊销"
+	ErrMap[error_code.ApiKeyScopeInvalid] = "API Key 权限范围不正确"
+	ErrMap[error_code.ApiKeySignatureInvalid] = "请求签名不正确"
+	ErrMap[error_code.ApiKeyRequestExpired] = "请求时间戳已过期"
+	ErrMap[error_code.ApiKeyNonceReused] = "请求已被使用, 请勿重放"
+	ErrMap[error_code.ApiKeyNotAllowed] = "该接口不支持 API Key 访问"
+	ErrMap[error_code.AdminNoPermission] = "需要平台管理员权限"
+	ErrMap[error_code.AdminTargetNotAllowed] = "不能对该账号执行此操作"
+	ErrMap[error_code.ImpersonationNotAllowed] = "模拟登录的会话不能执行该操作"
+	ErrMap[error_code.WalletNoExists] = "钱包不存在, 请先充值"
+	ErrMap[error_code.WalletInsufficientBalance] = "钱包余额不足"
+	ErrMap[error_code.WalletEntryNoExists] = "扣款记录不存在"
+	ErrMap[error_code.WalletRefundExceeded] = "退款金额超过扣款金额"
+	ErrMap[error_code.WalletOwnerInvalid] = "钱包所属用户不在本团队"
+	ErrMap[error_code.WalletSelfCredit] = "不能给自己的钱包充值或退款"
+	ErrMap[error_code.GrantInvalid] = "授权的资源类型或操作不正确"
+	ErrMap[error_code.GrantNoExists] = "授权不存在"
+	ErrMap[error_code.WarehouseNoPermission] = "没有该仓库的权限"
 }
 
 // define 000 00000
@@ -0,0 +1,311 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reflect"
+	"time"
+)
+
+const (
+	AuditTable = "audit_log"
+
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+
+	// 平台管理员的操作, 不一定伴随数据变更, 由管理接口显式记录
+	AuditActionSearch      = "search"
+	AuditActionView        = "view"
+	AuditActionDisable     = "disable"
+	AuditActionEnable      = "enable"
+	AuditActionImpersonate = "impersonate"
+
+	auditBeforeKey = "audit:before"
+)
+
+// auditSensitiveColumns 只记录发生了变更, 不记录内容
+var auditSensitiveColumns = map[string]bool{
+	"password":    true,
+	"secret":      true,
+	"secret_salt": true,
+	"secret_hash": true,
+	"code_hash":   true,
+}
+
+// auditIgnoreColumns 每次更新都会变化, 不计入差异
+var auditIgnoreColumns = map[string]bool{
+	"modify_time": true,
+}
+
+// RegisterAudit 记录请求中每一次新增/修改/删除: 操作人, 团队, 表名与主键, 变更前后的差异, IP 和请求id.
+// 修改和删除前按同样的条件查询一次原始数据, 修改后再按主键查询一次新数据.
+// 只记录 HTTP 请求中的操作, 定时任务等没有请求id的操作不记录
+func RegisterAudit(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:create").Register("audit:create", auditCreate); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").After(teamScopeCallback).Register("audit:before_update", auditSnapshot); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register("audit:update", auditUpdate); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").After(teamScopeCallback).Register("audit:before_delete", auditSnapshot); err != nil {
+		return err
+	}
+	return callback.Delete().After("gorm:delete").Register("audit:delete", auditDelete)
+}
+
+type auditEntry struct {
+	teamId   string
+	entityId string
+	action   string
+	before   map[string]interface{}
+	after    map[string]interface{}
+}
+
+func auditCreate(db *gorm.DB) {
+	if !auditEnabled(db) || db.Statement.Schema == nil || db.RowsAffected == 0 {
+		return
+	}
+	stmt := db.Statement
+	pk := stmt.Schema.PrioritizedPrimaryField
+	if pk == nil {
+		return
+	}
+	rows := make([]reflect.Value, 0)
+	switch value := stmt.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			rows = append(rows, reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		rows = append(rows, value)
+	}
+	for _, row := range rows {
+		after := make(map[string]interface{})
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			value, _ := field.ValueOf(stmt.Context, row)
+			after[field.DBName] = value
+		}
+		writeAudit(db, &auditEntry{
+			teamId:   auditString(after["team_id"]),
+			entityId: auditString(after[pk.DBName]),
+			action:   AuditActionCreate,
+			after:    redactAudit(after),
+		})
+	}
+}
+
+// auditSnapshot 修改/删除前按语句的查询条件读取原始数据
+func auditSnapshot(db *gorm.DB) {
+	if !auditEnabled(db) {
+		return
+	}
+	stmt := db.Statement
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(stmt.Table)
+	hasCondition := false
+	if where, ok := stmt.Clauses["WHERE"]; ok {
+		if expression, ok := where.Expression.(clause.Where); ok && len(expression.Exprs) > 0 {
+			tx.Statement.AddClause(expression)
+			hasCondition = true
+		}
+	}
+	// 以模型主键修改/删除时, 主键条件在执行时才追加, 这里先补上
+	if stmt.Schema != nil && stmt.Schema.PrioritizedPrimaryField != nil && stmt.ReflectValue.Kind() == reflect.Struct {
+		pk := stmt.Schema.PrioritizedPrimaryField
+		if value, zero := pk.ValueOf(stmt.Context, stmt.ReflectValue); !zero {
+			tx = tx.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: pk.DBName}, Value: value})
+			hasCondition = true
+		}
+	}
+	// 没有条件的全表修改会被 gorm 拒绝, 无需记录
+	if !hasCondition {
+		return
+	}
+	rows := make([]map[string]interface{}, 0)
+	if err := tx.Find(&rows).Error; err != nil {
+		vars.Log.Errorf("auditSnapshot error:%v, table:%s", err, stmt.Table)
+		return
+	}
+	db.InstanceSet(auditBeforeKey, rows)
+}
+
+func auditUpdate(db *gorm.DB) {
+	before, ok := auditBeforeRows(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+	pk := auditPrimaryKey(db)
+	ids := make([]interface{}, 0, len(before))
+	for _, row := range before {
+		ids = append(ids, normalizeAuditValue(row[pk]))
+	}
+	afterRows := make([]map[string]interface{}, 0)
+	err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(db.Statement.Table).
+		Where(clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: pk}, Values: ids}).Find(&afterRows).Error
+	if err != nil {
+		vars.Log.Errorf("auditUpdate error:%v, table:%s", err, db.Statement.Table)
+		return
+	}
+	afterById := make(map[string]map[string]interface{}, len(afterRows))
+	for _, row := range afterRows {
+		afterById[auditString(row[pk])] = row
+	}
+	for _, row := range before {
+		id := auditString(row[pk])
+		after, ok := afterById[id]
+		if !ok {
+			continue
+		}
+		beforeDiff := make(map[string]interface{})
+		afterDiff := make(map[string]interface{})
+		for column, value := range after {
+			if auditIgnoreColumns[column] {
+				continue
+			}
+			old := normalizeAuditValue(row[column])
+			value = normalizeAuditValue(value)
+			if reflect.DeepEqual(old, value) {
+				continue
+			}
+			beforeDiff[column] = old
+			afterDiff[column] = value
+		}
+		if len(afterDiff) == 0 {
+			continue
+		}
+		writeAudit(db, &auditEntry{
+			teamId:   auditString(after["team_id"]),
+			entityId: id,
+			action:   AuditActionUpdate,
+			before:   redactAudit(beforeDiff),
+			after:    redactAudit(afterDiff),
+		})
+	}
+}
+
+func auditDelete(db *gorm.DB) {
+	before, ok := auditBeforeRows(db)
+	if !ok || db.RowsAffected == 0 {
+		return
+	}
+	pk := auditPrimaryKey(db)
+	for _, row := range before {
+		for column, value := range row {
+			row[column] = normalizeAuditValue(value)
+		}
+		writeAudit(db, &auditEntry{
+			teamId:   auditString(row["team_id"]),
+			entityId: auditString(row[pk]),
+			action:   AuditActionDelete,
+			before:   redactAudit(row),
+		})
+	}
+}
+
+func auditEnabled(db *gorm.DB) bool {
+	return db.Error == nil && !db.DryRun && db.Statement.Table != "" && db.Statement.Table != AuditTable &&
+		contextString(db.Statement.Context, vars.RequestIdMetadataName) != ""
+}
+
+func auditBeforeRows(db *gorm.DB) ([]map[string]interface{}, bool) {
+	if !auditEnabled(db) {
+		return nil, false
+	}
+	value, ok := db.InstanceGet(auditBeforeKey)
+	if !ok {
+		return nil, false
+	}
+	rows, ok := value.([]map[string]interface{})
+	return rows, ok && len(rows) > 0
+}
+
+func auditPrimaryKey(db *gorm.DB) string {
+	if db.Statement.Schema != nil && db.Statement.Schema.PrioritizedPrimaryField != nil {
+		return db.Statement.Schema.PrioritizedPrimaryField.DBName
+	}
+	return "id"
+}
+
+// writeAudit 与业务操作使用同一连接, 事务回滚时审计记录一并回滚. 写入失败只记录日志, 不影响业务操作
+func writeAudit(db *gorm.DB, entry *auditEntry) {
+	ctx := db.Statement.Context
+	teamId := entry.teamId
+	if teamId == "" {
+		teamId = teamIdFromContext(ctx)
+	}
+	record := map[string]interface{}{
+		"id":              uuid.NewString(),
+		"team_id":         teamId,
+		"actor_id":        contextString(ctx, vars.UserIdMetadataName),
+		"impersonator_id": contextString(ctx, vars.ImpersonatorIdMetadataName),
+		"action":          entry.action,
+		"entity_type":     db.Statement.Table,
+		"entity_id":       entry.entityId,
+		"before_data":     marshalAudit(entry.before),
+		"after_data":      marshalAudit(entry.after),
+		"ip":              contextString(ctx, vars.ClientIpMetadataName),
+		"request_id":      contextString(ctx, vars.RequestIdMetadataName),
+		"create_time":     time.Now(),
+	}
+	err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(AuditTable).Create(record).Error
+	if err != nil {
+		vars.Log.Errorf("writeAudit error:%v, table:%s, id:%s", err, db.Statement.Table, entry.entityId)
+	}
+}
+
+func redactAudit(values map[string]interface{}) map[string]interface{} {
+	for column := range values {
+		if auditSensitiveColumns[column] {
+			values[column] = "******"
+		}
+	}
+	return values
+}
+
+// normalizeAuditValue 数据库驱动返回的字符串可能是 []byte, 统一为 string 以便比较和序列化
+func normalizeAuditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+	return value
+}
+
+func auditString(value interface{}) string {
+	value = normalizeAuditValue(value)
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func marshalAudit(values map[string]interface{}) string {
+	if values == nil {
+		return ""
+	}
+	return MarshalToStringNoErr(values)
+}
+
+func contextString(ctx context.Context, key string) string {
+	if ctx == nil {
+		return ""
+	}
+	value, _ := ctx.Value(key).(string)
+	return value
+}
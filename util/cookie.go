@@ -2,9 +2,14 @@ package util
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/shop_management/vars"
 )
 
+// GetUserIdByCookie 当前登录用户. API Key 签名请求没有 cookie, 由校验中间件写入上下文
 func GetUserIdByCookie(ctx *gin.Context) string {
+	if userId := ctx.GetString(vars.UserIdMetadataName); userId != "" {
+		return userId
+	}
 	userId, _ := ctx.Cookie("user_id")
 	return userId
 }
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// encryptKey 由配置 token.encrypt_key 派生的 AES-256 密钥, 不随进程重启变化
+var encryptKey []byte
+
+// SetEncryptKey 只在启动时调用
+func SetEncryptKey(key string) {
+	if key == "" {
+		encryptKey = nil
+		return
+	}
+	sum := sha256.Sum256([]byte(key))
+	encryptKey = sum[:]
+}
+
+// EncryptSecret 使用 AES-256-GCM 加密需要还原的敏感数据(如 TOTP 密钥)
+func EncryptSecret(plain string) (string, error) {
+	aead, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func DecryptSecret(encrypted string) (string, error) {
+	aead, err := secretCipher()
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.RawStdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < aead.NonceSize() {
+		return "", errors.New("encrypted secret too short")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func secretCipher() (cipher.AEAD, error) {
+	if encryptKey == nil {
+		return nil, errors.New("encrypt key not configured")
+	}
+	block, err := aes.NewCipher(encryptKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
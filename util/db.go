@@ -1,30 +1,45 @@
 package util
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/shop_management/config"
 	"github.com/shop_management/vars"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"os"
 )
 
-func GetDB() (*gorm.DB, error) {
-	dbName := os.Getenv("SM_DB_NAME")
-	if dbName == "" {
-		dbName = "shop_management_dev"
+// OpenDB 启动时创建连接池, 整个进程共用, 不要在请求中调用
+func OpenDB(cfg *config.MysqlConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(mysql.Open(cfg.DSN()), &gorm.Config{})
+	if err != nil {
+		return nil, err
 	}
-	dsn := fmt.Sprintf("root:ljg098098@tcp(120.24.169.86:3306)/%s?charset=utf8mb4&parseTime=True&loc=Local", dbName)
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 	db.Config.CreateBatchSize = 100
 	// 设置创建时的插件
-	return db.Debug(), nil
+	err = RegisterTeamScope(db)
+	if err != nil {
+		return nil, err
+	}
+	err = RegisterAudit(db)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Debug {
+		db = db.Debug()
+	}
+	return db, nil
 }
 
 func GetDBFromContext(ctx *gin.Context) *gorm.DB {
 	value, _ := ctx.Get(vars.DbMetadataName)
-	return value.(*gorm.DB).Debug()
+	// 绑定请求上下文, 团队隔离回调从中读取团队id
+	return value.(*gorm.DB).WithContext(ctx)
 }
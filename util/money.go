@@ -0,0 +1,170 @@
+package util
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money 定点金额, 以分为单位存储, 避免 float64 累加产生的分差.
+// 数据库列为 decimal(10,2), 读写时与 "12.34" 形式的字符串互转;
+// JSON 输出为字符串 "12.34", 输入同时接受字符串和数字.
+type Money int64
+
+// MoneyFromFloat 用于汇率, 税率等按比例计算后的结果, 四舍五入到分
+func MoneyFromFloat(v float64) Money {
+	return Money(math.Round(v * 100))
+}
+
+// ParseMoney 按十进制文本精确解析, 超过两位的小数四舍五入到分, 至少包含一位数字
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	text := s
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, fmt.Errorf("invalid money: %q", text)
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	roundUp := len(fracPart) > 2 && fracPart[2] >= '5'
+	fracPart = (fracPart + "00")[:2]
+	yuan, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || yuan > math.MaxInt64/100 {
+		return 0, fmt.Errorf("money out of range: %q", text)
+	}
+	cents, _ := strconv.ParseInt(fracPart, 10, 64)
+	m := Money(yuan*100 + cents)
+	if roundUp {
+		m++
+	}
+	// 整数部分恰好为上限时, 加上分仍可能溢出
+	if m < 0 {
+		return 0, fmt.Errorf("money out of range: %q", text)
+	}
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// Mul 单价乘以数量
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// MulRate 按比例计算, 如 MulRate(0.13) 计算 13% 的税额, 结果四舍五入到分
+func (m Money) MulRate(rate float64) Money {
+	return MoneyFromFloat(m.Float64() * rate)
+}
+
+// MulDiv 计算 m * num / den 并四舍五入到分, 用于按金额比例分摊
+func (m Money) MulDiv(num Money, den Money) Money {
+	if den == 0 {
+		return 0
+	}
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(num))), big.NewInt(int64(den)))
+	f, _ := r.Float64()
+	return Money(math.Round(f))
+}
+
+func MinMoney(a Money, b Money) Money {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		parsed, err := ParseMoney(string(v))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case string:
+		parsed, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case int64:
+		*m = Money(v * 100)
+		return nil
+	case float64:
+		*m = MoneyFromFloat(v)
+		return nil
+	}
+	return fmt.Errorf("can not scan %T into Money", value)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	// 只去掉成对的引号, 单侧引号按非法金额处理
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
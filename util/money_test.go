@@ -0,0 +1,185 @@
+package util
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "0", want: 0},
+		{in: "12", want: 1200},
+		{in: "12.3", want: 1230},
+		{in: "12.34", want: 1234},
+		{in: " 12.34 ", want: 1234},
+		{in: "+12.34", want: 1234},
+		{in: "-12.34", want: -1234},
+		{in: ".5", want: 50},
+		{in: "12.", want: 1200},
+		{in: "12.344", want: 1234},
+		{in: "12.345", want: 1235},
+		{in: "-12.345", want: -1235},
+		{in: "0.1", want: 10},
+		{in: "abc", wantErr: true},
+		{in: "12.3a", wantErr: true},
+		{in: "1,000.00", wantErr: true},
+		{in: "1e3", wantErr: true},
+		{in: "--1", wantErr: true},
+		// 必须包含数字
+		{in: "-", wantErr: true},
+		{in: "+", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "-.", wantErr: true},
+		// 超出 int64 分的范围
+		{in: "92233720368547758.07", want: math.MaxInt64},
+		{in: "92233720368547758.08", wantErr: true},
+		{in: "92233720368547759", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{in: 0, want: "0.00"},
+		{in: 5, want: "0.05"},
+		{in: 1234, want: "12.34"},
+		{in: -5, want: "-0.05"},
+		{in: -1234, want: "-12.34"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	tests := []struct {
+		name string
+		got  Money
+		want Money
+	}{
+		{name: "Mul", got: Money(1999).Mul(3), want: 5997},
+		{name: "MulRate", got: Money(10000).MulRate(0.13), want: 1300},
+		{name: "MulRate rounds", got: Money(999).MulRate(0.13), want: 130},
+		{name: "MulDiv", got: Money(1000).MulDiv(1, 3), want: 333},
+		{name: "MulDiv rounds", got: Money(1000).MulDiv(2, 3), want: 667},
+		{name: "MulDiv zero den", got: Money(1000).MulDiv(1, 0), want: 0},
+		{name: "MoneyFromFloat", got: MoneyFromFloat(0.1 + 0.2), want: 30},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	type payload struct {
+		Amount Money `json:"amount"`
+	}
+	data, err := json.Marshal(payload{Amount: 1234})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":"12.34"}` {
+		t.Errorf("Marshal = %s", data)
+	}
+
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: `{"amount":"12.34"}`, want: 1234},
+		{in: `{"amount":12.34}`, want: 1234},
+		{in: `{"amount":12}`, want: 1200},
+		{in: `{"amount":"-0.5"}`, want: -50},
+		// null 保持原值
+		{in: `{"amount":null}`, want: 7},
+		{in: `{"amount":"12.3x"}`, wantErr: true},
+		{in: `{"amount":true}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		p := payload{Amount: 7}
+		err := json.Unmarshal([]byte(tt.in), &p)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Unmarshal(%s) = %v, want error", tt.in, p.Amount)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", tt.in, err)
+			continue
+		}
+		if p.Amount != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, p.Amount, tt.want)
+		}
+	}
+
+	// 直接调用时只接受成对的引号
+	for _, in := range []string{`"12.34`, `12.34"`, `"`} {
+		var m Money
+		if err := m.UnmarshalJSON([]byte(in)); err == nil {
+			t.Errorf("UnmarshalJSON(%s) = %v, want error", in, m)
+		}
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    Money
+		wantErr bool
+	}{
+		{in: nil, want: 0},
+		{in: []byte("12.34"), want: 1234},
+		{in: "0.99", want: 99},
+		{in: int64(3), want: 300},
+		{in: 1.005, want: 100},
+		{in: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		m := Money(1)
+		err := m.Scan(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Scan(%v) = %v, want error", tt.in, m)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Scan(%v) error: %v", tt.in, err)
+			continue
+		}
+		if m != tt.want {
+			t.Errorf("Scan(%v) = %d, want %d", tt.in, m, tt.want)
+		}
+	}
+}
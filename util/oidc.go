@@ -0,0 +1,427 @@
+package util
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oidcKeysTTL 签名公钥的缓存时间, 遇到未知的 kid 时提前刷新
+	oidcKeysTTL = 10 * time.Minute
+	// oidcClockSkew 校验 ID Token 有效期时允许的时钟误差
+	oidcClockSkew = time.Minute
+)
+
+var oidcEncoding = base64.RawURLEncoding
+
+// OidcConfig 身份提供方配置, Issuer 为空表示未启用单点登录
+type OidcConfig struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectUrl  string
+	Scopes       []string
+}
+
+func (c *OidcConfig) Enabled() bool {
+	return c.Issuer != "" && c.ClientId != ""
+}
+
+// OidcClaims 登录用户在身份提供方的信息, 合并自 ID Token 和 userinfo 接口
+type OidcClaims struct {
+	Subject             string
+	Name                string
+	Email               string
+	EmailVerified       bool
+	PhoneNumber         string
+	PhoneNumberVerified bool
+}
+
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JwksUri               string `json:"jwks_uri"`
+}
+
+// OidcClient 授权码模式(PKCE)的 OIDC 客户端. 提供方的地址通过 Issuer 的发现文档获取,
+// 因此只需配置 Issuer 即可对接任意提供方, 包括本地的模拟提供方
+type OidcClient struct {
+	config     *OidcConfig
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	provider      *oidcProvider
+	keys          map[string]crypto.PublicKey
+	keysFetchTime time.Time
+}
+
+func NewOidcClient(config *OidcConfig, httpClient *http.Client) *OidcClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OidcClient{config: config, httpClient: httpClient}
+}
+
+// NewPkceVerifier 生成 code_verifier 及其 S256 code_challenge
+func NewPkceVerifier() (string, string, error) {
+	verifier, err := oidcRandom(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, oidcEncoding.EncodeToString(sum[:]), nil
+}
+
+// NewOidcState 生成 state 和 nonce 使用的随机值
+func NewOidcState() (string, error) {
+	return oidcRandom(24)
+}
+
+func oidcRandom(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return oidcEncoding.EncodeToString(b), nil
+}
+
+// AuthCodeURL 跳转到身份提供方登录页的地址
+func (c *OidcClient) AuthCodeURL(ctx context.Context, state string, nonce string, challenge string) (string, error) {
+	provider, err := c.getProvider(ctx)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", c.config.ClientId)
+	query.Set("redirect_uri", c.config.RedirectUrl)
+	query.Set("scope", strings.Join(c.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", "S256")
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return provider.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange 用授权码换取令牌并校验 ID Token, ID Token 中没有邮箱或手机号时再查询 userinfo 接口
+func (c *OidcClient) Exchange(ctx context.Context, code string, verifier string, nonce string) (*OidcClaims, error) {
+	provider, err := c.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.config.RedirectUrl)
+	form.Set("client_id", c.config.ClientId)
+	form.Set("code_verifier", verifier)
+	if c.config.ClientSecret != "" {
+		form.Set("client_secret", c.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	token := &struct {
+		AccessToken string `json:"access_token"`
+		IdToken     string `json:"id_token"`
+	}{}
+	if err = c.doJson(req, token); err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	if token.IdToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+	values, err := c.verifyIdToken(ctx, token.IdToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+	claims := oidcClaimsFrom(values)
+	if (claims.Email == "" && claims.PhoneNumber == "") && provider.UserinfoEndpoint != "" && token.AccessToken != "" {
+		if err = c.mergeUserinfo(ctx, provider, token.AccessToken, claims); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+func (c *OidcClient) mergeUserinfo(ctx context.Context, provider *oidcProvider, accessToken string, claims *OidcClaims) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.UserinfoEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	values := make(map[string]interface{})
+	if err = c.doJson(req, &values); err != nil {
+		return fmt.Errorf("userinfo request: %w", err)
+	}
+	info := oidcClaimsFrom(values)
+	// userinfo 的 sub 必须与 ID Token 一致, 否则不能采信
+	if info.Subject != claims.Subject {
+		return errors.New("userinfo subject mismatch")
+	}
+	if claims.Name == "" {
+		claims.Name = info.Name
+	}
+	claims.Email, claims.EmailVerified = info.Email, info.EmailVerified
+	claims.PhoneNumber, claims.PhoneNumberVerified = info.PhoneNumber, info.PhoneNumberVerified
+	return nil
+}
+
+// verifyIdToken 校验签名(RS256/ES256), iss, aud, exp 和 nonce, 返回全部声明
+func (c *OidcClient) verifyIdToken(ctx context.Context, idToken string, nonce string) (map[string]interface{}, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed id_token")
+	}
+	header := &struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := oidcDecodeSegment(parts[0], header); err != nil {
+		return nil, err
+	}
+	signature, err := oidcEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("malformed id_token signature")
+	}
+	key, err := c.getKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch publicKey := key.(type) {
+	case *rsa.PublicKey:
+		if header.Alg != "RS256" || rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) != nil {
+			return nil, errors.New("invalid id_token signature")
+		}
+	case *ecdsa.PublicKey:
+		if header.Alg != "ES256" || len(signature) != 64 ||
+			!ecdsa.Verify(publicKey, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+			return nil, errors.New("invalid id_token signature")
+		}
+	default:
+		return nil, errors.New("unsupported id_token key")
+	}
+	values := make(map[string]interface{})
+	if err = oidcDecodeSegment(parts[1], &values); err != nil {
+		return nil, err
+	}
+	if iss, _ := values["iss"].(string); iss != c.config.Issuer {
+		return nil, errors.New("id_token issuer mismatch")
+	}
+	if !oidcAudienceContains(values["aud"], c.config.ClientId) {
+		return nil, errors.New("id_token audience mismatch")
+	}
+	exp, _ := values["exp"].(float64)
+	if time.Unix(int64(exp), 0).Add(oidcClockSkew).Before(time.Now()) {
+		return nil, errors.New("id_token expired")
+	}
+	if value, _ := values["nonce"].(string); value != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+	if sub, _ := values["sub"].(string); sub == "" {
+		return nil, errors.New("id_token has no subject")
+	}
+	return values, nil
+}
+
+func (c *OidcClient) getProvider(ctx context.Context) (*oidcProvider, error) {
+	c.mu.Lock()
+	provider := c.provider
+	c.mu.Unlock()
+	if provider != nil {
+		return provider, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	provider = &oidcProvider{}
+	if err = c.doJson(req, provider); err != nil {
+		return nil, fmt.Errorf("discovery request: %w", err)
+	}
+	if provider.Issuer != c.config.Issuer {
+		return nil, errors.New("discovery issuer mismatch")
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JwksUri == "" {
+		return nil, errors.New("discovery document incomplete")
+	}
+	c.mu.Lock()
+	c.provider = provider
+	c.mu.Unlock()
+	return provider, nil
+}
+
+func (c *OidcClient) getKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	key, ok := c.keys[kid]
+	fresh := time.Since(c.keysFetchTime) < oidcKeysTTL
+	c.mu.Unlock()
+	if ok && fresh {
+		return key, nil
+	}
+	provider, err := c.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.JwksUri, nil)
+	if err != nil {
+		return nil, err
+	}
+	jwks := &struct {
+		Keys []*oidcJwk `json:"keys"`
+	}{}
+	if err = c.doJson(req, jwks); err != nil {
+		return nil, fmt.Errorf("jwks request: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if publicKey, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = publicKey
+		}
+	}
+	c.mu.Lock()
+	c.keys = keys
+	c.keysFetchTime = time.Now()
+	c.mu.Unlock()
+	key, ok = keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown id_token key %q", kid)
+	}
+	return key, nil
+}
+
+func (c *OidcClient) doJson(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, out)
+}
+
+type oidcJwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *oidcJwk) publicKey() (crypto.PublicKey, error) {
+	if k.Use != "" && k.Use != "sig" {
+		return nil, errors.New("not a signing key")
+	}
+	switch k.Kty {
+	case "RSA":
+		n, err := oidcEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := oidcEncoding.DecodeString(k.E)
+		if err != nil || len(e) > 4 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, errors.New("unsupported curve")
+		}
+		x, err := oidcEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := oidcEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !publicKey.Curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return nil, errors.New("invalid ec key")
+		}
+		return publicKey, nil
+	}
+	return nil, errors.New("unsupported key type")
+}
+
+func oidcDecodeSegment(segment string, out interface{}) error {
+	data, err := oidcEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("malformed id_token")
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return errors.New("malformed id_token")
+	}
+	return nil
+}
+
+func oidcAudienceContains(aud interface{}, clientId string) bool {
+	switch value := aud.(type) {
+	case string:
+		return value == clientId
+	case []interface{}:
+		for _, item := range value {
+			if item == clientId {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func oidcClaimsFrom(values map[string]interface{}) *OidcClaims {
+	claims := &OidcClaims{}
+	claims.Subject, _ = values["sub"].(string)
+	claims.Name, _ = values["name"].(string)
+	claims.Email, _ = values["email"].(string)
+	claims.PhoneNumber, _ = values["phone_number"].(string)
+	claims.EmailVerified = oidcBool(values["email_verified"])
+	claims.PhoneNumberVerified = oidcBool(values["phone_number_verified"])
+	return claims
+}
+
+// oidcBool 部分提供方以字符串 "true" 返回布尔声明
+func oidcBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package util
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testOidcProvider 模拟的身份提供方, 提供发现文档, 公钥, 令牌和 userinfo 接口.
+// 令牌接口按 PKCE 校验 code_verifier, 返回 idToken 指定的 ID Token
+type testOidcProvider struct {
+	server    *httptest.Server
+	rsaKey    *rsa.PrivateKey
+	ecKey     *ecdsa.PrivateKey
+	challenge string
+	idToken   string
+	userinfo  map[string]interface{}
+}
+
+func newTestOidcProvider(t *testing.T) *testOidcProvider {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testOidcProvider{rsaKey: rsaKey, ecKey: ecKey}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJson(w, map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"userinfo_endpoint":      p.server.URL + "/userinfo",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJson(w, map[string]interface{}{"keys": []map[string]string{
+			{
+				"kty": "RSA", "kid": "rsa", "use": "sig",
+				"n": oidcEncoding.EncodeToString(rsaKey.N.Bytes()),
+				"e": oidcEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			{
+				"kty": "EC", "kid": "ec", "crv": "P-256",
+				"x": oidcEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+				"y": oidcEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32))),
+			},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+		if r.PostFormValue("grant_type") != "authorization_code" || oidcEncoding.EncodeToString(sum[:]) != p.challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		writeTestJson(w, map[string]string{"access_token": "access", "id_token": p.idToken})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		writeTestJson(w, p.userinfo)
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testOidcProvider) client() *OidcClient {
+	return NewOidcClient(&OidcConfig{
+		Issuer:      p.server.URL,
+		ClientId:    "client",
+		RedirectUrl: "https://shop.example.com/callback",
+		Scopes:      []string{"openid", "email"},
+	}, p.server.Client())
+}
+
+// claims 有效的 ID Token 声明, 各用例在此基础上修改
+func (p *testOidcProvider) claims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":   p.server.URL,
+		"aud":   "client",
+		"sub":   "user-1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": "nonce",
+		"email": "a@example.com",
+	}
+}
+
+func (p *testOidcProvider) sign(t *testing.T, alg string, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := oidcEncoding.EncodeToString(header) + "." + oidcEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	var signature []byte
+	switch kid {
+	case "ec":
+		r, s, err := ecdsa.Sign(rand.Reader, p.ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	default:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, p.rsaKey, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return signingInput + "." + oidcEncoding.EncodeToString(signature)
+}
+
+func writeTestJson(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestNewPkceVerifier(t *testing.T) {
+	verifier, challenge, err := NewPkceVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// RFC 7636 要求 code_verifier 为 43 到 128 个字符
+	if len(verifier) < 43 || len(verifier) > 128 {
+		t.Errorf("verifier length = %d", len(verifier))
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	if challenge != oidcEncoding.EncodeToString(sum[:]) {
+		t.Errorf("challenge %q is not S256 of verifier", challenge)
+	}
+	other, _, err := NewPkceVerifier()
+	if err != nil || other == verifier {
+		t.Errorf("verifier is not random: %q, %v", other, err)
+	}
+}
+
+func TestOidcAuthCodeURL(t *testing.T) {
+	p := newTestOidcProvider(t)
+	authUrl, err := p.client().AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Scheme+"://"+u.Host+u.Path != p.server.URL+"/authorize" {
+		t.Errorf("endpoint = %s", authUrl)
+	}
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             "client",
+		"redirect_uri":          "https://shop.example.com/callback",
+		"scope":                 "openid email",
+		"state":                 "state",
+		"nonce":                 "nonce",
+		"code_challenge":        "challenge",
+		"code_challenge_method": "S256",
+	}
+	for key, value := range want {
+		if got := u.Query().Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestOidcVerifyIdToken(t *testing.T) {
+	p := newTestOidcProvider(t)
+	tests := []struct {
+		name    string
+		alg     string
+		kid     string
+		modify  func(claims map[string]interface{})
+		tamper  func(token string) string
+		wantErr string
+	}{
+		{name: "rs256", alg: "RS256", kid: "rsa"},
+		{name: "es256", alg: "ES256", kid: "ec"},
+		{name: "audience list", alg: "RS256", kid: "rsa", modify: func(c map[string]interface{}) {
+			c["aud"] = []string{"other", "client"}
+		}},
+		{name: "expired within clock skew", alg: "RS256", kid: "rsa", modify: func(c map[string]interface{}) {
+			c["exp"] = time.Now().Add(-30 * time.Second).Unix()
+		}},
+		{name: "expired", alg: "RS256", kid: "rsa", wantErr: "expired", modify: func(c map[string]interface{}) {
+			c["exp"] = time.Now().Add(-2 * time.Minute).Unix()
+		}},
+		{name: "missing exp", alg: "RS256", kid: "rsa", wantErr: "expired", modify: func(c map[string]interface{}) {
+			delete(c, "exp")
+		}},
+		{name: "issuer mismatch", alg: "RS256", kid: "rsa", wantErr: "issuer", modify: func(c map[string]interface{}) {
+			c["iss"] = "https://evil.example.com"
+		}},
+		{name: "audience mismatch", alg: "RS256", kid: "rsa", wantErr: "audience", modify: func(c map[string]interface{}) {
+			c["aud"] = []string{"other"}
+		}},
+		{name: "nonce mismatch", alg: "RS256", kid: "rsa", wantErr: "nonce", modify: func(c map[string]interface{}) {
+			c["nonce"] = "replayed"
+		}},
+		{name: "missing subject", alg: "RS256", kid: "rsa", wantErr: "subject", modify: func(c map[string]interface{}) {
+			delete(c, "sub")
+		}},
+		{name: "alg does not match key", alg: "ES256", kid: "rsa", wantErr: "signature"},
+		{name: "alg none", alg: "none", kid: "rsa", wantErr: "signature"},
+		{name: "unknown kid", alg: "RS256", kid: "missing", wantErr: "unknown"},
+		{name: "encryption key", alg: "RS256", kid: "enc", wantErr: "unknown"},
+		{name: "tampered payload", alg: "RS256", kid: "rsa", wantErr: "signature", tamper: func(token string) string {
+			parts := strings.Split(token, ".")
+			claims := p.claims()
+			claims["sub"] = "admin"
+			payload, _ := json.Marshal(claims)
+			return parts[0] + "." + oidcEncoding.EncodeToString(payload) + "." + parts[2]
+		}},
+		{name: "malformed", alg: "RS256", kid: "rsa", wantErr: "malformed", tamper: func(token string) string {
+			return token[:strings.LastIndexByte(token, '.')]
+		}},
+	}
+	client := p.client()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := p.claims()
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+			token := p.sign(t, tt.alg, tt.kid, claims)
+			if tt.tamper != nil {
+				token = tt.tamper(token)
+			}
+			values, err := client.verifyIdToken(context.Background(), token, "nonce")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if values["sub"] != "user-1" {
+					t.Errorf("sub = %v", values["sub"])
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOidcExchange(t *testing.T) {
+	tests := []struct {
+		name      string
+		verifier  string
+		claims    func(p *testOidcProvider) map[string]interface{}
+		userinfo  map[string]interface{}
+		wantEmail string
+		wantErr   string
+	}{
+		{name: "email from id token", wantEmail: "a@example.com"},
+		{name: "wrong verifier", verifier: "other", wantErr: "token request"},
+		{
+			name: "email from userinfo",
+			claims: func(p *testOidcProvider) map[string]interface{} {
+				c := p.claims()
+				delete(c, "email")
+				return c
+			},
+			userinfo:  map[string]interface{}{"sub": "user-1", "email": "b@example.com", "email_verified": "true"},
+			wantEmail: "b@example.com",
+		},
+		{
+			name: "userinfo subject mismatch",
+			claims: func(p *testOidcProvider) map[string]interface{} {
+				c := p.claims()
+				delete(c, "email")
+				return c
+			},
+			userinfo: map[string]interface{}{"sub": "user-2", "email": "b@example.com"},
+			wantErr:  "subject mismatch",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestOidcProvider(t)
+			verifier, challenge, err := NewPkceVerifier()
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.challenge = challenge
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			claims := p.claims()
+			if tt.claims != nil {
+				claims = tt.claims(p)
+			}
+			p.idToken = p.sign(t, "RS256", "rsa", claims)
+			p.userinfo = tt.userinfo
+			got, err := p.client().Exchange(context.Background(), "code", verifier, "nonce")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Subject != "user-1" || got.Email != tt.wantEmail {
+				t.Errorf("claims = %+v", got)
+			}
+		})
+	}
+}
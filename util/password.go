@@ -0,0 +1,90 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"github.com/shop_management/config"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// PasswordHashConfig argon2id 参数, Memory 单位为 KiB
+type PasswordHashConfig struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	KeyLen  uint32
+	SaltLen uint32
+}
+
+// PasswordConfig 默认参数, 启动时由 SetPasswordHashConfig 按配置调整
+var PasswordConfig = &PasswordHashConfig{
+	Time:    3,
+	Memory:  64 * 1024,
+	Threads: 2,
+	KeyLen:  32,
+	SaltLen: 16,
+}
+
+// SetPasswordHashConfig 只在启动时调用, 之后不再修改
+func SetPasswordHashConfig(cfg *config.PasswordConfig) {
+	PasswordConfig = &PasswordHashConfig{
+		Time:    cfg.Argon2Time,
+		Memory:  cfg.Argon2Memory,
+		Threads: cfg.Argon2Threads,
+		KeyLen:  32,
+		SaltLen: 16,
+	}
+}
+
+// HashPassword 生成 PHC 格式的 argon2id 哈希: $argon2id$v=19$m=65536,t=3,p=2$salt$hash
+func HashPassword(password string) (string, error) {
+	cfg := PasswordConfig
+	salt := make([]byte, cfg.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, cfg.Time, cfg.Memory, cfg.Threads, cfg.KeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, cfg.Memory, cfg.Time, cfg.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// VerifyPassword 常量时间比较密码. needsRehash 为 true 表示存储的是旧的明文密码,
+// 或哈希参数与当前配置不一致, 登录成功后应重新计算哈希
+func VerifyPassword(stored string, password string) (ok bool, needsRehash bool) {
+	if !strings.HasPrefix(stored, argon2idPrefix) {
+		ok = subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return ok, ok
+	}
+	parts := strings.Split(stored, "$")
+	if len(parts) != 6 {
+		return false, false
+	}
+	var version int
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false, false
+	}
+	actual := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, actual) != 1 {
+		return false, false
+	}
+	cfg := PasswordConfig
+	return true, memory != cfg.Memory || time != cfg.Time || threads != cfg.Threads
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/shop_management/vars"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"reflect"
+)
+
+const (
+	teamIdField       = "TeamID"
+	skipTeamScopeKey  = "team_scope:skip"
+	teamScopeCallback = "team_scope"
+	teamStampCallback = "team_scope:stamp"
+)
+
+// GetTeamId 当前请求所属团队, 即团队所有者的用户id, 由 token 校验后的中间件写入上下文
+func GetTeamId(ctx *gin.Context) string {
+	return ctx.GetString(vars.TeamIdMetadataName)
+}
+
+// WithoutTeamScope 跳过团队隔离, 仅用于定时任务等需要跨团队处理数据的场景
+func WithoutTeamScope(db *gorm.DB) *gorm.DB {
+	return db.Set(skipTeamScopeKey, true)
+}
+
+// RegisterTeamScope 为含有 TeamID 字段的模型自动隔离团队数据:
+// 创建时强制写入当前团队id, 查询/更新/删除时追加 team_id 条件.
+// 上下文中没有团队id时(如登录, 定时任务)不做处理
+func RegisterTeamScope(db *gorm.DB) error {
+	callback := db.Callback()
+	if err := callback.Create().Before("gorm:create").Register(teamStampCallback, stampTeamId); err != nil {
+		return err
+	}
+	if err := callback.Query().Before("gorm:query").Register(teamScopeCallback, scopeTeamId); err != nil {
+		return err
+	}
+	if err := callback.Update().Before("gorm:update").Register(teamScopeCallback, scopeTeamId); err != nil {
+		return err
+	}
+	if err := callback.Delete().Before("gorm:delete").Register(teamScopeCallback, scopeTeamId); err != nil {
+		return err
+	}
+	return callback.Row().Before("gorm:row").Register(teamScopeCallback, scopeTeamId)
+}
+
+func stampTeamId(db *gorm.DB) {
+	field, teamId, ok := teamScopeOf(db)
+	if !ok {
+		return
+	}
+	ctx := db.Statement.Context
+	switch value := db.Statement.ReflectValue; value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			_ = field.Set(ctx, reflect.Indirect(value.Index(i)), teamId)
+		}
+	case reflect.Struct:
+		_ = field.Set(ctx, value, teamId)
+	}
+}
+
+func scopeTeamId(db *gorm.DB) {
+	field, teamId, ok := teamScopeOf(db)
+	if !ok {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: teamId},
+	}})
+}
+
+func teamScopeOf(db *gorm.DB) (*schema.Field, string, bool) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return nil, "", false
+	}
+	if skip, ok := db.Get(skipTeamScopeKey); ok && skip == true {
+		return nil, "", false
+	}
+	field := db.Statement.Schema.LookUpField(teamIdField)
+	if field == nil {
+		return nil, "", false
+	}
+	teamId := teamIdFromContext(db.Statement.Context)
+	if teamId == "" {
+		return nil, "", false
+	}
+	return field, teamId, true
+}
+
+func teamIdFromContext(ctx context.Context) string {
+	return contextString(ctx, vars.TeamIdMetadataName)
+}
@@ -9,3 +9,10 @@ func FormatTime(t time.Time) string {
 func FormatTimeNoSec(t time.Time) string {
 	return t.Format("2006-01-02 15:04")
 }
+
+func FormatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return FormatTime(*t)
+}
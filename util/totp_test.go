@@ -0,0 +1,85 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret RFC 6238 附录 B 中 SHA1 的测试密钥 "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTotpCodeRfc6238(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 附录 B 中为8位动态码, 这里取后6位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod); got != tt.want {
+			t.Errorf("totpCode(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTotp(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / totpPeriod
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantOk   bool
+		wantStep int64
+	}{
+		{name: "current step", secret: rfc6238Secret, code: "050471", wantOk: true, wantStep: step},
+		{name: "lower case secret", secret: strings.ToLower(rfc6238Secret), code: "050471", wantOk: true, wantStep: step},
+		{name: "previous step", secret: rfc6238Secret, code: rfc6238Code(t, step-1), wantOk: true, wantStep: step - 1},
+		{name: "next step", secret: rfc6238Secret, code: rfc6238Code(t, step+1), wantOk: true, wantStep: step + 1},
+		{name: "outside skew", secret: rfc6238Secret, code: rfc6238Code(t, step-2)},
+		{name: "wrong code", secret: rfc6238Secret, code: "000000"},
+		{name: "short code", secret: rfc6238Secret, code: "50471"},
+		{name: "long code", secret: rfc6238Secret, code: "0504710"},
+		{name: "invalid secret", secret: "not base32!", code: "050471"},
+	}
+	for _, tt := range tests {
+		gotStep, ok := VerifyTotp(tt.secret, tt.code, now)
+		if ok != tt.wantOk || gotStep != tt.wantStep {
+			t.Errorf("%s: VerifyTotp = (%d, %v), want (%d, %v)", tt.name, gotStep, ok, tt.wantStep, tt.wantOk)
+		}
+	}
+}
+
+func TestGenerateTotpSecret(t *testing.T) {
+	secret, err := GenerateTotpSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q decodes to %d bytes, err %v", secret, len(key), err)
+	}
+	if _, ok := VerifyTotp(secret, totpCode(key, time.Now().Unix()/totpPeriod), time.Now()); !ok {
+		t.Error("generated secret does not verify its own code")
+	}
+}
+
+func rfc6238Code(t *testing.T, step int64) string {
+	t.Helper()
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return totpCode(key, step)
+}
@@ -6,6 +6,14 @@ import (
 )
 
 var DbMetadataName = "joinner_db"
+var TeamRoleMetadataName = "team_role"
+var TeamIdMetadataName = "team_id"
+var UserIdMetadataName = "user_id"
+var ApiKeyMetadataName = "api_key"
+var RequestIdMetadataName = "request_id"
+var ClientIpMetadataName = "client_ip"
+var ImpersonatorIdMetadataName = "impersonator_id"
+var UserGrantMetadataName = "user_grant"
 var Log *zap.SugaredLogger
 var RedisClient *redis.Client
 